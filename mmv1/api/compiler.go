@@ -16,6 +16,7 @@ package api
 import (
 	"log"
 	"os"
+	"strings"
 
 	"github.com/GoogleCloudPlatform/magic-modules/mmv1/google"
 )
@@ -27,5 +28,16 @@ func Compile(yamlPath string, obj interface{}) {
 	}
 
 	yamlValidator := google.YamlValidator{}
+
+	// Report all the problems of the file at once, before templates run into
+	// one of them mid-generation.
+	if errs := yamlValidator.Validate(yamlPath, objYaml, obj); len(errs) > 0 {
+		messages := make([]string, len(errs))
+		for i, err := range errs {
+			messages[i] = err.Error()
+		}
+		log.Fatalf("Invalid YAML file %s:\n%s", yamlPath, strings.Join(messages, "\n"))
+	}
+
 	yamlValidator.Parse(objYaml, obj)
 }
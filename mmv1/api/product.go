@@ -43,11 +43,11 @@ type Product struct {
 	// The list of permission scopes available for the service
 	// For example: `https://www.googleapis.com/auth/compute`
 
-	Scopes []string
+	Scopes []string `validate:"required"`
 
 	// The API versions of this product
 
-	Versions []*product.Version
+	Versions []*product.Version `validate:"required"`
 
 	// The base URL for the service API endpoint
 	// For example: `https://www.googleapis.com/compute/v1/`
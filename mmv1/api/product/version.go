@@ -34,10 +34,10 @@ type Version struct {
 	CaiBaseUrl string `yaml:"cai_base_url"`
 
 	// attr_accessor
-	BaseUrl string `yaml:"base_url"`
+	BaseUrl string `yaml:"base_url" validate:"required"`
 
 	// attr_accessor
-	Name string
+	Name string `validate:"required"`
 }
 
 // def validate
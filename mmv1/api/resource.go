@@ -30,7 +30,7 @@ type Resource struct {
 
 	// [Required] A description of the resource that's surfaced in provider
 	// documentation.
-	Description string
+	Description string `validate:"required"`
 
 	// [Required] (Api::Resource::ReferenceLinks) Reference links provided in
 	// downstream documentation.
@@ -65,6 +65,17 @@ type Resource struct {
 	// base URL. Specific to defining the resource as a CAI asset.
 	CaiBaseUrl string `yaml:"cai_base_url"`
 
+	// [Optional] The CAI asset types the resource is converted from, when
+	// there are several (e.g. regional and global variants), or when the
+	// asset type can't be derived from the base URL. The first one is the
+	// primary asset type.
+	CaiAssetTypes []string `yaml:"cai_asset_types"`
+
+	// [Optional] (Api::Resource::CaiNestedAsset) The location of the resource
+	// inside the CAI asset of a parent resource, for resources that are not
+	// assets themselves. Used to generate cai2hcl converters.
+	CaiNestedAsset resource.CaiNestedAsset `yaml:"cai_nested_asset"`
+
 	// ====================
 	// URL / HTTP Configuration
 	// ====================
@@ -164,6 +175,11 @@ type Resource struct {
 	// services with a mix of handwritten and generated resources.
 	LegacyName string `yaml:"legacy_name"`
 
+	// [Optional] Names the Terraform resource had in previous provider
+	// versions, e.g. google_compute_old_name. Converters map them to the
+	// current name, and can emit `moved` blocks from them.
+	Aliases []string
+
 	// The Terraform resource id format used when calling //setId(...).
 	// For instance, `{{name}}` means the id will be the resource name.
 	IdFormat string `yaml:"id_format"`
@@ -185,6 +201,11 @@ type Resource struct {
 	// be included in the resource constants or come from tpgresource
 	CustomDiff []string `yaml:"custom_diff"`
 
+	// [Optional] (Array of Api::Resource::Constraint) Constraints between
+	// fields, added to the schema of the fields or checked in a generated
+	// CustomizeDiff.
+	Constraints []resource.Constraint
+
 	// Lock name for a mutex to prevent concurrent API calls for a given
 	// resource.
 	Mutex string
@@ -219,6 +240,27 @@ type Resource struct {
 	// (i.e. terraform-provider-conversion)
 	ExcludeTgc bool `yaml:"exclude_tgc"`
 
+	// If true, no cai2hcl converter is generated for the resource, e.g. for
+	// resources without CAI coverage.
+	ExcludeCai2hcl bool `yaml:"exclude_cai2hcl"`
+
+	// If true, no TGC tests are generated or registered for the resource.
+	ExcludeTgcTest bool `yaml:"exclude_tgc_test"`
+
+	// If true, the resource is generated as a terraform-plugin-framework
+	// resource instead of a plugin SDK one. Only resources with top-level
+	// primitive fields and no custom code are supported.
+	PluginFramework bool `yaml:"plugin_framework"`
+
+	// If true (default), a data source reading the resource is generated,
+	// along with its documentation. Resources with a handwritten data source
+	// of the same name set it to false, generation fails otherwise.
+	GenerateDatasource bool `yaml:"generate_datasource"`
+
+	// [Optional] (Api::Resource::ListDatasource) If set, a plural data source
+	// listing the resources through the List method of the API is generated.
+	ListDatasource *resource.ListDatasource `yaml:"list_datasource"`
+
 	// If true, skip sweeper generation for this resource
 	SkipSweeper bool `yaml:"skip_sweeper"`
 
@@ -278,6 +320,17 @@ type Resource struct {
 	// an error and returns one.
 	ReadErrorTransform string `yaml:"read_error_transform"`
 
+	// The full resource name of the resource, e.g.
+	// "//storage.googleapis.com/projects/_/buckets/{{name}}". If set, tag
+	// bindings of the provider's default_tags are created for the resource
+	// once it's created.
+	TagBindingParent string `yaml:"tag_binding_parent"`
+
+	// The location tag bindings of the resource are created in, e.g.
+	// "{{location}}", for resources bound to tags through location-specific
+	// endpoints. Tag bindings are global if unset.
+	TagBindingLocation string `yaml:"tag_binding_location"`
+
 	// If true, resources that failed creation will be marked as tainted. As a consequence
 	// these resources will be deleted and recreated on the next apply call. This pattern
 	// is preferred over deleting the resource directly in post_create_failure hooks.
@@ -306,6 +359,7 @@ func (r *Resource) UnmarshalYAML(n *yaml.Node) error {
 	r.ReadVerb = "GET"
 	r.DeleteVerb = "DELETE"
 	r.UpdateVerb = "PUT"
+	r.GenerateDatasource = true
 
 	type resourceAlias Resource
 	aliasObj := (*resourceAlias)(r)
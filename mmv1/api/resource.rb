@@ -12,7 +12,10 @@
 # limitations under the License.
 
 require 'api/object'
+require 'api/resource/cai_nested_asset'
+require 'api/resource/constraint'
 require 'api/resource/iam_policy'
+require 'api/resource/list_datasource'
 require 'api/resource/nested_query'
 require 'api/resource/reference_links'
 require 'google/string_utils'
@@ -56,6 +59,17 @@ module Api
       # base URL. Specific to defining the resource as a CAI asset.
       attr_reader :cai_base_url
 
+      # [Optional] The CAI asset types the resource is converted from, when
+      # there are several (e.g. regional and global variants), or when the
+      # asset type can't be derived from the base URL. The first one is the
+      # primary asset type.
+      attr_reader :cai_asset_types
+
+      # [Optional] (Api::Resource::CaiNestedAsset) The location of the resource
+      # inside the CAI asset of a parent resource, for resources that are not
+      # assets themselves. Used to generate cai2hcl converters.
+      attr_reader :cai_nested_asset
+
       # ====================
       # URL / HTTP Configuration
       # ====================
@@ -144,6 +158,11 @@ module Api
       # services with a mix of handwritten and generated resources.
       attr_reader :legacy_name
 
+      # [Optional] Names the Terraform resource had in previous provider
+      # versions, e.g. google_compute_old_name. Converters map them to the
+      # current name, and can emit `moved` blocks from them.
+      attr_reader :aliases
+
       # The Terraform resource id format used when calling #setId(...).
       # For instance, `{{name}}` means the id will be the resource name.
       attr_accessor :id_format
@@ -162,6 +181,11 @@ module Api
       # be included in the resource constants or come from tpgresource
       attr_reader :custom_diff
 
+      # [Optional] (Array of Api::Resource::Constraint) Constraints between
+      # fields, added to the schema of the fields or checked in a generated
+      # CustomizeDiff.
+      attr_reader :constraints
+
       # Lock name for a mutex to prevent concurrent API calls for a given
       # resource.
       attr_reader :mutex
@@ -185,9 +209,30 @@ module Api
       # (i.e. terraform-provider-conversion)
       attr_reader :exclude_tgc
 
+      # If true, no cai2hcl converter is generated for the resource, e.g. for
+      # resources without CAI coverage.
+      attr_reader :exclude_cai2hcl
+
+      # If true, no TGC tests are generated or registered for the resource.
+      attr_reader :exclude_tgc_test
+
       # If true, skip sweeper generation for this resource
       attr_reader :skip_sweeper
 
+      # If true, the resource is generated as a terraform-plugin-framework
+      # resource instead of a plugin SDK one. Only resources with top-level
+      # primitive fields and no custom code are supported.
+      attr_reader :plugin_framework
+
+      # If true (default), a data source reading the resource is generated,
+      # along with its documentation. Resources with a handwritten data source
+      # of the same name set it to false, generation fails otherwise.
+      attr_reader :generate_datasource
+
+      # [Optional] (Api::Resource::ListDatasource) If set, a plural data source
+      # listing the resources through the List method of the API is generated.
+      attr_reader :list_datasource
+
       attr_reader :timeouts
 
       # An array of function names that determine whether an error is retryable.
@@ -240,6 +285,17 @@ module Api
       # an error and returns one.
       attr_reader :read_error_transform
 
+      # The full resource name of the resource, e.g.
+      # "//storage.googleapis.com/projects/_/buckets/{{name}}". If set, tag
+      # bindings of the provider's default_tags are created for the resource
+      # once it's created.
+      attr_reader :tag_binding_parent
+
+      # The location tag bindings of the resource are created in, e.g.
+      # "{{location}}", for resources bound to tags through location-specific
+      # endpoints. Tag bindings are global if unset.
+      attr_reader :tag_binding_location
+
       # If true, resources that failed creation will be marked as tainted. As a consequence
       # these resources will be deleted and recreated on the next apply call. This pattern
       # is preferred over deleting the resource directly in post_create_failure hooks.
@@ -264,6 +320,12 @@ module Api
       check :async, type: Api::Async
       check :base_url, type: String
       check :cai_base_url, type: String, required: false
+      check :cai_asset_types, type: Array, item_type: String
+      check :cai_nested_asset, type: Api::Resource::CaiNestedAsset
+      if @cai_asset_types && @cai_nested_asset
+        raise "#{@name}: :cai_asset_types can't be set on resources with " \
+              ':cai_nested_asset, which use the asset types of their parent'
+      end
       check :create_url, type: String
       check :delete_url, type: String
       check :update_url, type: String
@@ -308,6 +370,7 @@ module Api
 
       check :filename_override, type: String
       check :legacy_name, type: String
+      check :aliases, type: Array, item_type: String, default: []
       check :id_format, type: String
       check :examples, item_type: Provider::Terraform::Examples, type: Array, default: []
       check :virtual_fields,
@@ -322,6 +385,7 @@ module Api
       check :autogen_async, type: :boolean, default: false
       check :exclude_import, type: :boolean, default: false
       check :custom_diff, type: Array, item_type: String, default: []
+      check :constraints, type: Array, item_type: Api::Resource::Constraint, default: []
       check :timeouts, type: Api::Timeouts
       check :error_retry_predicates, type: Array, item_type: String
       check :error_abort_predicates, type: Array, item_type: String
@@ -335,11 +399,23 @@ module Api
       check :supports_indirect_user_project_override, type: :boolean, default: false
       check :legacy_long_form_project, type: :boolean, default: false
       check :read_error_transform, type: String
+      check :tag_binding_parent, type: String
+      check :tag_binding_location, type: String
       check :taint_resource_on_failed_create, type: :boolean, default: false
       check :skip_sweeper, type: :boolean, default: false
+      check :exclude_cai2hcl, type: :boolean, default: false
+      check :exclude_tgc_test, type: :boolean, default: false
+      check :plugin_framework, type: :boolean, default: false
+      check :generate_datasource, type: :boolean, default: true
+      check :list_datasource, type: Api::Resource::ListDatasource
+      if @list_datasource && (@nested_query || @custom_code.decoder)
+        raise "#{@name}: list_datasource can't be generated for resources with " \
+              'nested_query or a custom decoder, items are read from the list response'
+      end
       check :deprecation_message, type: ::String
 
       validate_identity unless @identity.nil?
+      validate_plugin_framework if @plugin_framework
     end
 
     # ====================
@@ -749,5 +825,46 @@ Please refer to the field `effective_#{title}` for all of the #{title} present o
           if all_user_properties.select { |p| p.name == i }.empty?
       end
     end
+
+    PLUGIN_FRAMEWORK_UNSUPPORTED_CODE = %i[
+      extra_schema_entry encoder update_encoder decoder constants pre_create
+      post_create post_create_failure custom_create pre_read pre_update
+      post_update custom_update pre_delete post_delete custom_delete
+      custom_import post_import
+    ].freeze
+
+    # Plugin framework resources are generated from a much smaller template
+    # than SDK ones, ensure the resource doesn't rely on anything it lacks.
+    def validate_plugin_framework
+      prefix = "#{@name}: plugin_framework resources"
+      raise "#{prefix} can't be async" unless @async.nil?
+      raise "#{prefix} can't use nested_query" unless @nested_query.nil?
+      raise "#{prefix} can't have virtual_fields" unless @virtual_fields.empty?
+
+      PLUGIN_FRAMEWORK_UNSUPPORTED_CODE.each do |code|
+        raise "#{prefix} can't use custom_code.#{code}" unless @custom_code.send(code).nil?
+      end
+
+      supported_types = [
+        Api::Type::String, Api::Type::Enum, Api::Type::Boolean,
+        Api::Type::Integer, Api::Type::Double
+      ]
+      all_user_properties.each do |prop|
+        next if supported_types.any? { |t| prop.is_a?(t) }
+        next if prop.is_a?(Api::Type::Array) &&
+                prop.item_type_class == Api::Type::String
+
+        raise "#{prefix} don't support #{prop.type} property #{prop.name}"
+      end
+
+      all_user_properties.each do |prop|
+        %i[custom_expand custom_flatten diff_suppress_func state_func update_url].each do |attr|
+          raise "#{prefix} don't support #{attr} on #{prop.name}" unless prop.send(attr).nil?
+        end
+        if prop.is_a?(Api::Type::Array) && !prop.default_value.nil?
+          raise "#{prefix} don't support default_value on #{prop.name}"
+        end
+      end
+    end
   end
 end
@@ -0,0 +1,42 @@
+# Copyright 2024 Google Inc.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+require 'api/object'
+require 'google/string_utils'
+
+module Api
+  # An object available in the product
+  class Resource < Api::NamedObject
+    # Metadata for resources that have no CAI asset of their own, but are
+    # stored as a nested object (or list of objects) inside the asset of
+    # a parent resource. e.g. Router NATs inside a Router asset
+    class CaiNestedAsset < Google::YamlValidator
+      # The name of the resource in the same product whose asset contains
+      # this resource, e.g. "Router"
+      attr_reader :parent
+
+      # A list of keys to traverse in order within the parent asset data.
+      # i.e. router --> nats should be ["nats"]
+      # If the value at the end of the path is a list, each element is
+      # converted to a separate resource.
+      attr_reader :keys
+
+      def validate
+        super
+
+        check :parent, type: String, required: true
+        check :keys, type: Array, item_type: String, required: true
+      end
+    end
+  end
+end
@@ -0,0 +1,49 @@
+// Copyright 2024 Google Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+// A constraint between fields of a resource, declared once for the whole
+// group of fields instead of on each field.
+//
+// conflicts, required_with, at_least_one_of and exactly_one_of are added to
+// the schema of the fields, like the lists of the same name on properties.
+// required_if and forbidden_if depend on the value of another field, and
+// are checked in a generated CustomizeDiff.
+type Constraint struct {
+	// google.YamlValidator
+
+	// The kind of constraint, e.g. conflicts or required_if
+	Type string `validate:"required"`
+
+	// Schema paths of the constrained fields, e.g. settings.0.tier
+	Fields []string `validate:"required"`
+
+	// For required_if and forbidden_if, the schema path of the field whose
+	// value the constraint applies on, and that value.
+	WhenField string `yaml:"when_field"`
+	WhenValue string `yaml:"when_value"`
+
+	// An optional error message for required_if and forbidden_if
+	Message string
+}
+
+// def validate
+//   super
+
+//   check :type, type: String, allowed: SCHEMA_TYPES + VALUE_TYPES, required: true
+//   check :fields, type: Array, item_type: String, required: true
+//   check :when_field, type: String
+//   check :when_value, type: String
+//   check :message, type: String
+// end
@@ -0,0 +1,66 @@
+# Copyright 2024 Google Inc.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+require 'api/object'
+require 'google/string_utils'
+
+module Api
+  class Resource < Api::NamedObject
+    # A constraint between fields of a resource, declared once for the whole
+    # group of fields instead of on each field.
+    #
+    # conflicts, required_with, at_least_one_of and exactly_one_of are added to
+    # the schema of the fields, like the lists of the same name on properties.
+    # required_if and forbidden_if depend on the value of another field, and
+    # are checked in a generated CustomizeDiff.
+    class Constraint < Google::YamlValidator
+      SCHEMA_TYPES = %w[conflicts required_with at_least_one_of exactly_one_of].freeze
+      VALUE_TYPES = %w[required_if forbidden_if].freeze
+
+      # The kind of constraint, one of SCHEMA_TYPES or VALUE_TYPES
+      attr_reader :type
+
+      # Schema paths of the constrained fields, e.g. settings.0.tier
+      attr_reader :fields
+
+      # For required_if and forbidden_if, the schema path of the field whose
+      # value the constraint applies on, and that value.
+      attr_reader :when_field
+      attr_reader :when_value
+
+      # An optional error message for required_if and forbidden_if
+      attr_reader :message
+
+      def validate
+        super
+
+        check :type, type: String, allowed: SCHEMA_TYPES + VALUE_TYPES, required: true
+        check :fields, type: Array, item_type: String, required: true
+        check :when_field, type: String
+        check :when_value, type: String
+        check :message, type: String
+
+        if value_constraint?
+          raise "#{@type} constraints need a when_field and a when_value" \
+            if @when_field.nil? || @when_value.nil?
+        elsif @fields.length < 2
+          raise "#{@type} constraints need at least two fields"
+        end
+      end
+
+      def value_constraint?
+        VALUE_TYPES.include?(@type)
+      end
+    end
+  end
+end
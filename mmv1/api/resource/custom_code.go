@@ -135,6 +135,18 @@ type CustomCode struct {
 	// resource was successfully deleted. Use this if the API responds
 	// with a success HTTP code for deleted resources
 	TestCheckDestroy string `yaml:"test_check_destroy"`
+
+	// This code is run in the cai2hcl converter before the resource data
+	// of the asset is converted. `res` holds the resource data, and
+	// `asset` the asset it was read from. The Go generator renders the
+	// .go.tmpl file next to the given template, e.g. foo.go.tmpl for foo.erb.
+	PreConvert string `yaml:"pre_convert"`
+
+	// This code is run in the cai2hcl converter after the resource data
+	// of the asset is converted, to adjust `hclData`, the attributes of
+	// the converted resource block. Rendered from a .go.tmpl file by the Go
+	// generator, like PreConvert.
+	PostConvert string `yaml:"post_convert"`
 }
 
 // def validate
@@ -156,4 +168,6 @@ type CustomCode struct {
 //   check :custom_import, type: String
 //   check :post_import, type: String
 //   check :test_check_destroy, type: String
+//   check :pre_convert, type: String
+//   check :post_convert, type: String
 // end
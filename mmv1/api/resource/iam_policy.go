@@ -115,6 +115,11 @@ type IamPolicy struct {
 	// [Optional] Check to see if zone value should be replaced with GOOGLE_ZONE in iam tests
 	// Defaults to true
 	SubstituteZoneValue bool `yaml:"substitute_zone_value"`
+
+	// [Optional] Whether the changes of the IAM bindings and members of several
+	// resources of the same parent are batched, see the `iam` batching of the
+	// provider. Defaults to false
+	EnableBatching bool `yaml:"enable_batching"`
 }
 
 func (p *IamPolicy) UnmarshalYAML(n *yaml.Node) error {
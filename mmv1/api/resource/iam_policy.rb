@@ -114,6 +114,11 @@ module Api
       # Defaults to true
       attr_reader :substitute_zone_value
 
+      # [Optional] Whether the changes of the IAM bindings and members of several
+      # resources of the same parent are batched, see the `iam` batching of the
+      # provider. Defaults to false
+      attr_reader :enable_batching
+
       def validate
         super
 
@@ -143,6 +148,7 @@ module Api
         check :iam_policy_version, type: String
         check :min_version, type: String
         check :substitute_zone_value, type: :boolean, default: true
+        check :enable_batching, type: :boolean, default: false
       end
     end
   end
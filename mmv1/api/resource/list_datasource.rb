@@ -0,0 +1,39 @@
+# Copyright 2024 Google Inc.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+require 'api/object'
+require 'google/string_utils'
+
+module Api
+  class Resource < Api::NamedObject
+    # Metadata for generating a plural data source listing the resources
+    # returned by the List method of the API, e.g. google_compute_addresses.
+    # Items are read from the collection_url_key of the list response.
+    class ListDatasource < Google::YamlValidator
+      # The Terraform name of the data source, defaults to the plural of
+      # the resource name, e.g. google_pubsub_topics
+      attr_reader :name
+
+      # If true, the List method supports the `filter` query parameter and
+      # the data source has a `filter` argument.
+      attr_reader :filter
+
+      def validate
+        super
+
+        check :name, type: String
+        check :filter, type: :boolean, default: false
+      end
+    end
+  end
+end
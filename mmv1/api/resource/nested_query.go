@@ -22,7 +22,7 @@ type NestedQuery struct {
 	// A list of keys to traverse in order.
 	// i.e. backendBucket --> cdnPolicy.signedUrlKeyNames
 	// should be ["cdnPolicy", "signedUrlKeyNames"]
-	Keys []string
+	Keys []string `validate:"required"`
 
 	// If true, we expect the the nested list to be
 	// a list of IDs for the nested resource, rather
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api/product"
+	"gopkg.in/yaml.v3"
 )
 
 func TestResourceMinVersionObj(t *testing.T) {
@@ -143,3 +144,85 @@ func TestResourceNotInVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestResourceGenerateDatasourceDefault(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		yaml        string
+		expected    bool
+	}{
+		{
+			description: "generate_datasource is unset",
+			yaml:        "name: Topic\n",
+			expected:    true,
+		},
+		{
+			description: "generate_datasource is false",
+			yaml:        "name: Topic\ngenerate_datasource: false\n",
+			expected:    false,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			var r Resource
+			if err := yaml.Unmarshal([]byte(tc.yaml), &r); err != nil {
+				t.Fatalf("error unmarshalling resource: %v", err)
+			}
+			if got, want := r.GenerateDatasource, tc.expected; got != want {
+				t.Errorf("expected %v to be %v", got, want)
+			}
+		})
+	}
+}
+
+func TestResourceTimeoutsDefault(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		yaml        string
+		expected    *Timeouts
+	}{
+		{
+			description: "all timeouts are set",
+			yaml:        "name: Topic\ntimeouts:\n  insert_minutes: 60\n  update_minutes: 45\n  delete_minutes: 30\n",
+			expected: &Timeouts{
+				InsertMinutes: 60,
+				UpdateMinutes: 45,
+				DeleteMinutes: 30,
+			},
+		},
+		{
+			description: "some timeouts are set",
+			yaml:        "name: Topic\ntimeouts:\n  insert_minutes: 60\n  delete_minutes: 30\n",
+			expected: &Timeouts{
+				InsertMinutes: 60,
+				UpdateMinutes: DEFAULT_UPDATE_TIMEOUT_MINUTES,
+				DeleteMinutes: 30,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			var r Resource
+			if err := yaml.Unmarshal([]byte(tc.yaml), &r); err != nil {
+				t.Fatalf("error unmarshalling resource: %v", err)
+			}
+			if got, want := r.Timeouts, tc.expected; !reflect.DeepEqual(got, want) {
+				t.Errorf("expected %v to be %v", got, want)
+			}
+		})
+	}
+}
@@ -13,6 +13,10 @@
 
 package api
 
+import (
+	"gopkg.in/yaml.v3"
+)
+
 // Default timeout for all operation types is 20, the Terraform default (https://www.terraform.io/plugin/sdkv2/resources/retries-and-customizable-timeouts)
 // minutes. This can be overridden for each resource.
 const DEFAULT_INSERT_TIMEOUT_MINUTES = 20
@@ -41,6 +45,19 @@ func NewTimeouts() *Timeouts {
 	}
 }
 
+// Missing operation types default to the Terraform default, as for resources
+// without timeouts.
+func (t *Timeouts) UnmarshalYAML(n *yaml.Node) error {
+	t.InsertMinutes = DEFAULT_INSERT_TIMEOUT_MINUTES
+	t.UpdateMinutes = DEFAULT_UPDATE_TIMEOUT_MINUTES
+	t.DeleteMinutes = DEFAULT_DELETE_TIMEOUT_MINUTES
+
+	type timeoutsAlias Timeouts
+	aliasObj := (*timeoutsAlias)(t)
+
+	return n.Decode(&aliasObj)
+}
+
 // def validate
 //   super
 
@@ -23,6 +23,7 @@ ENV['TZ'] = 'UTC'
 
 require 'api/compiler'
 require 'openapi_generate/parser'
+require 'google/generation_manifest'
 require 'google/logger'
 require 'optparse'
 require 'parallel'
@@ -30,8 +31,10 @@ require 'pathname'
 require 'provider/terraform'
 require 'provider/terraform_kcc'
 require 'provider/terraform_oics'
+require 'provider/terraform_schema'
 require 'provider/terraform_tgc'
 require 'provider/terraform_tgc_cai2hcl'
+require 'provider/terraform_tgc_next'
 
 products_to_generate = nil
 all_products = false
@@ -45,6 +48,10 @@ types_to_generate = []
 version = 'ga'
 override_dir = nil
 openapi_generate = false
+clean_output = false
+incremental = false
+conversion_module = nil
+provider_import_path = nil
 
 ARGV << '-h' if ARGV.empty?
 Google::LOGGER.level = Logger::INFO
@@ -94,6 +101,21 @@ OptionParser.new do |opt|
   opt.on('--openapi-generate', 'Generate MMv1 YAML from openapi directory (Experimental)') do
     openapi_generate = true
   end
+  opt.on('--clean', 'Remove previously generated files that are no longer generated') do
+    clean_output = true
+  end
+  opt.on('--incremental', 'Only generate products whose YAML files, templates, handwritten files ' \
+                           'or generator code changed since the last run into the output path') do
+    incremental = true
+  end
+  opt.on('--conversion-module MODULE',
+         'Go module of the generated cai2hcl code, for forks of terraform-google-conversion') do |m|
+    conversion_module = m
+  end
+  opt.on('--provider-import-path PATH',
+         'Import path of the provider packages the generated cai2hcl code uses') do |p|
+    provider_import_path = p
+  end
 end.parse!
 # rubocop:enable Metrics/BlockLength
 
@@ -134,6 +156,15 @@ Google::LOGGER.info "Using #{version} version"
 
 allowed_classes = Google::YamlValidator.allowed_classes
 
+# Inputs are only hashed, and the manifest only written, in incremental mode,
+# so that other runs don't pay for it.
+if incremental
+  manifest = Google::GenerationManifest.current(version, force_provider || provider_name,
+                                                generate_code, generate_docs,
+                                                all_product_files, override_dir)
+  previous_manifest = manifest.read_previous(output_path)
+end
+
 # Building compute takes a long time and can't be parallelized within the product
 # so lets build it first
 all_product_files = all_product_files.sort_by { |product| product == 'products/compute' ? 0 : 1 }
@@ -244,7 +275,9 @@ products_for_version = Parallel.map(all_product_files, in_processes: 8) do |prod
       'validator' => Provider::TerraformGoogleConversion,
       'tgc' => Provider::TerraformGoogleConversion,
       'tgc_cai2hcl' => Provider::CaiToTerraformConversion,
-      'kcc' => Provider::TerraformKCC
+      'tgc_next' => Provider::TerraformGoogleConversionNext,
+      'kcc' => Provider::TerraformKCC,
+      'schema' => Provider::TerraformSchema
     }
 
     provider_class = override_providers[force_provider]
@@ -257,9 +290,26 @@ products_for_version = Parallel.map(all_product_files, in_processes: 8) do |prod
       override_providers[force_provider].new(product_api, version, start_time)
   end
 
+  unless conversion_module.nil? && provider_import_path.nil?
+    unless provider.respond_to?(:conversion_module=)
+      raise 'Options --conversion-module and --provider-import-path are not supported by the ' \
+            "#{force_provider || provider_name} provider"
+    end
+
+    provider.conversion_module = conversion_module
+    provider.provider_import_path = provider_import_path
+  end
+
   unless products_to_generate.include?(product_name)
     Google::LOGGER.info "#{product_name}: Not specified, skipping generation"
-    next { definitions: product_api, provider: provider } # rubocop:disable Style/HashSyntax
+    next { definitions: product_api, provider: provider, generated: false } # rubocop:disable Style/HashSyntax
+  end
+
+  # Unchanged products are still compiled, as the common files are compiled
+  # from every product.
+  if incremental && manifest.unchanged?(previous_manifest, product_name)
+    Google::LOGGER.info "#{product_name}: Unchanged since the last run, skipping generation"
+    next { definitions: product_api, provider: provider, generated: false } # rubocop:disable Style/HashSyntax
   end
 
   Google::LOGGER.info \
@@ -274,7 +324,7 @@ products_for_version = Parallel.map(all_product_files, in_processes: 8) do |prod
   )
 
   # we need to preserve a single provider instance to use outside of this loop.
-  { definitions: product_api, provider: provider } # rubocop:disable Style/HashSyntax
+  { definitions: product_api, provider: provider, generated: true, product_name: product_name } # rubocop:disable Style/HashSyntax
 end
 
 # remove any nil values
@@ -308,3 +358,26 @@ if generate_code
   end
 end
 # rubocop:enable Metrics/BlockLength
+
+if generate_code
+  generated_products = products_for_version.select { |p| p[:generated] }.map { |p| p[:definitions] }
+  provider&.write_changelog_draft(output_path, generated_products)
+end
+
+if clean_output && generate_code
+  unless provider.respond_to?(:clean_output_folder)
+    raise "Option --clean is not supported by the #{force_provider || provider_name} provider"
+  end
+
+  generated_products = products_for_version.select { |p| p[:generated] }.map { |p| p[:definitions] }
+  provider.clean_output_folder(output_path, generated_products, products_for_version)
+end
+
+# Products are only recorded as generated when all their types were.
+if incremental
+  generated_product_names = []
+  if types_to_generate.empty?
+    generated_product_names = products_for_version.select { |p| p[:generated] }.map { |p| p[:product_name] }
+  end
+  manifest.write(output_path, previous_manifest, generated_product_names)
+end
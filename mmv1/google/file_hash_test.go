@@ -0,0 +1,88 @@
+package google
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHashDirectories(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	write := func(name, content string) {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	hash := func() string {
+		h, err := HashDirectories(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return h
+	}
+
+	write("product.yaml", "name: Foo")
+	write("nested/Bar.yaml", "name: Bar")
+	original := hash()
+
+	if got := hash(); got != original {
+		t.Errorf("expected hash to be stable, got %q and %q", original, got)
+	}
+
+	write("nested/Bar.yaml", "name: Baz")
+	changed := hash()
+	if changed == original {
+		t.Error("expected hash to change when file contents change")
+	}
+
+	if err := os.Rename(filepath.Join(dir, "nested/Bar.yaml"), filepath.Join(dir, "nested/Baz.yaml")); err != nil {
+		t.Fatal(err)
+	}
+	if got := hash(); got == changed {
+		t.Error("expected hash to change when a file is renamed")
+	}
+
+	if _, err := HashDirectories(filepath.Join(dir, "missing")); err == nil {
+		t.Error("expected an error for a missing directory")
+	}
+}
+
+// The Ruby generator hashes its inputs the same way, see
+// spec/generation_manifest_spec.rb.
+func TestHashDirectories_relativePaths(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"products/foo/product.yaml":    "name: Foo",
+		"products/foo/nested/Bar.yaml": "name: Bar",
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	h, err := HashDirectories("products/foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "69ce42418c6a667071b1482a27b66a475b6b3b7967e5a91b70af122b37564571"; h != want {
+		t.Errorf("expected hash %q, got %q", want, h)
+	}
+}
@@ -0,0 +1,152 @@
+# Copyright 2024 Google Inc.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+require 'digest'
+require 'fileutils'
+require 'json'
+
+module Google
+  # Records content hashes of the inputs used to generate each product, so
+  # that unchanged products can be skipped in incremental mode. Same format and
+  # hashes as the manifest of the Go generator, see manifest.go.
+  class GenerationManifest
+    # Folder, relative to the output path, of the manifests recording the
+    # inputs of previous generation runs. Runs generating different files into
+    # the same output path, e.g. the ga code and the beta docs of the
+    # provider, have a manifest each, see file_name.
+    FOLDER = '.mmv1_manifests'.freeze
+
+    # Directories and files whose contents affect the output of every
+    # product: the templates and handwritten files, as well as the code of the
+    # generator itself.
+    SHARED_INPUTS = %w[
+      templates
+      third_party/terraform
+      third_party/cai2hcl
+      third_party/tgc
+      third_party/tgc_next
+      api
+      provider
+      google
+      main.go
+      manifest.go
+      compiler.rb
+      go.mod
+      go.sum
+    ].freeze
+
+    attr_reader :version, :provider, :code, :docs, :shared, :products
+
+    def initialize(version, provider, code, docs, shared, products)
+      @version = version
+      @provider = provider
+      @code = code
+      @docs = docs
+      @shared = shared
+      @products = products
+    end
+
+    # Returns the manifest describing the current inputs for the given
+    # products. Products of the override directory are hashed along with the
+    # product they override, and the rest of the override directory with the
+    # shared inputs.
+    def self.current(version, provider, code, docs, product_paths, override_dir = nil)
+      shared_inputs = SHARED_INPUTS.dup
+      shared_inputs << override_dir unless override_dir.nil?
+      products = product_paths.to_h do |p|
+        inputs = [p]
+        inputs << File.join(override_dir, p) unless override_dir.nil?
+        [p, hash_directories(*inputs.select { |dir| Dir.exist?(dir) })]
+      end
+      new(version, provider, code, docs, hash_directories(*shared_inputs), products)
+    end
+
+    # Returns a hex encoded sha256 digest of the names and contents of all
+    # regular files under the given directories, like HashDirectories in
+    # google/file_hash.go.
+    def self.hash_directories(*dirs)
+      files = dirs.flat_map do |dir|
+        raise "Cannot hash #{dir}: no such file or directory" unless File.exist?(dir)
+
+        next [dir] unless File.directory?(dir)
+
+        Dir.glob("#{dir}/**/*", File::FNM_DOTMATCH).select { |f| File.lstat(f).file? }
+      end
+
+      digest = Digest::SHA256.new
+      files.uniq.sort.each do |file|
+        # Include the name so renames are detected, and a separator so that
+        # moving bytes between adjacent files changes the digest.
+        digest << "#{file}\0"
+        digest << File.binread(file)
+        digest << "\0"
+      end
+      digest.hexdigest
+    end
+
+    # Returns the name of the manifest file of runs generating the same
+    # files, relative to the output path, e.g.
+    # ".mmv1_manifests/terraform_ga_code.json".
+    def file_name
+      name = "#{@provider}_#{@version}"
+      name += '_code' if @code
+      name += '_docs' if @docs
+      File.join(FOLDER, "#{name}.json")
+    end
+
+    # Reads the manifest of the previous run generating the same files into
+    # the output path. A missing or unreadable manifest results in an empty
+    # one, which causes every product to be regenerated.
+    def read_previous(output_path)
+      data = JSON.parse(File.read(File.join(output_path, file_name)))
+      return GenerationManifest.new(nil, nil, false, false, nil, {}) unless data['products'].is_a?(Hash)
+
+      GenerationManifest.new(data['version'], data['provider'], data['code'], data['docs'],
+                             data['shared'], data['products'])
+    rescue StandardError
+      GenerationManifest.new(nil, nil, false, false, nil, {})
+    end
+
+    # Returns true if the product was generated from the same inputs in the
+    # previous run.
+    def unchanged?(previous, product_path)
+      return false unless same_inputs?(previous)
+
+      previous.products.key?(product_path) && previous.products[product_path] == @products[product_path]
+    end
+
+    # Writes the manifest to the output path, keeping the entries of products
+    # that were not generated in this run from the previous manifest.
+    def write(output_path, previous, generated)
+      products = same_inputs?(previous) ? previous.products.dup : {}
+      generated.each do |p|
+        raise "No hash recorded for product #{p}" unless @products.key?(p)
+
+        products[p] = @products[p]
+      end
+
+      data = { version: @version, provider: @provider, code: @code, docs: @docs, shared: @shared, products: }
+      FileUtils.mkpath File.join(output_path, FOLDER)
+      File.write(File.join(output_path, file_name), JSON.pretty_generate(data))
+    end
+
+    private
+
+    # Returns true if the previous run generated the same files, with the
+    # same shared inputs.
+    def same_inputs?(previous)
+      !previous.nil? && previous.version == @version && previous.provider == @provider &&
+        previous.code == @code && previous.docs == @docs && previous.shared == @shared
+    end
+  end
+end
@@ -14,7 +14,12 @@
 package google
 
 import (
+	"fmt"
 	"log"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -31,6 +36,168 @@ func (v *YamlValidator) Parse(content []byte, obj interface{}) {
 	}
 }
 
+// A problem found in a YAML file, at the line and column of the offending node.
+type YamlError struct {
+	Path    string
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e YamlError) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %s", e.Path, e.Line, e.Column, e.Message)
+}
+
+// Validate checks YAML content against the struct obj points to, before it is
+// unmarshaled. All the unknown keys, values of the wrong kind and missing
+// required fields are reported, rather than only the first one. Fields tagged
+// with `validate:"required"` are required.
+func (v *YamlValidator) Validate(path string, content []byte, obj interface{}) []error {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return []error{fmt.Errorf("%s: %v", path, err)}
+	}
+	if len(doc.Content) == 0 {
+		return nil
+	}
+
+	var errs []error
+	validateYamlNode(path, doc.Content[0], reflect.TypeOf(obj), &errs)
+	return errs
+}
+
+func validateYamlNode(path string, n *yaml.Node, t reflect.Type, errs *[]error) {
+	if n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if n.Kind == yaml.ScalarNode && n.Tag == "!!null" {
+		return
+	}
+
+	fail := func(format string, a ...interface{}) {
+		*errs = append(*errs, YamlError{Path: path, Line: n.Line, Column: n.Column, Message: fmt.Sprintf(format, a...)})
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		return
+	case reflect.Struct:
+		if n.Kind != yaml.MappingNode {
+			fail("expected a mapping for %s, got %s", t.Name(), yamlKindName(n))
+			return
+		}
+		fields := yamlFields(t)
+		seen := make(map[string]bool)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, value := n.Content[i], n.Content[i+1]
+			field, ok := fields[key.Value]
+			if !ok {
+				*errs = append(*errs, YamlError{Path: path, Line: key.Line, Column: key.Column, Message: fmt.Sprintf("unknown key %q in %s", key.Value, t.Name())})
+				continue
+			}
+			seen[key.Value] = true
+			validateYamlNode(path, value, field.Type, errs)
+		}
+		for _, name := range sortedYamlKeys(fields) {
+			if fields[name].Tag.Get("validate") == "required" && !seen[name] {
+				fail("missing required key %q in %s", name, t.Name())
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if n.Kind != yaml.SequenceNode {
+			fail("expected a sequence, got %s", yamlKindName(n))
+			return
+		}
+		for _, item := range n.Content {
+			validateYamlNode(path, item, t.Elem(), errs)
+		}
+	case reflect.Map:
+		if n.Kind != yaml.MappingNode {
+			fail("expected a mapping, got %s", yamlKindName(n))
+			return
+		}
+		for i := 1; i < len(n.Content); i += 2 {
+			validateYamlNode(path, n.Content[i], t.Elem(), errs)
+		}
+	default:
+		if n.Kind != yaml.ScalarNode {
+			fail("expected a %s, got %s", t.Kind(), yamlKindName(n))
+			return
+		}
+		if err := validateYamlScalar(n.Value, t.Kind()); err != nil {
+			fail("%q is not a valid %s", n.Value, t.Kind())
+		}
+	}
+}
+
+func validateYamlScalar(value string, kind reflect.Kind) error {
+	var err error
+	switch kind {
+	case reflect.Bool:
+		var b bool
+		err = yaml.Unmarshal([]byte(value), &b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		_, err = strconv.ParseInt(value, 0, 64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		_, err = strconv.ParseUint(value, 0, 64)
+	case reflect.Float32, reflect.Float64:
+		_, err = strconv.ParseFloat(value, 64)
+	}
+	return err
+}
+
+// yamlFields returns the fields of a struct by YAML key, following the
+// conventions of gopkg.in/yaml.v3 for untagged and inlined fields.
+func yamlFields(t reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+
+		tag := field.Tag.Get("yaml")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if strings.Contains(opts, "inline") {
+			for k, f := range yamlFields(field.Type) {
+				fields[k] = f
+			}
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		fields[name] = field
+	}
+	return fields
+}
+
+func sortedYamlKeys(fields map[string]reflect.StructField) []string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func yamlKindName(n *yaml.Node) string {
+	switch n.Kind {
+	case yaml.MappingNode:
+		return "a mapping"
+	case yaml.SequenceNode:
+		return "a sequence"
+	default:
+		return fmt.Sprintf("%q", n.Value)
+	}
+}
+
 // func (v *YamlValidator) allowed_classes() {
 // ObjectSpace.each_object(Class).select do |klass|
 //   klass < Google::YamlValidator
@@ -0,0 +1,102 @@
+package google
+
+import (
+	"reflect"
+	"testing"
+)
+
+type validatedNested struct {
+	Keys []string `validate:"required"`
+}
+
+type validatedObject struct {
+	Name    string
+	Enabled bool              `yaml:"enabled"`
+	Count   int               `yaml:"count"`
+	Nested  *validatedNested  `yaml:"nested"`
+	Items   []validatedNested `yaml:"items"`
+	Labels  map[string]string `yaml:"labels"`
+	Value   interface{}       `yaml:"value"`
+}
+
+func TestYamlValidatorValidate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		content     string
+		expected    []string
+	}{
+		{
+			description: "valid content",
+			content: `name: foo
+enabled: true
+count: 3
+nested:
+  keys: [a]
+items:
+  - keys: [b]
+labels:
+  a: b
+value: [1, 2]
+`,
+			expected: nil,
+		},
+		{
+			description: "unknown key",
+			content: `name: foo
+unknown: bar
+`,
+			expected: []string{`object.yaml:2:1: unknown key "unknown" in validatedObject`},
+		},
+		{
+			description: "wrong scalar types",
+			content: `enabled: maybe
+count: many
+`,
+			expected: []string{
+				`object.yaml:1:10: "maybe" is not a valid bool`,
+				`object.yaml:2:8: "many" is not a valid int`,
+			},
+		},
+		{
+			description: "wrong kinds",
+			content: `name: [foo]
+items: foo
+`,
+			expected: []string{
+				`object.yaml:1:7: expected a string, got a sequence`,
+				`object.yaml:2:8: expected a sequence, got "foo"`,
+			},
+		},
+		{
+			description: "missing required key in nested objects",
+			content: `nested: {}
+items:
+  - keys: [a]
+  - {}
+`,
+			expected: []string{
+				`object.yaml:1:9: missing required key "keys" in validatedNested`,
+				`object.yaml:4:5: missing required key "keys" in validatedNested`,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			v := YamlValidator{}
+			var got []string
+			for _, err := range v.Validate("object.yaml", []byte(tc.content), &validatedObject{}) {
+				got = append(got, err.Error())
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("expected %q to be %q", got, tc.expected)
+			}
+		})
+	}
+}
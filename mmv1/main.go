@@ -7,8 +7,10 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"runtime"
 	"sort"
 	"strings"
+	"sync"
 
 	"golang.org/x/exp/slices"
 
@@ -26,6 +28,19 @@ var version = flag.String("version", "", "optional version name. If specified, t
 
 var product = flag.String("product", "", "optional product name. If specified, the resources under the specific product will be generated. Otherwise, resources under all products will be generated.")
 
+// Example usage: --provider tgc_cai2hcl
+var providerName = flag.String("provider", "", "optional provider name. If specified, a non-default provider will be used.")
+
+var workers = flag.Int("workers", runtime.NumCPU(), "optional. Number of products to generate in parallel.")
+
+var incremental = flag.Bool("incremental", false, "optional. If specified, only products whose YAML files, templates, handwritten files or generator code changed since the last run into the output path are generated.")
+
+// Example usage: --conversion-module github.com/example/terraform-google-conversion
+var conversionModule = flag.String("conversion-module", "", "optional. Go module of the generated cai2hcl code, for forks of terraform-google-conversion.")
+
+// Example usage: --provider-import-path github.com/example/terraform-provider-google/google
+var providerImportPath = flag.String("provider-import-path", "", "optional. Import path of the provider packages the generated cai2hcl code uses.")
+
 func main() {
 	flag.Parse()
 	var generateCode = true
@@ -39,6 +54,14 @@ func main() {
 		log.Fatalf("No version specified")
 	}
 
+	importPaths := provider.Cai2hclImportPaths{
+		ConversionModule:   *conversionModule,
+		ProviderImportPath: *providerImportPath,
+	}
+	if importPaths != (provider.Cai2hclImportPaths{}) && *providerName != "tgc_cai2hcl" {
+		log.Fatalf("Flags -conversion-module and -provider-import-path are not supported by the %q provider", *providerName)
+	}
+
 	var productsToGenerate []string
 	var allProducts = false
 	if product == nil || *product == "" {
@@ -71,83 +94,146 @@ func main() {
 	log.Printf("Generating MM output to '%s'", *outputPath)
 	log.Printf("Using %s version", *version)
 
+	// Inputs are only hashed, and the manifest only written, in incremental
+	// mode, so that other runs don't pay for it.
+	var previousManifest, manifest *GenerationManifest
+	if *incremental {
+		manifest, err = NewGenerationManifest(*version, *providerName, generateCode, generateDocs, allProductFiles)
+		if err != nil {
+			log.Fatalf("Cannot hash generation inputs: %v", err)
+		}
+		previousManifest = manifest.ReadPrevious(*outputPath)
+	}
+
 	// Building compute takes a long time and can't be parallelized within the product
 	// so lets build it first
-	sort.Slice(allProductFiles, func(i int, j int) bool {
-		if allProductFiles[i] == "compute" {
-			return true
-		}
-		return false
+	sort.SliceStable(allProductFiles, func(i int, j int) bool {
+		return allProductFiles[i] == "products/compute" && allProductFiles[j] != "products/compute"
 	})
 
-	for _, productName := range allProductFiles {
-		productYamlPath := path.Join(productName, "go_product.yaml")
+	workerCount := *workers
+	if workerCount < 1 {
+		workerCount = 1
+	}
 
-		// TODO Q2: uncomment the error check that if the product.yaml exists for each product
-		// after Go-converted product.yaml files are complete for all products
-		// if _, err := os.Stat(productYamlPath); errors.Is(err, os.ErrNotExist) {
-		// 	log.Fatalf("%s does not contain a product.yaml file", productName)
-		// }
+	// Products are generated into separate folders, so they can be generated
+	// independently. Results are collected by index to keep the output of the
+	// run independent of the order in which workers finish.
+	generated := make([]bool, len(allProductFiles))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workerCount; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				productName := allProductFiles[i]
+				if !slices.Contains(productsToGenerate, productName) {
+					log.Printf("%s not specified, skipping generation", productName)
+					continue
+				}
+				if *incremental && manifest.Unchanged(previousManifest, productName) {
+					log.Printf("%s: Unchanged since the last run, skipping generation", productName)
+					continue
+				}
+				generated[i] = GenerateProduct(productName, *outputPath, *version, *providerName, importPaths, generateCode, generateDocs)
+			}
+		}()
+	}
+	for i := range allProductFiles {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
 
-		// TODO Q2: product overrides
+	var generatedProducts []string
+	for i, productName := range allProductFiles {
+		if generated[i] {
+			generatedProducts = append(generatedProducts, productName)
+		}
+	}
+	// TODO Q2: copy common files
+	if generateCode && len(generatedProducts) > 0 {
+		provider.CompileCommonFiles(*providerName, *outputPath, *version, importPaths)
+	}
 
-		if _, err := os.Stat(productYamlPath); err == nil {
-			var resources []*api.Resource = make([]*api.Resource, 0)
+	if *incremental {
+		if err := manifest.Write(*outputPath, previousManifest, generatedProducts); err != nil {
+			log.Printf("Cannot write generation manifest: %v", err)
+		}
+	}
+}
 
-			productApi := &api.Product{}
-			api.Compile(productYamlPath, productApi)
+// Generates the product at the given path into the output path, returning
+// true if files were generated.
+func GenerateProduct(productName, outputPath, version, providerName string, importPaths provider.Cai2hclImportPaths, generateCode, generateDocs bool) bool {
+	productYamlPath := path.Join(productName, "go_product.yaml")
 
-			if !productApi.ExistsAtVersionOrLower(*version) {
-				log.Printf("%s does not have a '%s' version, skipping", productName, *version)
-				continue
-			}
+	// TODO Q2: uncomment the error check that if the product.yaml exists for each product
+	// after Go-converted product.yaml files are complete for all products
+	// if _, err := os.Stat(productYamlPath); errors.Is(err, os.ErrNotExist) {
+	// 	log.Fatalf("%s does not contain a product.yaml file", productName)
+	// }
 
-			resourceFiles, err := filepath.Glob(fmt.Sprintf("%s/*", productName))
-			if err != nil {
-				log.Fatalf("Cannot get resources files: %v", err)
-			}
-			for _, resourceYamlPath := range resourceFiles {
-				if filepath.Base(resourceYamlPath) == "product.yaml" || filepath.Ext(resourceYamlPath) != ".yaml" {
-					continue
-				}
+	// TODO Q2: product overrides
 
-				// Prepend "go_" to the Go yaml files' name to distinguish with the ruby yaml files
-				if filepath.Base(resourceYamlPath) == "go_product.yaml" || !strings.HasPrefix(filepath.Base(resourceYamlPath), "go_") {
-					continue
-				}
+	if _, err := os.Stat(productYamlPath); err != nil {
+		return false
+	}
 
-				resource := &api.Resource{}
-				api.Compile(resourceYamlPath, resource)
+	var resources []*api.Resource = make([]*api.Resource, 0)
 
-				resource.TargetVersionName = *version
-				resource.Properties = resource.AddLabelsRelatedFields(resource.PropertiesWithExcluded(), nil)
-				resource.SetDefault(productApi)
-				resource.Validate()
-				resources = append(resources, resource)
-			}
+	productApi := &api.Product{}
+	api.Compile(productYamlPath, productApi)
 
-			// TODO Q2: override resources
+	if !productApi.ExistsAtVersionOrLower(version) {
+		log.Printf("%s does not have a '%s' version, skipping", productName, version)
+		return false
+	}
 
-			// Sort resources by name
-			sort.Slice(resources, func(i, j int) bool {
-				return resources[i].Name < resources[j].Name
-			})
+	resourceFiles, err := filepath.Glob(fmt.Sprintf("%s/*", productName))
+	if err != nil {
+		log.Fatalf("Cannot get resources files: %v", err)
+	}
+	for _, resourceYamlPath := range resourceFiles {
+		if filepath.Base(resourceYamlPath) == "product.yaml" || filepath.Ext(resourceYamlPath) != ".yaml" {
+			continue
+		}
 
-			productApi.Objects = resources
-			productApi.Validate()
+		// Prepend "go_" to the Go yaml files' name to distinguish with the ruby yaml files
+		if filepath.Base(resourceYamlPath) == "go_product.yaml" || !strings.HasPrefix(filepath.Base(resourceYamlPath), "go_") {
+			continue
+		}
 
-			// TODO Q2: set other providers via flag
-			providerToGenerate := provider.NewTerraform(productApi, *version)
+		resource := &api.Resource{}
+		api.Compile(resourceYamlPath, resource)
 
-			if !slices.Contains(productsToGenerate, productName) {
-				log.Printf("%s not specified, skipping generation", productName)
-				continue
-			}
+		resource.TargetVersionName = version
+		resource.Properties = resource.AddLabelsRelatedFields(resource.PropertiesWithExcluded(), nil)
+		resource.SetDefault(productApi)
+		resource.Validate()
+		resources = append(resources, resource)
+	}
 
-			log.Printf("%s: Generating files", productName)
-			providerToGenerate.Generate(*outputPath, productName, generateCode, generateDocs)
-		}
+	// TODO Q2: override resources
 
-		// TODO Q2: copy common files
+	// Sort resources by name
+	sort.Slice(resources, func(i, j int) bool {
+		return resources[i].Name < resources[j].Name
+	})
+
+	productApi.Objects = resources
+	productApi.Validate()
+
+	providerToGenerate, err := provider.NewProvider(providerName, productApi, version)
+	if err != nil {
+		log.Fatalf("Cannot create provider: %v", err)
+	}
+	if conversion, ok := providerToGenerate.(*provider.CaiToTerraformConversion); ok {
+		conversion.ImportPaths = importPaths
 	}
+
+	log.Printf("%s: Generating files", productName)
+	providerToGenerate.Generate(outputPath, productName, generateCode, generateDocs)
+	return true
 }
@@ -0,0 +1,175 @@
+// Copyright 2024 Google Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/google"
+)
+
+// Folder, relative to the output path, of the manifests recording the inputs
+// of previous generation runs. Runs generating different files into the same
+// output path, e.g. the ga code and the beta docs of the provider, have a
+// manifest each, see GenerationManifest.FileName.
+const manifestFolder = ".mmv1_manifests"
+
+// Directories and files whose contents affect the output of every product:
+// the templates and handwritten files, as well as the code of the generator
+// itself.
+var sharedInputDirs = []string{
+	"templates",
+	"third_party/terraform",
+	"third_party/cai2hcl",
+	"third_party/tgc",
+	"third_party/tgc_next",
+	"api",
+	"provider",
+	"google",
+	"main.go",
+	"manifest.go",
+	"compiler.rb",
+	"go.mod",
+	"go.sum",
+}
+
+// GenerationManifest records content hashes of the inputs used to generate
+// each product, so that unchanged products can be skipped in incremental mode.
+type GenerationManifest struct {
+	Version string `json:"version"`
+
+	// Name of the provider the products were generated for, since providers
+	// generate different files into the same output path.
+	Provider string `json:"provider"`
+
+	// Whether the code and the docs of the products were generated.
+	Code bool `json:"code"`
+	Docs bool `json:"docs"`
+
+	// Hash of the templates, handwritten files and generator code shared by
+	// all products.
+	Shared string `json:"shared"`
+
+	// Hash of each product's YAML files, keyed by product path.
+	Products map[string]string `json:"products"`
+}
+
+// Returns the manifest describing the current inputs for the given products.
+func NewGenerationManifest(version, providerName string, generateCode, generateDocs bool, productPaths []string) (*GenerationManifest, error) {
+	shared, err := google.HashDirectories(sharedInputDirs...)
+	if err != nil {
+		return nil, err
+	}
+	if providerName == "" {
+		providerName = "terraform"
+	}
+	m := &GenerationManifest{
+		Version:  version,
+		Provider: providerName,
+		Code:     generateCode,
+		Docs:     generateDocs,
+		Shared:   shared,
+		Products: make(map[string]string),
+	}
+	for _, p := range productPaths {
+		h, err := google.HashDirectories(p)
+		if err != nil {
+			return nil, err
+		}
+		m.Products[p] = h
+	}
+	return m, nil
+}
+
+// Returns the name of the manifest file of runs generating the same files,
+// relative to the output path, e.g. ".mmv1_manifests/terraform_ga_code.json".
+func (m *GenerationManifest) FileName() string {
+	name := m.Provider + "_" + m.Version
+	if m.Code {
+		name += "_code"
+	}
+	if m.Docs {
+		name += "_docs"
+	}
+	return path.Join(manifestFolder, name+".json")
+}
+
+// Reads the manifest of the previous run generating the same files into the
+// output path. A missing or unreadable manifest results in an empty one, which
+// causes every product to be regenerated.
+func (m *GenerationManifest) ReadPrevious(outputPath string) *GenerationManifest {
+	previous := &GenerationManifest{}
+	data, err := os.ReadFile(path.Join(outputPath, m.FileName()))
+	if err != nil {
+		return previous
+	}
+	if err := json.Unmarshal(data, previous); err != nil || previous.Products == nil {
+		return &GenerationManifest{}
+	}
+	return previous
+}
+
+// Returns true if the product was generated from the same inputs in the
+// previous run.
+func (m *GenerationManifest) Unchanged(previous *GenerationManifest, productPath string) bool {
+	if !m.sameInputs(previous) {
+		return false
+	}
+	h, ok := previous.Products[productPath]
+	return ok && h == m.Products[productPath]
+}
+
+// Returns true if the previous run generated the same files, with the same
+// shared inputs.
+func (m *GenerationManifest) sameInputs(previous *GenerationManifest) bool {
+	return previous != nil && previous.Version == m.Version && previous.Provider == m.Provider &&
+		previous.Code == m.Code && previous.Docs == m.Docs && previous.Shared == m.Shared
+}
+
+// Writes the manifest to the output path, keeping the entries of products
+// that were not generated in this run from the previous manifest.
+func (m *GenerationManifest) Write(outputPath string, previous *GenerationManifest, generated []string) error {
+	result := &GenerationManifest{
+		Version:  m.Version,
+		Provider: m.Provider,
+		Code:     m.Code,
+		Docs:     m.Docs,
+		Shared:   m.Shared,
+		Products: make(map[string]string),
+	}
+	if m.sameInputs(previous) {
+		for p, h := range previous.Products {
+			result.Products[p] = h
+		}
+	}
+	for _, p := range generated {
+		h, ok := m.Products[p]
+		if !ok {
+			return errors.New("no hash recorded for product " + p)
+		}
+		result.Products[p] = h
+	}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(path.Join(outputPath, manifestFolder), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path.Join(outputPath, m.FileName()), data, 0644)
+}
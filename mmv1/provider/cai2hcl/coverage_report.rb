@@ -0,0 +1,100 @@
+# Copyright 2024 Google Inc.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+require 'json'
+
+module Provider
+  module Cai2hcl
+    # Builds a report of which resources have a cai2hcl converter, and how many
+    # of their Terraform fields the converter sets, to track conversion parity
+    # with the provider.
+    module CoverageReport
+      COVERAGE_REPORT_FILE = 'coverage_report.json'.freeze
+
+      def write_coverage_report(output_folder, products)
+        resources = products.flat_map do |product|
+          coverage_entries(product[:definitions])
+        end
+        resources.sort_by! { |r| [r[:product], r[:resource]] }
+
+        report = {
+          version: @target_version_name,
+          summary: %w[generated handwritten missing].to_h do |status|
+            [status, resources.count { |r| r[:status] == status }]
+          end,
+          resources:
+        }
+
+        path = File.join(output_folder, COVERAGE_REPORT_FILE)
+        Google::LOGGER.info "Writing cai2hcl coverage report to #{path}"
+        File.write(path, "#{JSON.pretty_generate(report)}\n")
+      end
+
+      private
+
+      def coverage_entries(product)
+        version = product.version_obj_or_closest(@target_version_name)
+        product.objects.reject { |o| o.exclude || o.not_in_version?(version) }.map do |object|
+          terraform_name = cai2hcl_terraform_name(object)
+          status = converter_status(object, terraform_name)
+          {
+            product: product.name.downcase,
+            resource: terraform_name,
+            asset_type: cai2hcl_asset_type(object),
+            status:,
+            fields: status == 'generated' ? field_coverage(object, version) : nil
+          }
+        end
+      end
+
+      def converter_status(object, terraform_name)
+        if handwritten_converters.include?(terraform_name)
+          'handwritten'
+        elsif object.exclude_resource || exclude_cai2hcl?(object)
+          'missing'
+        else
+          'generated'
+        end
+      end
+
+      # Counts the Terraform fields of the object, at every level of nesting,
+      # and how many of them the generated converter sets.
+      def field_coverage(object, version)
+        coverage = { covered: 0, total: 0 }
+        count_fields(object.all_user_properties, version, true, coverage,
+                     cai2hcl_name_fields(object).keys)
+        coverage
+      end
+
+      # URL parameters are parsed from the asset name when they are part of it.
+      def count_fields(properties, version, emitted, coverage, name_fields = [])
+        properties.each do |prop|
+          # Derived by the provider, so never part of a configuration.
+          next if prop.exclude || version < prop.min_version || cai2hcl_derived_labels?(prop)
+
+          prop_emitted = emitted && !prop.ignore_read &&
+                         (!prop.url_param_only || name_fields.include?(prop.name.underscore))
+          if prop.flatten_object
+            count_fields(prop.properties, version, prop_emitted, coverage)
+            next
+          end
+          next unless tf_types.include?(prop.class)
+
+          coverage[:total] += 1
+          coverage[:covered] += 1 if prop_emitted
+          count_fields(prop.nested_properties || [], version, prop_emitted, coverage)
+        end
+      end
+    end
+  end
+end
@@ -0,0 +1,77 @@
+# Copyright 2024 Google Inc.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+
+module Provider
+  module Cai2hcl
+    # Builds the field mapping shown in the generated converter docs: which
+    # asset field each Terraform attribute is read from, and the attributes the
+    # converter can't set.
+    module Documentation
+      # Returns one entry per Terraform field of the object, at every level of
+      # nesting, with the path of the asset field it is converted from and the
+      # reason the converter leaves it unset, if it does. The fields of an
+      # unset field are omitted.
+      def cai2hcl_field_docs(object)
+        version = object.__product.version_obj_or_closest(@target_version_name)
+        field_docs(object.all_user_properties, version, [], [], cai2hcl_name_fields(object).keys)
+      end
+
+      private
+
+      # name_fields are the fields parsed from the asset name at this level.
+      def field_docs(properties, version, asset_path, tf_path, name_fields)
+        properties.flat_map do |prop|
+          next [] if prop.exclude || version < prop.min_version
+
+          asset_field = asset_path + [prop.api_name]
+          if prop.flatten_object
+            next field_docs(prop.properties, version, asset_field, tf_path, name_fields)
+          end
+          next [] unless tf_types.include?(prop.class)
+
+          attribute = tf_path + [prop.name.underscore]
+          entry = {
+            asset_field: prop.url_param_only ? 'name' : asset_field.join('.'),
+            attribute: attribute.join('.'),
+            gap: field_gap(prop, name_fields),
+            note: field_note(prop, name_fields)
+          }
+          next [entry] unless entry[:gap].nil?
+
+          [entry] + field_docs(prop.nested_properties || [], version, asset_field, attribute, [])
+        end
+      end
+
+      def field_note(prop, name_fields)
+        if prop.url_param_only && name_fields.include?(prop.name.underscore)
+          'Parsed from the asset name.'
+        elsif prop.custom_flatten
+          'Converted by a custom flattener.'
+        elsif prop.is_a?(Api::Type::KeyValueLabels)
+          'Labels applied by GCP services, e.g. goog-terraform-provisioned, are left out.'
+        end
+      end
+
+      def field_gap(prop, name_fields)
+        if prop.url_param_only && !name_fields.include?(prop.name.underscore)
+          'Only part of the request URL, not stored in the asset.'
+        elsif prop.ignore_read
+          'Not returned by the API.'
+        elsif cai2hcl_derived_labels?(prop)
+          'Derived from labels by the provider.'
+        end
+      end
+    end
+  end
+end
@@ -0,0 +1,117 @@
+# Copyright 2024 Google Inc.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+module Provider
+  module Cai2hcl
+    # Local fixes to generated converters, kept in third_party/cai2hcl/overrides
+    # so that they survive regeneration. An override has the path of the
+    # converter it patches, e.g. overrides/compute/compute_forwarding_rule.go.
+    # Each top-level declaration of the override replaces the generated
+    # declaration of the same name, other declarations are appended to the
+    # converter. Imports of the override are added to the converter.
+    module Overrides
+      OVERRIDES_FOLDER = 'third_party/cai2hcl/overrides'.freeze
+
+      # Start of a top-level declaration, capturing the method receiver type
+      # and the name of the declaration.
+      DECLARATION = /\A(?:func\s+(?:\(\s*\w*\s*\*?(\w+)\s*\)\s*)?|type\s+|var\s+|const\s+)(\w+)/
+
+      def converter_override(pwd, product_name, target)
+        path = File.join(pwd, OVERRIDES_FOLDER, product_name, target)
+        File.exist?(path) ? path : nil
+      end
+
+      # Patches the generated converter at path with the override, if any.
+      def apply_converter_override(pwd, product_name, path)
+        override = converter_override(pwd, product_name, File.basename(path))
+        return if override.nil?
+
+        Google::LOGGER.info "Applying cai2hcl override #{override}"
+        File.write(path, merge_go_declarations(File.read(path), File.read(override)))
+      end
+
+      # Returns generated with the declarations and imports of override.
+      def merge_go_declarations(generated, override)
+        lines = generated.lines
+        overrides = go_declarations(override.lines)
+
+        go_declarations(lines).values.reverse_each do |decl|
+          replacement = overrides.delete(decl[:key])
+          next if replacement.nil?
+
+          lines[decl[:range]] = replacement[:lines]
+        end
+
+        lines = merge_go_imports(lines, go_imports(override.lines))
+        appended = overrides.values.flat_map { |decl| ["\n"] + decl[:lines] }
+        (lines + appended).join
+      end
+
+      private
+
+      # Returns the top-level declarations of a Go file by key, e.g.
+      # "Converter.convert" for methods, with their doc comments.
+      def go_declarations(lines)
+        declarations = {}
+        i = 0
+        while i < lines.length
+          match = DECLARATION.match(lines[i])
+          if match.nil?
+            i += 1
+            next
+          end
+
+          first = i
+          first -= 1 while first.positive? && lines[first - 1].start_with?('//')
+          last = go_declaration_end(lines, i)
+          key = [match[1], match[2]].compact.join('.')
+          declarations[key] = { key: key, range: first..last, lines: lines[first..last] }
+          i = last + 1
+        end
+        declarations
+      end
+
+      # Declarations spanning several lines are closed by a brace or
+      # parenthesis at the start of a line.
+      def go_declaration_end(lines, start)
+        return start unless lines[start].rstrip.end_with?('{', '(')
+
+        last = (start + 1...lines.length).find { |j| lines[j] =~ /\A[})]/ }
+        raise "Unterminated declaration: #{lines[start]}" if last.nil?
+
+        last
+      end
+
+      # Returns the import specs of a Go file, e.g. ["\t\"fmt\"\n"].
+      def go_imports(lines)
+        start = lines.index { |l| l.start_with?('import (') }
+        specs = if start.nil?
+                  lines.select { |l| l.start_with?('import "') }.map { |l| l.delete_prefix('import ') }
+                else
+                  finish = (start...lines.length).find { |j| lines[j].start_with?(')') }
+                  lines[start + 1...finish]
+                end
+        specs.map(&:strip).reject(&:empty?).map { |l| "\t#{l}\n" }
+      end
+
+      def merge_go_imports(lines, imports)
+        start = lines.index { |l| l.start_with?('import (') }
+        return lines if imports.empty? || start.nil?
+
+        existing = lines.map(&:strip)
+        missing = imports.reject { |i| existing.include?(i.strip) }
+        lines.dup.insert(start + 1, *missing)
+      end
+    end
+  end
+end
@@ -0,0 +1,131 @@
+# Copyright 2024 Google Inc.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+module Provider
+  module Cai2hcl
+    # Generation-time checks that the fields a converter emits line up with the
+    # Terraform schema of the resource it converts to. A field missing from the
+    # schema, or with a different type, makes the converter fail at runtime.
+    module SchemaCheck
+      # Raises an error listing every mismatch between the fields emitted by the
+      # converter of the object and the object's Terraform schema.
+      def check_converter_fields(object, pwd)
+        schema = schema_fields(object.all_user_properties)
+        errors = []
+        check_emitted_fields(object, object.gettable_properties, schema, [], errors, pwd)
+        return if errors.empty?
+
+        raise "cai2hcl converter for #{object.resource_name} does not match the " \
+              "Terraform schema of #{cai2hcl_terraform_name(object)}:\n" \
+              "#{errors.map { |e| "  - #{e}" }.join("\n")}"
+      end
+
+      private
+
+      # Returns the schema fields generated for the properties, keyed by field
+      # name, mirroring templates/terraform/schema_property.erb.
+      def schema_fields(properties)
+        properties.each_with_object({}) do |prop, fields|
+          if prop.flatten_object
+            fields.merge!(schema_fields(prop.properties))
+          elsif tf_types.include?(prop.class)
+            fields[prop.name.underscore] = {
+              type: schema_type(prop),
+              nested: schema_fields(prop.nested_properties || [])
+            }
+          end
+        end
+      end
+
+      def schema_type(prop)
+        prop.is_set ? 'schema.TypeSet' : tf_type(prop)
+      end
+
+      # Returns the type of the value produced by the default flattener of the
+      # property, mirroring templates/terraform/flatten_property_method.erb.
+      def flattened_type(prop)
+        if prop.is_a?(Api::Type::Array) && prop.item_type.is_a?(Api::Type::NestedObject)
+          prop.is_set ? 'schema.TypeSet' : 'schema.TypeList'
+        elsif prop.is_a?(Api::Type::Array) && prop.is_set
+          'schema.TypeSet'
+        else
+          tf_type(prop)
+        end
+      end
+
+      # Properties set by the converter at this level, with flattened objects
+      # collapsed into their parent.
+      def emitted_fields(properties)
+        properties.reject { |p| p.ignore_read || cai2hcl_derived_labels?(p) }.flat_map do |prop|
+          prop.flatten_object ? emitted_fields(prop.properties) : [prop]
+        end
+      end
+
+      def check_emitted_fields(object, properties, schema, path, errors, pwd)
+        seen = []
+        emitted_fields(properties).each do |prop|
+          name = prop.name.underscore
+          field_path = (path + [name]).join('.')
+
+          errors << "#{field_path} is emitted more than once" if seen.include?(name)
+          seen << name
+
+          unless tf_types.include?(prop.class)
+            errors << "#{field_path} has type #{prop.class} which has no Terraform schema type"
+            next
+          end
+
+          field = schema[name]
+          if field.nil?
+            errors << "#{field_path} is not in the Terraform schema"
+            next
+          end
+
+          if prop.custom_flatten
+            check_custom_flatten_fields(object, prop, field, field_path, errors, pwd)
+            next
+          end
+
+          if flattened_type(prop) != field[:type]
+            errors << "#{field_path} is emitted as #{flattened_type(prop)} " \
+                      "but the Terraform schema has #{field[:type]}"
+          end
+
+          nested = prop.nested_properties
+          unless nested.nil? || nested.empty?
+            check_emitted_fields(object, nested, field[:nested], path + [name], errors, pwd)
+          end
+        end
+      end
+
+      # Custom flatteners are free-form, so only the field names they set are
+      # checked against any level of the nested schema.
+      def check_custom_flatten_fields(object, prop, field, field_path, errors, pwd)
+        nested_names = all_nested_names(field[:nested])
+        return if nested_names.empty?
+
+        code = build_flatten_method(object.resource_name, prop, object, pwd)
+        keys = code.scan(/"(\w+)":\s*flatten/).flatten |
+               code.scan(/transformed\["(\w+)"\]\s*=/).flatten
+        (keys - nested_names).each do |key|
+          errors << "#{field_path}.#{key} is set by #{prop.custom_flatten} " \
+                    'but is not in the Terraform schema'
+        end
+      end
+
+      def all_nested_names(fields)
+        fields.flat_map { |name, field| [name] + all_nested_names(field[:nested]) }
+      end
+    end
+  end
+end
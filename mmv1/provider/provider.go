@@ -0,0 +1,47 @@
+// Copyright 2024 Google Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api"
+)
+
+// Provider generates the files of a downstream for a single product.
+type Provider interface {
+	Generate(outputFolder, productPath string, generateCode, generateDocs bool)
+}
+
+// Returns the provider generating the given product, matching the
+// --force option of compiler.rb. An empty name selects the default
+// Terraform provider.
+func NewProvider(name string, product *api.Product, versionName string) (Provider, error) {
+	switch name {
+	case "", "terraform":
+		return NewTerraform(product, versionName), nil
+	case "tgc_cai2hcl":
+		return NewCaiToTerraformConversion(product, versionName), nil
+	}
+	return nil, fmt.Errorf("invalid provider %q", name)
+}
+
+// Generates the files shared by all the products of the given provider, once
+// the products are generated, matching compile_common_files of compiler.rb.
+func CompileCommonFiles(name, outputFolder, versionName string, importPaths Cai2hclImportPaths) {
+	switch name {
+	case "tgc_cai2hcl":
+		CompileCai2hclCommonFiles(outputFolder, versionName, importPaths)
+	}
+}
@@ -39,6 +39,10 @@ type TemplateData struct {
 	TerraformResourceDirectory string
 	TerraformProviderModule    string
 
+	// Import paths replacing the default ones in generated files, if set,
+	// see Cai2hclImportPaths.
+	ImportPaths Cai2hclImportPaths
+
 	// TODO Q2: is this needed?
 	//     # Information about the local environment
 	//     # (which formatters are enabled, start-time)
@@ -120,6 +124,47 @@ func (td *TemplateData) GenerateTestFile(filePath string, resource api.Resource)
 	td.GenerateFile(filePath, templatePath, tmplInput, true, templates...)
 }
 
+func (td *TemplateData) GenerateCai2hclConverterFile(filePath string, input Cai2hclConverterInput) {
+	templatePath := "templates/cai2hcl/resource_converter.go.tmpl"
+	templates := []string{
+		"templates/cai2hcl/flatten_property_method.go.tmpl",
+		templatePath,
+	}
+	td.GenerateFile(filePath, templatePath, input, true, templates...)
+}
+
+func (td *TemplateData) GenerateCai2hclDocumentationFile(filePath string, input Cai2hclDocumentationInput) {
+	templatePath := "templates/cai2hcl/resource_converter.md.tmpl"
+	templates := []string{
+		templatePath,
+	}
+	td.GenerateFile(filePath, templatePath, input, false, templates...)
+}
+
+func (td *TemplateData) GenerateCai2hclServiceConverterMapFile(filePath string, input Cai2hclServiceConverterMapInput) {
+	templatePath := "templates/cai2hcl/service_converter_map.go.tmpl"
+	templates := []string{
+		templatePath,
+	}
+	td.GenerateFile(filePath, templatePath, input, true, templates...)
+}
+
+func (td *TemplateData) GenerateCai2hclServiceConverterMapTestFile(filePath string, input Cai2hclServiceConverterMapInput) {
+	templatePath := "templates/cai2hcl/service_converter_map_test.go.tmpl"
+	templates := []string{
+		templatePath,
+	}
+	td.GenerateFile(filePath, templatePath, input, true, templates...)
+}
+
+func (td *TemplateData) GenerateCai2hclConverterMapFile(filePath string, services []string) {
+	templatePath := "templates/cai2hcl/converter_map.go.tmpl"
+	templates := []string{
+		templatePath,
+	}
+	td.GenerateFile(filePath, templatePath, services, true, templates...)
+}
+
 func (td *TemplateData) GenerateFile(filePath, templatePath string, input any, goFormat bool, templates ...string) {
 	log.Printf("Generating %s", filePath)
 
@@ -140,6 +185,12 @@ func (td *TemplateData) GenerateFile(filePath, templatePath string, input any, g
 	if td.TerraformResourceDirectory != "google" {
 		sourceByte = bytes.Replace(sourceByte, []byte("github.com/hashicorp/terraform-provider-google/google"), []byte(td.TerraformProviderModule+"/"+td.TerraformResourceDirectory), -1)
 	}
+	if td.ImportPaths.ProviderImportPath != "" {
+		sourceByte = bytes.Replace(sourceByte, []byte(td.TerraformProviderModule+"/"+td.TerraformResourceDirectory), []byte(td.ImportPaths.ProviderImportPath), -1)
+	}
+	if td.ImportPaths.ConversionModule != "" {
+		sourceByte = bytes.Replace(sourceByte, []byte(cai2hclConversionModule), []byte(td.ImportPaths.ConversionModule), -1)
+	}
 
 	if goFormat {
 		sourceByte, err = format.Source(sourceByte)
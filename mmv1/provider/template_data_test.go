@@ -0,0 +1,76 @@
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api/product"
+)
+
+const importsTemplate = `package foo
+
+import (
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v5/cai2hcl/common"
+	"github.com/hashicorp/terraform-provider-google/google/tpgresource"
+)
+`
+
+func TestGenerateFileImportPaths(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	templatePath := filepath.Join(dir, "imports.go.tmpl")
+	if err := os.WriteFile(templatePath, []byte(importsTemplate), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]struct {
+		version     string
+		importPaths Cai2hclImportPaths
+		expected    []string
+	}{
+		"defaults": {
+			version: "ga",
+			expected: []string{
+				`"github.com/GoogleCloudPlatform/terraform-google-conversion/v5/cai2hcl/common"`,
+				`"github.com/hashicorp/terraform-provider-google/google/tpgresource"`,
+			},
+		},
+		"beta defaults": {
+			version: "beta",
+			expected: []string{
+				`"github.com/GoogleCloudPlatform/terraform-google-conversion/v5/cai2hcl/common"`,
+				`"github.com/hashicorp/terraform-provider-google-beta/google-beta/tpgresource"`,
+			},
+		},
+		"overridden": {
+			version: "beta",
+			importPaths: Cai2hclImportPaths{
+				ConversionModule:   "github.com/example/conversion",
+				ProviderImportPath: "github.com/example/provider/google",
+			},
+			expected: []string{
+				`"github.com/example/conversion/cai2hcl/common"`,
+				`"github.com/example/provider/google/tpgresource"`,
+			},
+		},
+	}
+	for name, tc := range cases {
+		filePath := filepath.Join(dir, strings.ReplaceAll(name, " ", "_")+".go")
+		td := NewTemplateData(dir, product.Version{Name: tc.version})
+		td.ImportPaths = tc.importPaths
+		td.GenerateFile(filePath, templatePath, nil, true, templatePath)
+
+		contents, err := os.ReadFile(filePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, e := range tc.expected {
+			if !strings.Contains(string(contents), e) {
+				t.Errorf("%s: expected %s to import %s", name, contents, e)
+			}
+		}
+	}
+}
@@ -18,10 +18,14 @@ require 'google/logger'
 require 'json'
 require 'provider/file_template'
 require 'provider/terraform/async'
+require 'provider/terraform/changelog'
 require 'provider/terraform/import'
 require 'provider/terraform/custom_code'
+require 'provider/terraform/custom_endpoints'
+require 'provider/terraform/datasources'
 require 'provider/terraform/docs'
 require 'provider/terraform/examples'
+require 'provider/terraform/framework'
 require 'provider/terraform/sub_template'
 require 'google/golang_utils'
 
@@ -30,12 +34,14 @@ module Provider
   # resources.
   class Terraform
     include Compile::Core
+    include Provider::Terraform::Changelog
+    include Provider::Terraform::CustomEndpoints
+    include Provider::Terraform::Datasources
+    include Provider::Terraform::Framework
     include Provider::Terraform::Import
     include Provider::Terraform::SubTemplate
     include Google::GolangUtils
 
-    attr_accessor :resource_count
-    attr_accessor :iam_resource_count
     attr_accessor :resources_for_version
 
     TERRAFORM_PROVIDER_GA = 'github.com/hashicorp/terraform-provider-google'.freeze
@@ -67,8 +73,6 @@ module Provider
       @start_time = start_time
       @go_format_enabled = check_goformat
 
-      @resource_count = 0
-      @iam_resource_count = 0
       @resources_for_version = []
     end
 
@@ -632,6 +636,43 @@ module Provider
       end
     end
 
+    # The property at a schema path, e.g. settings.0.tier, or nil.
+    def property_at_schema_path(schema_path, resource)
+      nested_props = resource.properties
+      prop = nil
+      schema_path.split('.0.').each do |pname|
+        prop = nested_props.find { |p| p.name == pname.camelize(:lower) } ||
+               nested_props.find { |p| p.name == schema_path }
+        return nil if prop.nil?
+
+        nested_props = prop.nested_properties || []
+      end
+      prop
+    end
+
+    # The schema path of a field of a resource-level constraint, see
+    # Api::Resource::Constraint.
+    def constraint_schema_path(schema_path, resource)
+      path = get_property_schema_path(schema_path, resource)
+      raise "#{resource.name}: constraint field #{schema_path} not found" if path.nil?
+
+      path
+    end
+
+    # The fields a resource-level constraint of the given type adds to the
+    # schema list of the same name of the property, e.g. ConflictsWith.
+    def constraint_schema_fields(property, resource, type)
+      resource.constraints.select { |c| c.type == type }.flat_map do |constraint|
+        constraint.fields.each { |f| constraint_schema_path(f, resource) }
+        field = constraint.fields.find do |f|
+          property_at_schema_path(f, resource).equal?(property)
+        end
+        next [] if field.nil?
+
+        %w[conflicts required_with].include?(type) ? constraint.fields - [field] : constraint.fields
+      end
+    end
+
     # Transforms a format string with field markers to a regex string with
     # capture groups.
     #
@@ -656,17 +697,24 @@ module Provider
       property.name.camelize(:upper)
     end
 
-    # Generates the list of resources, and gets the count of resources and iam resources
-    # dependent on the version ga, beta or private.
+    # Generates the list of resources dependent on the version ga, beta or private.
     # The resource object has the format
     # {
+    #    product:
     #    terraform_name:
     #    resource_name:
+    #    framework_resource_name:
+    #    datasource_name:
+    #    list_datasource:
     #    iam_class_name:
+    #    iam_batching:
     # }
-    # The variable resources_for_version is used to generate resources in file
-    # mmv1/third_party/terraform/provider/provider_mmv1_resources.go.erb
+    # The variable resources_for_version is used to generate resources in files
+    # mmv1/third_party/terraform/provider/provider_mmv1_resources.go.erb and
+    # mmv1/third_party/terraform/fwprovider/framework_provider_mmv1_resources.go.erb
+    # and the manifest in mmv1/third_party/terraform/generated-resources.json.erb
     def generate_resources_for_version(products, version)
+      objects = []
       products.each do |product|
         product_definition = product[:definitions]
         service = product_definition.name.downcase
@@ -676,31 +724,82 @@ module Provider
             next
           end
 
-          @resource_count += 1 unless object&.exclude_resource
+          objects << object
 
           tf_product = (object.__product.legacy_name || product_definition.name).underscore
           terraform_name = object.legacy_name || "google_#{tf_product}_#{object.name.underscore}"
 
           unless object&.exclude_resource
-            resource_name = "#{service}.Resource#{product_definition.name}#{object.name}"
+            if object.plugin_framework
+              framework_resource_name =
+                "#{service}.New#{product_definition.name}#{object.name}Resource"
+            else
+              resource_name = "#{service}.Resource#{product_definition.name}#{object.name}"
+            end
           end
 
-          iam_policy = object&.iam_policy
+          if generate_datasource?(object)
+            datasource_name = "#{service}.DataSource#{product_definition.name}#{object.name}"
+          end
 
-          @iam_resource_count += 3 unless iam_policy.nil? || iam_policy.exclude
+          if generate_list_datasource?(object)
+            list_datasource = {
+              terraform_name: list_datasource_terraform_name(object),
+              datasource_name: "#{service}.DataSource#{product_definition.name}#{object.name.plural}"
+            }
+          end
+
+          iam_policy = object&.iam_policy
 
           unless iam_policy.nil? || iam_policy.exclude ||
                  (iam_policy.min_version && iam_policy.min_version < version)
             iam_class_name = "#{service}.#{product_definition.name}#{object.name}"
+            iam_batching = iam_policy.enable_batching
           end
 
-          @resources_for_version << { terraform_name:, resource_name:, iam_class_name: }
+          @resources_for_version << { product: product_definition.name, terraform_name:,
+                                      resource_name:, framework_resource_name:,
+                                      datasource_name:, list_datasource:, iam_class_name:,
+                                      iam_batching: }
         end
       end
 
+      check_all_datasource_collisions(objects)
       @resources_for_version = @resources_for_version.compact
     end
 
+    # The generated resources and data sources of resources_for_version, with
+    # the product they come from and how they are generated: "sdk" or
+    # "framework" for resources, "sdk" or "list" for data sources, and "iam"
+    # for the IAM resources and data sources.
+    def generated_resources_manifest
+      resources = []
+      datasources = []
+      @resources_for_version.each do |object|
+        entry = ->(name, mode) { { name:, product: object[:product], mode: } }
+        resources << entry.call(object[:terraform_name], 'sdk') unless object[:resource_name].nil?
+        unless object[:framework_resource_name].nil?
+          resources << entry.call(object[:terraform_name], 'framework')
+        end
+        datasources << entry.call(object[:terraform_name], 'sdk') unless object[:datasource_name].nil?
+        unless object[:list_datasource].nil?
+          datasources << entry.call(object[:list_datasource][:terraform_name], 'list')
+        end
+        next if object[:iam_class_name].nil?
+
+        %w[binding member policy].each do |suffix|
+          resources << entry.call("#{object[:terraform_name]}_iam_#{suffix}", 'iam')
+        end
+        datasources << entry.call("#{object[:terraform_name]}_iam_policy", 'iam')
+      end
+
+      {
+        version: @target_version_name,
+        resources: resources.sort_by { |r| r[:name] },
+        datasources: datasources.sort_by { |d| d[:name] }
+      }
+    end
+
     # TODO(nelsonjr): Review all object interfaces and move to private methods
     # that should not be exposed outside the object hierarchy.
     private
@@ -746,17 +845,39 @@ module Provider
 
     # This function uses the resource.erb template to create one file
     # per resource. The resource.erb template forms the basis of a single
-    # GCP Resource on Terraform.
+    # GCP Resource on Terraform. Resources marked as plugin_framework use
+    # the framework_resource.go.erb template instead.
     def generate_resource(pwd, data, generate_code, generate_docs)
       if generate_code
         # @api.api_name is the service folder name
         product_name = @api.api_name
         target_folder = File.join(folder_name(data.version), 'services', product_name)
         FileUtils.mkpath target_folder
-        data.generate(pwd,
-                      '/templates/terraform/resource.erb',
-                      "#{target_folder}/resource_#{full_resource_name(data)}.go",
-                      self)
+        if data.object.plugin_framework
+          data.generate(pwd,
+                        '/templates/terraform/framework_resource.go.erb',
+                        "#{target_folder}/framework_resource_#{full_resource_name(data)}.go",
+                        self)
+        else
+          data.generate(pwd,
+                        '/templates/terraform/resource.erb',
+                        "#{target_folder}/resource_#{full_resource_name(data)}.go",
+                        self)
+        end
+        check_datasource_collisions(data.object)
+        if generate_datasource?(data.object)
+          data.generate(pwd,
+                        '/templates/terraform/datasource.go.erb',
+                        "#{target_folder}/data_source_#{full_resource_name(data)}.go",
+                        self)
+        end
+        if generate_list_datasource?(data.object)
+          list_name = list_datasource_terraform_name(data.object).delete_prefix('google_')
+          data.generate(pwd,
+                        '/templates/terraform/list_datasource.go.erb',
+                        "#{target_folder}/data_source_#{list_name}.go",
+                        self)
+        end
       end
 
       return unless generate_docs
@@ -770,6 +891,12 @@ module Provider
       FileUtils.mkpath target_folder
       filepath = File.join(target_folder, "#{full_resource_name(data)}.html.markdown")
       data.generate(pwd, 'templates/terraform/resource.html.markdown.erb', filepath, self)
+      return unless generate_datasource?(data.object)
+
+      datasource_folder = File.join(data.output_folder, 'website', 'docs', 'd')
+      FileUtils.mkpath datasource_folder
+      filepath = File.join(datasource_folder, "#{full_resource_name(data)}.html.markdown")
+      data.generate(pwd, 'templates/terraform/datasource.html.markdown.erb', filepath, self)
     end
 
     def generate_resource_tests(pwd, data)
@@ -792,11 +919,13 @@ module Provider
       )
     end
 
+    # Sweepers delete leaked test resources through the delete URL of the
+    # resource, so resources that need custom code before or instead of that
+    # request can't be swept. post_delete only cleans up after the request.
     def generate_resource_sweepers(pwd, data)
       return if data.object.skip_sweeper ||
                 data.object.custom_code.custom_delete ||
                 data.object.custom_code.pre_delete ||
-                data.object.custom_code.post_delete ||
                 data.object.skip_delete
 
       product_name = @api.api_name
@@ -894,6 +1023,12 @@ module Provider
       object.id_format || object.self_link_uri
     end
 
+    # The name of the resource type of an object, e.g. google_compute_address
+    def resource_terraform_name(object)
+      tf_product = (object.__product.legacy_name || object.__product.name).underscore
+      object.legacy_name || "google_#{tf_product}_#{object.name.underscore}"
+    end
+
     def full_resource_name(data)
       if data.object.legacy_name
         data.object.legacy_name.sub(/^google_/, '')
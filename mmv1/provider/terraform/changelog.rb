@@ -0,0 +1,99 @@
+# Copyright 2024 Google Inc.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+require 'fileutils'
+require 'json'
+
+module Provider
+  class Terraform
+    # Drafts release notes for the resources and fields added by generation.
+    # The generated resources and their fields are recorded in the output, and
+    # the ones missing from the record of the previous run get a release note
+    # in .changelog/, to be moved to the changelog entry of the change.
+    module Changelog
+      CHANGELOG_INVENTORY = '.changelog/.mmv1_inventory.json'.freeze
+      CHANGELOG_DRAFT = '.changelog/mmv1_generated.txt'.freeze
+
+      def write_changelog_draft(output_folder, products)
+        inventory_path = File.join(output_folder, CHANGELOG_INVENTORY)
+        previous = JSON.parse(File.read(inventory_path)) if File.exist?(inventory_path)
+        current = changelog_inventory(products)
+
+        # Without a record of the previous run everything would look new.
+        unless previous.nil?
+          notes = changelog_notes(previous, current)
+          unless notes.empty?
+            Google::LOGGER.info "Drafting #{notes.length} release notes in #{CHANGELOG_DRAFT}"
+            File.open(File.join(output_folder, CHANGELOG_DRAFT), 'a') do |f|
+              notes.each { |note| f.write("#{note}\n") }
+            end
+          end
+        end
+
+        FileUtils.mkpath File.dirname(inventory_path)
+        File.write(inventory_path, "#{JSON.pretty_generate((previous || {}).merge(current))}\n")
+      end
+
+      # The fields of every generated resource by resource, by product.
+      def changelog_inventory(products)
+        products.to_h do |product|
+          [
+            product.api_name,
+            changelog_objects(product).to_h do |object|
+              [changelog_resource_name(object), changelog_fields(object.all_user_properties)]
+            end
+          ]
+        end
+      end
+
+      def changelog_notes(previous, current)
+        current.flat_map do |product, resources|
+          known = previous[product] || {}
+          resources.flat_map do |name, fields|
+            next [changelog_new_resource_note(product, name)] unless known.key?(name)
+
+            (fields - known[name]).map { |field| changelog_new_field_note(product, name, field) }
+          end
+        end
+      end
+
+      def changelog_objects(product)
+        version = product.version_obj_or_closest(@target_version_name)
+        product.objects.reject do |object|
+          object.exclude || object.exclude_resource || object.not_in_version?(version)
+        end
+      end
+
+      def changelog_resource_name(object)
+        tf_product = (object.__product.legacy_name || object.__product.name).underscore
+        object.legacy_name || "google_#{tf_product}_#{object.name.underscore}"
+      end
+
+      # Field paths of the properties, e.g. "settings.tier"
+      def changelog_fields(properties, prefix = '')
+        properties.reject(&:exclude).flat_map do |prop|
+          path = "#{prefix}#{prop.name.underscore}"
+          [path] + changelog_fields(prop.nested_properties || [], "#{path}.")
+        end
+      end
+
+      def changelog_new_resource_note(_product, name)
+        "```release-note:new-resource\n`#{name}`\n```\n"
+      end
+
+      def changelog_new_field_note(product, name, field)
+        "```release-note:enhancement\n#{product}: added `#{field}` field to `#{name}` resource\n```\n"
+      end
+    end
+  end
+end
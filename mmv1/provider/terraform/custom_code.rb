@@ -122,6 +122,14 @@ module Provider
       # resource was successfully deleted. Use this if the API responds
       # with a success HTTP code for deleted resources
       attr_reader :test_check_destroy
+      # This code is run in the cai2hcl converter before the resource data
+      # of the asset is converted. `res` holds the resource data, and
+      # `asset` the asset it was read from.
+      attr_reader :pre_convert
+      # This code is run in the cai2hcl converter after the resource data
+      # of the asset is converted, to adjust `hclData`, the attributes of
+      # the converted resource block.
+      attr_reader :post_convert
 
       def validate
         super
@@ -142,6 +150,8 @@ module Provider
         check :custom_import, type: String
         check :post_import, type: String
         check :test_check_destroy, type: String
+        check :pre_convert, type: String
+        check :post_convert, type: String
       end
     end
   end
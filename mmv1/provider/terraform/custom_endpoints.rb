@@ -0,0 +1,88 @@
+# Copyright 2024 Google Inc.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+require 'yaml'
+
+module Provider
+  class Terraform
+    # A custom endpoint of the provider that isn't derived from a generated
+    # product, see custom_endpoints.yaml.
+    class CustomEndpoint
+      # The name of the base path, e.g. "CloudBilling" for the
+      # CloudBillingBasePath config field and CloudBillingBasePathKey.
+      attr_reader :name
+      # The provider schema key, defaults to <name>_custom_endpoint.
+      attr_reader :key
+      # The field of the framework provider model, defaults to <name>CustomEndpoint.
+      attr_reader :model_field
+      # The environment variable the endpoint defaults to, defaults to
+      # GOOGLE_<NAME>_CUSTOM_ENDPOINT.
+      attr_reader :env
+      # The default base path at GA, and at beta if different.
+      attr_reader :base_url
+      attr_reader :beta_base_url
+      # The lowest provider version the endpoint is available at.
+      attr_reader :min_version
+      # The base path is declared by the generated product of the same name,
+      # only the key is added.
+      attr_reader :product_base_path
+      # The key is declared by a generated product, only the base path is added.
+      attr_reader :product_key
+
+      def initialize(values)
+        @name = values.fetch('name')
+        @key = values['key'] || "#{@name.underscore}_custom_endpoint"
+        @model_field = values['model_field'] || "#{@name}CustomEndpoint"
+        @env = values['env'] || "GOOGLE_#{@name.underscore.upcase}_CUSTOM_ENDPOINT"
+        @base_url = values['base_url']
+        @beta_base_url = values['beta_base_url']
+        @min_version = values['min_version'] || 'ga'
+        @product_base_path = values['product_base_path'] || false
+        @product_key = values['product_key'] || false
+      end
+
+      def in_version?(version)
+        version != 'ga' || @min_version == 'ga'
+      end
+
+      def base_url_for(version)
+        version == 'ga' ? @base_url : (@beta_base_url || @base_url)
+      end
+    end
+
+    # Custom endpoints that aren't generated from the product catalog.
+    module CustomEndpoints
+      CUSTOM_ENDPOINTS_FILE = File.join(__dir__, 'custom_endpoints.yaml').freeze
+
+      # Endpoints of handwritten products, with a base path in the transport
+      # config, in the provider version being generated.
+      def handwritten_endpoints
+        custom_endpoints('handwritten')
+      end
+
+      # Endpoints of DCL-based products.
+      def dcl_endpoints
+        custom_endpoints('dcl')
+      end
+
+      private
+
+      def custom_endpoints(kind)
+        @custom_endpoints ||= YAML.safe_load(File.read(CUSTOM_ENDPOINTS_FILE))
+        @custom_endpoints.fetch(kind)
+                         .map { |e| CustomEndpoint.new(e) }
+                         .select { |e| e.in_version?(@target_version_name) }
+      end
+    end
+  end
+end
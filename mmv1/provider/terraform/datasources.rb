@@ -0,0 +1,162 @@
+# Copyright 2024 Google Inc.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+module Provider
+  class Terraform
+    # Support for data sources generated from the schema and Read of a
+    # generated resource, see templates/terraform/datasource.go.erb.
+    module Datasources
+      # Fields that default to a provider-level value when omitted.
+      PROVIDER_DEFAULT_FIELDS = %w[project region zone].freeze
+
+      THIRD_PARTY_FOLDER = File.join(__dir__, '..', '..', 'third_party', 'terraform').freeze
+
+      # Whether a data source is generated for the resource. Resources with a
+      # handwritten data source set generate_datasource to false, see
+      # check_datasource_collisions. Resources that cannot be read have none.
+      def generate_datasource?(object)
+        return false unless object.generate_datasource
+        return false if object.exclude_resource || object.plugin_framework || object.skip_read
+
+        # All the fields identifying the resource must be in its schema, to
+        # be set in the configuration of the data source.
+        fields = object.all_user_properties.map { |p| p.name.underscore }
+        fields << 'project' if object.project?
+        (datasource_required_fields(object) + datasource_optional_fields(object)).all? do |f|
+          fields.include?(f)
+        end
+      end
+
+      # The fields a data source is looked up by.
+      def datasource_required_fields(object)
+        extract_identifiers(id_format(object)).uniq - PROVIDER_DEFAULT_FIELDS
+      end
+
+      # The lookup fields that default to the provider configuration.
+      def datasource_optional_fields(object)
+        extract_identifiers(id_format(object)).uniq & PROVIDER_DEFAULT_FIELDS
+      end
+
+      # Whether a plural data source listing the resources is generated.
+      def generate_list_datasource?(object)
+        return false if object.list_datasource.nil?
+
+        !object.exclude_resource && !object.plugin_framework
+      end
+
+      def list_datasource_terraform_name(object)
+        object.list_datasource.name || resource_terraform_name(object).plural
+      end
+
+      # The arguments of a list data source are the parameters of the
+      # collection URL. Parameters with a provider-level default are optional.
+      def list_datasource_fields(object)
+        extract_identifiers(object.collection_uri).uniq
+      end
+
+      # The names of the data sources generated for the resource.
+      def generated_datasource_names(object)
+        names = []
+        names << resource_terraform_name(object) if generate_datasource?(object)
+        names << list_datasource_terraform_name(object) if generate_list_datasource?(object)
+        names
+      end
+
+      # The Go functions declared by the data sources generated for the
+      # resource, in the package of its service.
+      def generated_datasource_functions(object)
+        functions = []
+        if generate_datasource?(object)
+          functions += %W[DataSource#{object.resource_name} dataSource#{object.resource_name}Read]
+        end
+        if generate_list_datasource?(object)
+          plural = object.resource_name.plural
+          functions += %W[DataSource#{plural} dataSource#{plural}Read flatten#{plural}Item]
+        end
+        functions
+      end
+
+      # Fails generation if a data source generated for the resource collides
+      # with a handwritten one: a data source of the same name registered in
+      # the SDK or plugin framework provider, or its documentation page, or a
+      # function of the same name in the handwritten files of the service.
+      # Generated data sources never silently replace or get replaced by
+      # handwritten ones.
+      def check_datasource_collisions(object)
+        generated_datasource_names(object).each do |name|
+          doc = File.join(THIRD_PARTY_FOLDER, 'website', 'docs', 'd',
+                          "#{name.delete_prefix('google_')}.html.markdown")
+          next unless handwritten_datasources.include?(name) || File.exist?(doc)
+
+          raise "#{object.name}: generated data source #{name} collides with the handwritten " \
+                "one, set generate_datasource: false or rename the list data source"
+        end
+
+        functions = generated_datasource_functions(object)
+        return if functions.empty?
+
+        service_folder = File.join(THIRD_PARTY_FOLDER, 'services', object.__product.api_name)
+        Dir[File.join(service_folder, '*.go*')].each do |file|
+          content = File.read(file)
+          functions.each do |function|
+            next unless content.include?("func #{function}(")
+
+            raise "#{object.name}: generated data source function #{function} is already " \
+                  "declared in #{File.basename(file)}, set generate_datasource: false"
+          end
+        end
+      end
+
+      # Fails generation if two resources generate data sources of the same
+      # name, or if a generated data source collides with a handwritten one.
+      def check_all_datasource_collisions(objects)
+        seen = {}
+        objects.each do |object|
+          check_datasource_collisions(object)
+          generated_datasource_names(object).each do |name|
+            if seen.key?(name)
+              raise "#{object.name}: generated data source #{name} is also generated for " \
+                    "#{seen[name]}"
+            end
+
+            seen[name] = object.name
+          end
+        end
+      end
+
+      # The names of the data sources that are implemented by hand: the keys
+      # between the handwritten datasources markers of the SDK provider, and
+      # the type names of the plugin framework data sources. Entries that
+      # can't be read fail generation rather than being skipped.
+      def handwritten_datasources
+        @handwritten_datasources ||= begin
+          registry = File.read(File.join(THIRD_PARTY_FOLDER, 'provider', 'provider_mmv1_resources.go.erb'))
+          block = registry[/START handwritten datasources #+$(.*?)^[^\n]*END handwritten datasources/m, 1]
+          raise 'handwritten datasources markers not found in provider_mmv1_resources.go.erb' if block.nil?
+
+          names = block.lines.map(&:strip).reject { |l| l.empty? || l.start_with?('<%', '//') }.map do |line|
+            line[/\A"(\w+)":/, 1] || raise("Cannot read handwritten datasource registration: #{line}")
+          end
+
+          Dir[File.join(THIRD_PARTY_FOLDER, 'services', '**', 'data_source_*.go*')].each do |file|
+            File.read(file).scan(/resp\.TypeName = req\.ProviderTypeName \+ "(_\w+)"/).flatten.each do |suffix|
+              names << "google#{suffix}"
+            end
+          end
+
+          names.uniq
+        end
+      end
+    end
+  end
+end
@@ -0,0 +1,97 @@
+# Copyright 2024 Google Inc.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+module Provider
+  class Terraform
+    # Helpers for resources generated as terraform-plugin-framework resources,
+    # see templates/terraform/framework_resource.go.erb.
+    module Framework
+      # The framework schema attribute of a property, e.g. schema.StringAttribute
+      def framework_attribute_type(property)
+        if property.is_a?(Api::Type::Boolean)
+          'schema.BoolAttribute'
+        elsif property.is_a?(Api::Type::Integer)
+          'schema.Int64Attribute'
+        elsif property.is_a?(Api::Type::Double)
+          'schema.Float64Attribute'
+        elsif property.is_a?(Api::Type::Array)
+          'schema.ListAttribute'
+        else
+          'schema.StringAttribute'
+        end
+      end
+
+      # The type of the model field of a property, e.g. types.String
+      def framework_model_type(property)
+        framework_attribute_type(property).sub('schema.', 'types.').delete_suffix('Attribute')
+      end
+
+      # The plan modifier package of a property, e.g. stringplanmodifier
+      def framework_plan_modifier(property)
+        "#{framework_model_type(property).delete_prefix('types.').downcase}planmodifier"
+      end
+
+      # The URL parameters of the resource, which must all be fields of its model.
+      def framework_url_params(object)
+        urls = [object.self_link_uri, object.create_uri, object.delete_uri]
+        urls << object.update_url unless object.update_url.nil?
+        params = urls.flat_map { |u| extract_identifiers(u) }.uniq
+        missing = params - ['project'] - object.all_user_properties.map { |p| p.name.underscore }
+        unless missing.empty?
+          raise "#{object.name}: plugin_framework URL parameters #{missing.join(', ')} " \
+                'are not properties of the resource'
+        end
+
+        object.all_user_properties.each do |prop|
+          next unless params.include?(prop.name.underscore)
+          next if prop.is_a?(Api::Type::String) || prop.is_a?(Api::Type::Enum)
+
+          raise "#{object.name}: plugin_framework URL parameter #{prop.name} must be a string"
+        end
+
+        params
+      end
+
+      # The name of the resource type without the provider prefix, e.g. pubsub_topic
+      def framework_type_name(object)
+        return object.legacy_name.delete_prefix('google_') if object.legacy_name
+
+        product_name = (object.__product.legacy_name || object.__product.name).underscore
+        "#{product_name}_#{object.name.underscore}"
+      end
+
+      # The package and function of the default of a property,
+      # e.g. stringdefault.StaticString
+      def framework_default(property)
+        kind = framework_model_type(property).delete_prefix('types.')
+        "#{kind.downcase}default.Static#{kind}"
+      end
+
+      def framework_has_project?(object)
+        object.self_link_uri.include?('{{project}}')
+      end
+
+      # Whether the project field is added by the provider rather than
+      # declared by the resource.
+      def framework_provider_project?(object)
+        framework_has_project?(object) &&
+          object.all_user_properties.none? { |p| p.name.underscore == 'project' }
+      end
+
+      # Import formats as Go regexes, from the most to the least specific.
+      def framework_import_regexes(object)
+        import_id_formats_from_resource(object).map { |f| "^#{format2regex(f)}$" }
+      end
+    end
+  end
+end
@@ -76,6 +76,22 @@ module Provider
         # followed by number of variables (`{{`) to make `{{name}}` appear last.
         id_formats.uniq.reject(&:empty?).sort_by { |i| [i.count('/'), i.count('{{')] }.reverse
       end
+
+      # Returns the import id formats of a resource that generated tests
+      # import it with, in addition to its id. The formats are built from the
+      # state of the resource, so formats using fields that aren't in the
+      # state are left out.
+      def import_test_formats(resource)
+        fields = resource.all_user_properties.map { |p| p.name.underscore }
+        fields << 'project' if resource.project?
+        id = id_format(resource).gsub('{{%', '{{')
+
+        import_id_formats_from_resource(resource)
+          .map { |f| f.gsub('{{%', '{{') }
+          .reject { |f| f == id }
+          .select { |f| f.scan(/{{([[:word:]]+)}}/).flatten.all? { |v| fields.include?(v) } }
+          .uniq
+      end
     end
   end
 end
@@ -125,6 +125,8 @@ module Provider
 
     def copy_common_files(output_folder, generate_code, generate_docs) end
 
+    def write_changelog_draft(output_folder, products) end
+
     # Most resources' idTemplate is their longest import ID format, i.e.
     # the first item in the result of import_id_formats_from_resource().
     # However, this method can't properly generate the import ID when the
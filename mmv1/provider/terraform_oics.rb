@@ -80,6 +80,8 @@ module Provider
 
     def copy_common_files(output_folder, generate_code, generate_docs) end
 
+    def write_changelog_draft(output_folder, products) end
+
     def generate_iam_policy(pwd, data, generate_code, generate_docs) end
   end
 end
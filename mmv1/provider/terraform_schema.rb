@@ -0,0 +1,123 @@
+# Copyright 2024 Google Inc.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+require 'provider/terraform'
+require 'provider/terraform_tgc_cai2hcl'
+require 'fileutils'
+require 'json'
+
+module Provider
+  # Emits a machine-readable JSON descriptor of every product, listing the
+  # fields, types, CAI asset types and import formats of its resources, for
+  # tooling outside of the generated providers, e.g. policy engines.
+  class TerraformSchema < Provider::Terraform
+    def initialize(api, version_name, start_time)
+      super(api, version_name, start_time)
+
+      @cai2hcl = Provider::CaiToTerraformConversion.new(api, version_name, start_time)
+      @schema_resources = []
+    end
+
+    def generating_hashicorp_repo?
+      # This code is not used when generating TPG/TPGB
+      false
+    end
+
+    def generate(output_folder, types, _product_path, _dump_yaml, generate_code, generate_docs)
+      @base_url = @version.base_url
+      generate_objects(output_folder, types, generate_code, generate_docs)
+      return unless generate_code
+
+      target_folder = File.join(output_folder, 'schemas')
+      FileUtils.mkpath target_folder
+      File.write(File.join(target_folder, "#{@api.api_name}.json"),
+                 "#{JSON.pretty_generate(product_schema)}\n")
+    end
+
+    def generate_resource(_pwd, data, generate_code, _generate_docs)
+      @schema_resources << resource_schema(data.object) if generate_code
+    end
+
+    def product_schema
+      {
+        name: @api.name,
+        display_name: @api.display_name,
+        version: @target_version_name,
+        base_url: @version.base_url,
+        resources: @schema_resources.sort_by { |r| r[:name] }
+      }
+    end
+
+    def resource_schema(object)
+      {
+        name: object.name,
+        terraform_name: @cai2hcl.cai2hcl_terraform_name(object),
+        self_link: object.self_link_uri,
+        id_format: id_format(object),
+        import_formats: object.exclude_import ? [] : import_id_formats_from_resource(object),
+        asset_types: schema_asset_types(object),
+        fields: field_schemas(object.all_user_properties, object)
+      }
+    end
+
+    # Resources excluded from TGC aren't converted from any asset.
+    def schema_asset_types(object)
+      return [] if object.exclude_tgc && object.cai_nested_asset.nil?
+
+      @cai2hcl.cai2hcl_asset_types(object)
+    end
+
+    def field_schemas(properties, object)
+      properties.map do |prop|
+        field = {
+          name: prop.name.underscore,
+          api_name: prop.api_name,
+          type: schema_type_name(prop),
+          required: prop.required ? true : false,
+          output: prop.output ? true : false,
+          immutable: force_new?(prop, object),
+          sensitive: prop.sensitive ? true : false
+        }
+        field[:item_type] = schema_item_type_name(prop) if prop.is_a?(Api::Type::Array)
+        field[:values] = prop.values.map(&:to_s) if prop.is_a?(Api::Type::Enum)
+        nested = prop.nested_properties || []
+        field[:fields] = field_schemas(nested, object) unless nested.empty?
+        field
+      end
+    end
+
+    # The name of the MMv1 type of a property, e.g. NestedObject
+    def schema_type_name(prop)
+      prop.class.name.split('::').last
+    end
+
+    # Array items are either a type, e.g. NestedObject, or a type name,
+    # e.g. Api::Type::String
+    def schema_item_type_name(prop)
+      item = prop.item_type
+      item.is_a?(Api::Type) ? schema_type_name(item) : item.to_s.split('::').last
+    end
+
+    def generate_resource_tests(pwd, data) end
+
+    def generate_resource_sweepers(pwd, data) end
+
+    def generate_iam_policy(pwd, data, generate_code, generate_docs) end
+
+    def compile_common_files(output_folder, products, _common_compile_file) end
+
+    def copy_common_files(output_folder, generate_code, generate_docs) end
+
+    def write_changelog_draft(output_folder, products) end
+  end
+end
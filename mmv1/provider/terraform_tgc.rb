@@ -96,6 +96,11 @@ module Provider
       files.sort
     end
 
+    # Location of the test data relative to the generated test sources.
+    def test_data_folder
+      '../testdata/templates'
+    end
+
     def retrieve_full_list_of_test_files_with_location
       files = retrieve_full_list_of_test_files
       files.map do |file|
@@ -313,6 +318,7 @@ module Provider
       product_whitelist = []
 
       return unless product_whitelist.include?(data.product.name.downcase)
+      return if data.object.exclude_tgc_test
       return if data.object.examples
                     .reject(&:skip_test)
                     .reject do |e|
@@ -367,6 +373,8 @@ module Provider
 
     def generate_resource_sweepers(pwd, data) end
 
+    def write_changelog_draft(output_folder, products) end
+
     def replace_import_path(output_folder, target)
       # Replace import paths to reference the resources dir instead of the google provider
       data = File.read("#{output_folder}/#{target}")
@@ -0,0 +1,634 @@
+// Copyright 2024 Google Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api/product"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/google"
+)
+
+const cai2hclHandwrittenConvertersFile = "provider/cai2hcl/handwritten_converters.yaml"
+
+// The Go module of the generated code, as imported by the templates.
+const cai2hclConversionModule = "github.com/GoogleCloudPlatform/terraform-google-conversion/v5"
+
+// Import paths of the generated code, for forks of terraform-google-conversion
+// or of the provider. Empty paths keep the defaults, see the
+// -conversion-module and -provider-import-path flags.
+type Cai2hclImportPaths struct {
+	// The Go module of the generated code, replacing
+	// github.com/GoogleCloudPlatform/terraform-google-conversion/v5.
+	ConversionModule string
+
+	// The import path of the provider packages used by the generated code,
+	// replacing the one of the generated version, e.g.
+	// github.com/hashicorp/terraform-provider-google/google.
+	ProviderImportPath string
+}
+
+// Code generator for a library converting GCP CAI objects to Terraform state.
+// Go port of terraform_tgc_cai2hcl.rb.
+type CaiToTerraformConversion struct {
+	Terraform
+
+	// Handwritten converters by service, see handwritten_converters.yaml.
+	HandwrittenConvertersByService map[string][]Cai2hclHandwrittenConverter
+
+	ImportPaths Cai2hclImportPaths
+}
+
+// A handwritten converter in third_party/cai2hcl, see handwritten_converters.yaml.
+type Cai2hclHandwrittenConverter struct {
+	// The Terraform resource the converter produces.
+	Resource string
+
+	// The constructor of the converter in the service package.
+	Converter string
+
+	// The constants of the asset types the converter handles.
+	AssetTypes []string `yaml:"asset_types"`
+}
+
+func NewCaiToTerraformConversion(product *api.Product, versionName string) *CaiToTerraformConversion {
+	handwritten, err := ReadCai2hclHandwrittenConverters(cai2hclHandwrittenConvertersFile)
+	if err != nil {
+		log.Fatalf("Cannot read handwritten converters: %v", err)
+	}
+	return &CaiToTerraformConversion{
+		Terraform:                      *NewTerraform(product, versionName),
+		HandwrittenConvertersByService: handwritten,
+	}
+}
+
+func ReadCai2hclHandwrittenConverters(filePath string) (map[string][]Cai2hclHandwrittenConverter, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	converters := make(map[string][]Cai2hclHandwrittenConverter)
+	if err := yaml.Unmarshal(content, &converters); err != nil {
+		return nil, fmt.Errorf("%s: %v", filePath, err)
+	}
+	return converters, nil
+}
+
+func (c *CaiToTerraformConversion) Generate(outputFolder, productPath string, generateCode, generateDocs bool) {
+	if err := os.MkdirAll(outputFolder, os.ModePerm); err != nil {
+		log.Println(fmt.Errorf("error creating output directory %v: %v", outputFolder, err))
+	}
+
+	for _, object := range c.Product.Objects {
+		c.GenerateObject(*object, outputFolder, generateCode, generateDocs)
+	}
+
+	if generateCode {
+		c.GenerateServiceConverterMap(outputFolder)
+	}
+}
+
+func (c *CaiToTerraformConversion) GenerateObject(object api.Resource, outputFolder string, generateCode, generateDocs bool) {
+	if object.Exclude || object.ExcludeResource {
+		return
+	}
+	if object.NotInVersion(&c.Version) {
+		log.Printf("Excluding %s per API version", object.Name)
+		return
+	}
+	if ExcludeCai2hcl(object) {
+		log.Printf("Skipping fine-grained resource %s", object.Name)
+		return
+	}
+
+	templateData := NewTemplateData(outputFolder, c.Version)
+	templateData.ImportPaths = c.ImportPaths
+	productName := strings.ToLower(c.Product.Name)
+	fileName := fmt.Sprintf("%s_%s", productName, google.Underscore(object.Name))
+	if generateCode {
+		log.Printf("Generating %s converter", object.Name)
+		input, err := c.converterInput(object)
+		if err != nil {
+			log.Fatalf("Cannot generate %s converter: %v", object.Name, err)
+		}
+
+		targetFolder := path.Join(outputFolder, "services", productName)
+		if err := os.MkdirAll(targetFolder, os.ModePerm); err != nil {
+			log.Println(fmt.Errorf("error creating parent directory %v: %v", targetFolder, err))
+		}
+		targetFilePath := path.Join(targetFolder, fileName+".go")
+		templateData.GenerateCai2hclConverterFile(targetFilePath, input)
+		if err := ApplyCai2hclConverterOverride(productName, targetFilePath); err != nil {
+			log.Fatalf("Cannot apply the override of the %s converter: %v", object.Name, err)
+		}
+	}
+
+	// Docs are only generated for converters that aren't replaced by a
+	// handwritten one, as they describe the generated conversion.
+	if generateDocs && !c.Cai2hclHandwritten(object) {
+		input, err := c.documentationInput(object)
+		if err != nil {
+			log.Fatalf("Cannot generate %s converter documentation: %v", object.Name, err)
+		}
+
+		targetFolder := path.Join(outputFolder, "docs", productName)
+		if err := os.MkdirAll(targetFolder, os.ModePerm); err != nil {
+			log.Println(fmt.Errorf("error creating parent directory %v: %v", targetFolder, err))
+		}
+		templateData.GenerateCai2hclDocumentationFile(path.Join(targetFolder, fileName+".md"), input)
+	}
+}
+
+// Generates the converter map of the service package of the product, and
+// its test. It registers the generated converters along with the
+// handwritten converters of the service.
+func (c *CaiToTerraformConversion) GenerateServiceConverterMap(outputFolder string) {
+	input := c.serviceConverterMapInput()
+	if len(input.Converters) == 0 && len(input.Handwritten) == 0 {
+		return
+	}
+
+	targetFolder := path.Join(outputFolder, "services", input.Package)
+	if err := os.MkdirAll(targetFolder, os.ModePerm); err != nil {
+		log.Println(fmt.Errorf("error creating parent directory %v: %v", targetFolder, err))
+	}
+	templateData := NewTemplateData(outputFolder, c.Version)
+	templateData.ImportPaths = c.ImportPaths
+	templateData.GenerateCai2hclServiceConverterMapFile(path.Join(targetFolder, "converter_map.go"), input)
+	templateData.GenerateCai2hclServiceConverterMapTestFile(path.Join(targetFolder, "converter_map_test.go"), input)
+}
+
+// Generates the converter map of the library, registering the converter
+// maps of the service packages in the output folder. Unlike compiler.rb,
+// which lists the services of all the products, the Go generator only
+// generates the products with Go definitions, so the services are the ones
+// generated so far.
+func CompileCai2hclCommonFiles(outputFolder, versionName string, importPaths Cai2hclImportPaths) {
+	maps, err := filepath.Glob(path.Join(outputFolder, "services", "*", "converter_map.go"))
+	if err != nil {
+		log.Fatalf("Cannot list the cai2hcl services: %v", err)
+	}
+	var services []string
+	for _, m := range maps {
+		services = append(services, filepath.Base(filepath.Dir(m)))
+	}
+
+	log.Printf("Compiling cai2hcl common files.")
+	templateData := NewTemplateData(outputFolder, product.Version{Name: versionName})
+	templateData.ImportPaths = importPaths
+	templateData.GenerateCai2hclConverterMapFile(path.Join(outputFolder, "converter_map.go"), services)
+}
+
+// Whether the Terraform resource of the object is converted by a handwritten
+// converter instead of the generated one.
+func (c *CaiToTerraformConversion) Cai2hclHandwritten(object api.Resource) bool {
+	for _, converters := range c.HandwrittenConvertersByService {
+		for _, converter := range converters {
+			if converter.Resource == object.TerraformName() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Fine-grained resources are excluded from TGC as they have no asset of
+// their own, but can still be converted if they declare where they are
+// nested inside the asset of their parent.
+func ExcludeCai2hcl(object api.Resource) bool {
+	if object.ExcludeCai2hcl {
+		return true
+	}
+	return object.ExcludeTgc && object.CaiNestedAsset.Parent == ""
+}
+
+// The resource whose asset a nested resource is read from.
+func (c *CaiToTerraformConversion) Cai2hclParent(object api.Resource) (*api.Resource, error) {
+	parentName := object.CaiNestedAsset.Parent
+	for _, o := range c.Product.Objects {
+		if o.Name == parentName {
+			return o, nil
+		}
+	}
+	return nil, fmt.Errorf("%s: cai_nested_asset parent %s not found", object.Name, parentName)
+}
+
+// The primary CAI asset type of a resource, e.g. compute.googleapis.com/ForwardingRule
+func (c *CaiToTerraformConversion) Cai2hclAssetType(object api.Resource) (string, error) {
+	assetTypes, err := c.Cai2hclAssetTypes(object)
+	if err != nil {
+		return "", err
+	}
+	return assetTypes[0], nil
+}
+
+// All the CAI asset types a resource is converted from.
+func (c *CaiToTerraformConversion) Cai2hclAssetTypes(object api.Resource) ([]string, error) {
+	if object.CaiNestedAsset.Parent != "" {
+		parent, err := c.Cai2hclParent(object)
+		if err != nil {
+			return nil, err
+		}
+		return c.Cai2hclAssetTypes(*parent)
+	}
+	if len(object.CaiAssetTypes) > 0 {
+		return object.CaiAssetTypes, nil
+	}
+
+	baseUrl := c.Version.CaiBaseUrl
+	if baseUrl == "" {
+		baseUrl = c.Version.BaseUrl
+	}
+	_, host, found := strings.Cut(baseUrl, "://")
+	if !found {
+		return nil, fmt.Errorf("%s: cannot derive the CAI asset type from base url %q", object.Name, baseUrl)
+	}
+	backendName, _, _ := strings.Cut(host, ".googleapis.com")
+	return []string{fmt.Sprintf("%s.googleapis.com/%s", strings.ToLower(backendName), object.Name)}, nil
+}
+
+// Input of templates/cai2hcl/resource_converter.go.tmpl
+type Cai2hclConverterInput struct {
+	Res api.Resource
+
+	// Package of the service the converter belongs to, e.g. "compute"
+	Package string
+
+	AssetTypes []string
+
+	// Properties read from the asset data.
+	ReadProperties []*api.Type
+
+	// The attributes parsed from the asset name, by the collection
+	// preceding them in the name, e.g. "regions" for region.
+	NameFields map[string]string
+
+	// The attributes with a default value, by path in the converted HCL,
+	// e.g. "settings.tier". Attributes equal to their default are left out
+	// of the converted HCL, as users rarely set them.
+	DefaultValues map[string]interface{}
+}
+
+func (c *CaiToTerraformConversion) converterInput(object api.Resource) (Cai2hclConverterInput, error) {
+	assetTypes, err := c.Cai2hclAssetTypes(object)
+	if err != nil {
+		return Cai2hclConverterInput{}, err
+	}
+
+	input := Cai2hclConverterInput{
+		Res:            object,
+		Package:        strings.ToLower(c.Product.Name),
+		AssetTypes:     assetTypes,
+		ReadProperties: cai2hclReadProperties(object),
+	}
+	input.DefaultValues = make(map[string]interface{})
+	cai2hclPropertyDefaults(input.ReadProperties, "", input.DefaultValues)
+	input.NameFields = cai2hclNameFields(object, input.ReadProperties)
+	return input, nil
+}
+
+// Properties read from the asset data.
+func cai2hclReadProperties(object api.Resource) []*api.Type {
+	return google.Reject(object.GettableProperties(), func(p *api.Type) bool {
+		return p.IgnoreRead || cai2hclDerivedLabels(p)
+	})
+}
+
+var nameFieldRegex = regexp.MustCompile(`^\{\{%?(\w+)\}\}$`)
+
+// The fields of the id format of a resource, or of its self link, that
+// aren't read from the asset data, by the collection preceding them.
+func cai2hclNameFields(object api.Resource, readProperties []*api.Type) map[string]string {
+	read := make(map[string]bool)
+	for _, p := range readProperties {
+		read[google.Underscore(p.Name)] = true
+	}
+
+	fields := make(map[string]string)
+	for _, format := range []string{object.IdFormat, object.SelfLinkUri()} {
+		segments := strings.Split(format, "/")
+		for i := 1; i < len(segments); i++ {
+			match := nameFieldRegex.FindStringSubmatch(segments[i])
+			collection := segments[i-1]
+			if match == nil || strings.Contains(collection, "{{") {
+				continue
+			}
+			field := google.Underscore(match[1])
+			if _, ok := fields[field]; ok || read[field] {
+				continue
+			}
+			fields[field] = collection
+		}
+	}
+	return fields
+}
+
+// terraform_labels and effective_labels are derived from labels by the
+// provider, so they aren't read from the asset data.
+func cai2hclDerivedLabels(prop *api.Type) bool {
+	return prop.IsA("KeyValueTerraformLabels") || prop.IsA("KeyValueEffectiveLabels")
+}
+
+func cai2hclPropertyDefaults(properties []*api.Type, prefix string, defaults map[string]interface{}) {
+	for _, prop := range properties {
+		if prop.IgnoreRead {
+			continue
+		}
+		if prop.IsA("NestedObject") {
+			// Flattened objects are only flattened into the top level.
+			nestedPrefix := fmt.Sprintf("%s%s.", prefix, google.Underscore(prop.Name))
+			if prop.FlattenObject && prefix == "" {
+				nestedPrefix = ""
+			}
+			cai2hclPropertyDefaults(prop.NestedProperties(), nestedPrefix, defaults)
+			continue
+		}
+		if prop.DefaultValue == nil {
+			continue
+		}
+		if _, ok := prop.DefaultValue.(map[string]interface{}); ok {
+			continue
+		}
+		defaults[prefix+google.Underscore(prop.Name)] = prop.DefaultValue
+	}
+}
+
+// The name of a property as used in the name of its flattener.
+func (i Cai2hclConverterInput) TitlelizeProperty(prop *api.Type) string {
+	return cai2hclTitlelize(prop)
+}
+
+// The custom code run before the resource data of the asset is converted,
+// see custom_code.pre_convert.
+func (i Cai2hclConverterInput) PreConvert() (string, error) {
+	if i.Res.CustomCode.PreConvert == "" {
+		return "", nil
+	}
+	return cai2hclCustomCode(i.Res.CustomCode.PreConvert, i)
+}
+
+// The custom code run after the resource data of the asset is converted,
+// see custom_code.post_convert.
+func (i Cai2hclConverterInput) PostConvert() (string, error) {
+	if i.Res.CustomCode.PostConvert == "" {
+		return "", nil
+	}
+	return cai2hclCustomCode(i.Res.CustomCode.PostConvert, i)
+}
+
+// Input of templates/cai2hcl/resource_converter.md.tmpl
+type Cai2hclDocumentationInput struct {
+	Res api.Resource
+
+	AssetTypes []string
+
+	// One entry per Terraform field of the object, at every level of nesting.
+	Fields []Cai2hclFieldDoc
+}
+
+// A Terraform field in the generated converter docs.
+type Cai2hclFieldDoc struct {
+	// The path of the asset field the attribute is converted from, e.g.
+	// "settings.tier".
+	AssetField string
+
+	// The path of the Terraform attribute, e.g. "settings.tier".
+	Attribute string
+
+	Note string
+
+	// The reason the converter leaves the attribute unset, if it does.
+	Gap string
+}
+
+// The note and the gap of the field, as shown in the Notes column.
+func (f Cai2hclFieldDoc) Notes() string {
+	return strings.TrimSpace(f.Note + " " + f.Gap)
+}
+
+// The fields the converter leaves unset.
+func (i Cai2hclDocumentationInput) Gaps() []Cai2hclFieldDoc {
+	return google.Select(i.Fields, func(f Cai2hclFieldDoc) bool {
+		return f.Gap != ""
+	})
+}
+
+// The name of the resource in the Terraform registry docs URL, e.g.
+// compute_forwarding_rule
+func (i Cai2hclDocumentationInput) RegistryName() string {
+	return strings.TrimPrefix(i.Res.TerraformName(), "google_")
+}
+
+func (c *CaiToTerraformConversion) documentationInput(object api.Resource) (Cai2hclDocumentationInput, error) {
+	assetTypes, err := c.Cai2hclAssetTypes(object)
+	if err != nil {
+		return Cai2hclDocumentationInput{}, err
+	}
+	return Cai2hclDocumentationInput{
+		Res:        object,
+		AssetTypes: assetTypes,
+		Fields:     c.Cai2hclFieldDocs(object),
+	}, nil
+}
+
+// Returns one entry per Terraform field of the object, at every level of
+// nesting, with the path of the asset field it is converted from and the
+// reason the converter leaves it unset, if it does. The fields of an unset
+// field are omitted.
+func (c *CaiToTerraformConversion) Cai2hclFieldDocs(object api.Resource) []Cai2hclFieldDoc {
+	nameFields := cai2hclNameFields(object, cai2hclReadProperties(object))
+	return c.cai2hclFieldDocs(object.AllUserProperties(), nil, nil, nameFields)
+}
+
+// nameFields are the fields parsed from the asset name at this level.
+func (c *CaiToTerraformConversion) cai2hclFieldDocs(properties []*api.Type, assetPath, tfPath []string, nameFields map[string]string) []Cai2hclFieldDoc {
+	var docs []Cai2hclFieldDoc
+	for _, prop := range properties {
+		if prop.Exclude || (prop.MinVersion != "" && c.Version.CompareTo(&product.Version{Name: prop.MinVersion}) < 0) {
+			continue
+		}
+
+		assetField := append(append([]string{}, assetPath...), prop.ApiName)
+		if prop.FlattenObject {
+			docs = append(docs, c.cai2hclFieldDocs(prop.Properties, assetField, tfPath, nameFields)...)
+			continue
+		}
+		if !cai2hclTfType(prop) {
+			continue
+		}
+
+		attribute := append(append([]string{}, tfPath...), google.Underscore(prop.Name))
+		_, parsed := nameFields[google.Underscore(prop.Name)]
+		doc := Cai2hclFieldDoc{
+			AssetField: strings.Join(assetField, "."),
+			Attribute:  strings.Join(attribute, "."),
+			Note:       cai2hclFieldNote(prop, parsed),
+			Gap:        cai2hclFieldGap(prop, parsed),
+		}
+		if prop.UrlParamOnly {
+			doc.AssetField = "name"
+		}
+		docs = append(docs, doc)
+		if doc.Gap == "" {
+			docs = append(docs, c.cai2hclFieldDocs(prop.NestedProperties(), assetField, attribute, nil)...)
+		}
+	}
+	return docs
+}
+
+// The types with a Terraform schema type, see tf_types in terraform.rb.
+var cai2hclTfTypes = []string{
+	"Boolean", "Double", "Integer", "String", "Time", "Enum", "ResourceRef",
+	"NestedObject", "Array", "KeyValuePairs", "KeyValueLabels",
+	"KeyValueTerraformLabels", "KeyValueEffectiveLabels", "KeyValueAnnotations",
+	"Map", "Fingerprint",
+}
+
+func cai2hclTfType(prop *api.Type) bool {
+	for _, t := range cai2hclTfTypes {
+		if prop.IsA(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// parsed is whether the field is parsed from the asset name.
+func cai2hclFieldNote(prop *api.Type, parsed bool) string {
+	switch {
+	case prop.UrlParamOnly && parsed:
+		return "Parsed from the asset name."
+	case prop.CustomFlatten != "":
+		return "Converted by a custom flattener."
+	case prop.IsA("KeyValueLabels"):
+		return "Labels applied by GCP services, e.g. goog-terraform-provisioned, are left out."
+	}
+	return ""
+}
+
+func cai2hclFieldGap(prop *api.Type, parsed bool) string {
+	switch {
+	case prop.UrlParamOnly && !parsed:
+		return "Only part of the request URL, not stored in the asset."
+	case prop.IgnoreRead:
+		return "Not returned by the API."
+	case cai2hclDerivedLabels(prop):
+		return "Derived from labels by the provider."
+	}
+	return ""
+}
+
+// Input of templates/cai2hcl/service_converter_map.go.tmpl and its test.
+type Cai2hclServiceConverterMapInput struct {
+	// Package of the service, e.g. "compute"
+	Package string
+
+	Handwritten []Cai2hclHandwrittenConverter
+
+	// The generated converters of the service.
+	Converters []Cai2hclServiceConverter
+}
+
+// A generated converter in the converter map of its service.
+type Cai2hclServiceConverter struct {
+	// The prefix of the declarations of the converter, e.g. ComputeRouterNat
+	ResourceName string
+
+	AssetTypes []string
+
+	// Whether the converter reads fine-grained resources stored inside the
+	// assets of its asset types.
+	Nested bool
+
+	// Previous names of the Terraform resource.
+	Aliases []string
+
+	// Test cases in ./testdata the converter is checked against.
+	Tests []string
+}
+
+// Asset types with nested converters, in order of first appearance.
+type Cai2hclNestedAssetType struct {
+	AssetType string
+
+	ResourceNames []string
+}
+
+func (i Cai2hclServiceConverterMapInput) NestedAssetTypes() []Cai2hclNestedAssetType {
+	var nested []Cai2hclNestedAssetType
+	index := make(map[string]int)
+	for _, converter := range i.Converters {
+		if !converter.Nested {
+			continue
+		}
+		for _, assetType := range converter.AssetTypes {
+			j, ok := index[assetType]
+			if !ok {
+				j = len(nested)
+				index[assetType] = j
+				nested = append(nested, Cai2hclNestedAssetType{AssetType: assetType})
+			}
+			nested[j].ResourceNames = append(nested[j].ResourceNames, converter.ResourceName)
+		}
+	}
+	return nested
+}
+
+func (c *CaiToTerraformConversion) serviceConverterMapInput() Cai2hclServiceConverterMapInput {
+	productName := strings.ToLower(c.Product.Name)
+	input := Cai2hclServiceConverterMapInput{
+		Package:     productName,
+		Handwritten: c.HandwrittenConvertersByService[productName],
+	}
+	testdata := path.Join("third_party/cai2hcl/services", productName, "testdata")
+	for _, object := range c.Product.Objects {
+		if object.Exclude || object.ExcludeResource || object.NotInVersion(&c.Version) || ExcludeCai2hcl(*object) || c.Cai2hclHandwritten(*object) {
+			continue
+		}
+		assetTypes, err := c.Cai2hclAssetTypes(*object)
+		if err != nil {
+			log.Fatalf("Cannot generate the %s converter map: %v", productName, err)
+		}
+		input.Converters = append(input.Converters, Cai2hclServiceConverter{
+			ResourceName: object.ResourceName(),
+			AssetTypes:   assetTypes,
+			Nested:       object.CaiNestedAsset.Parent != "",
+			Aliases:      object.Aliases,
+			Tests:        cai2hclTestNames(*object, productName, testdata),
+		})
+	}
+	return input
+}
+
+// Test cases of a generated converter, i.e. the assets in the testdata of
+// its service named after the converter.
+func cai2hclTestNames(object api.Resource, productName, testdata string) []string {
+	if object.ExcludeTgcTest {
+		return nil
+	}
+	name := fmt.Sprintf("%s_%s", productName, google.Underscore(object.Name))
+	if _, err := os.Stat(path.Join(testdata, name+".json")); err != nil {
+		return nil
+	}
+	return []string{name}
+}
@@ -12,21 +12,289 @@
 # limitations under the License.
 
 require 'provider/terraform_oics'
+require 'provider/cai2hcl/coverage_report'
+require 'provider/cai2hcl/documentation'
+require 'provider/cai2hcl/overrides'
+require 'provider/cai2hcl/schema_check'
 require 'fileutils'
+require 'yaml'
 
 module Provider
   # Code generator for a library converting GCP CAI objects to Terraform state.
   class CaiToTerraformConversion < Provider::Terraform
+    include Provider::Cai2hcl::CoverageReport
+    include Provider::Cai2hcl::Documentation
+    include Provider::Cai2hcl::Overrides
+    include Provider::Cai2hcl::SchemaCheck
+
+    HANDWRITTEN_CONVERTERS_FILE = 'provider/cai2hcl/handwritten_converters.yaml'.freeze
+    CONVERSION_MODULE = 'github.com/GoogleCloudPlatform/terraform-google-conversion/v5'.freeze
+
+    # Template for files generated once per service package.
+    class ServiceFileTemplate < Provider::ProviderFileTemplate
+      # The name of the service package, e.g. "compute"
+      attr_accessor :service
+
+      def initialize(output_folder, version, env, products, service)
+        super(output_folder, version, env, products)
+
+        @service = service
+      end
+    end
+
+    # Handwritten converters by service, see handwritten_converters.yaml.
+    attr_reader :handwritten_converters_by_service
+
+    # Module of the generated code, replacing CONVERSION_MODULE in imports,
+    # e.g. for forks of terraform-google-conversion.
+    attr_accessor :conversion_module
+
+    # Import path of the provider packages the generated code is built
+    # against, replacing the one of the generated version in imports.
+    attr_accessor :provider_import_path
+
+    def initialize(api, version_name, start_time)
+      super(api, version_name, start_time)
+
+      @handwritten_converters_by_service = YAML.safe_load(File.read(HANDWRITTEN_CONVERTERS_FILE))
+    end
+
     def generating_hashicorp_repo?
       # This code is not used when generating TPG/TPGB
       false
     end
 
-    def generate(output_folder, types, _product_path, _dump_yaml, generate_code, generate_docs) end
+    def generate(output_folder, types, _product_path, _dump_yaml, generate_code, generate_docs)
+      generate_objects(
+        output_folder,
+        types,
+        generate_code,
+        generate_docs
+      )
+    end
+
+    def generate_object(object, output_folder, version_name, generate_code, generate_docs)
+      if exclude_cai2hcl?(object)
+        Google::LOGGER.info "Skipping fine-grained resource #{object.name}"
+        return
+      end
+
+      super(object, output_folder, version_name, generate_code, generate_docs)
+    end
+
+    def generate_resource(pwd, data, generate_code, generate_docs)
+      if generate_code
+        check_converter_fields(data.object, pwd)
 
-    def generate_resource(pwd, data, _generate_code, _generate_docs) end
+        product_name = data.object.__product.name.downcase
+        output_folder = File.join(data.output_folder, 'services', product_name)
+        object_name = data.object.name.underscore
+        target = "#{product_name}_#{object_name}.go"
+        data.generate(pwd,
+                      'templates/cai2hcl/resource_converter.go.erb',
+                      File.join(output_folder, target),
+                      self)
+        replace_import_path(output_folder, target)
+        apply_converter_override(pwd, product_name, File.join(output_folder, target))
+      end
 
-    def compile_common_files(output_folder, products, _common_compile_file) end
+      return unless generate_docs
+
+      generate_documentation(pwd, data)
+    end
+
+    # Docs are only generated for converters that aren't replaced by a
+    # handwritten one, as they describe the generated conversion.
+    def generate_documentation(pwd, data)
+      return if handwritten_converters.include?(cai2hcl_terraform_name(data.object))
+
+      product_name = data.object.__product.name.downcase
+      target_folder = File.join(data.output_folder, 'docs', product_name)
+      FileUtils.mkpath target_folder
+      filepath = File.join(target_folder, "#{product_name}_#{data.object.name.underscore}.md")
+      data.generate(pwd, 'templates/cai2hcl/resource_converter.md.erb', filepath, self)
+    end
+
+    # Fine-grained resources are excluded from TGC as they have no asset of
+    # their own, but can still be converted if they declare where they are
+    # nested inside the asset of their parent.
+    def exclude_cai2hcl?(object)
+      return true if object.exclude_cai2hcl
+
+      object.exclude_tgc && object.cai_nested_asset.nil?
+    end
+
+    # The resource whose asset a nested resource is read from.
+    def cai2hcl_parent(object)
+      parent_name = object.cai_nested_asset.parent
+      parent = object.__product.objects.find { |o| o.name == parent_name }
+      raise "#{object.name}: cai_nested_asset parent #{parent_name} not found" if parent.nil?
+
+      parent
+    end
+
+    # The primary CAI asset type of a resource, e.g. compute.googleapis.com/ForwardingRule
+    def cai2hcl_asset_type(object)
+      cai2hcl_asset_types(object).first
+    end
+
+    # All the CAI asset types a resource is converted from.
+    def cai2hcl_asset_types(object)
+      return cai2hcl_asset_types(cai2hcl_parent(object)) if object.cai_nested_asset
+      return object.cai_asset_types if object.cai_asset_types
+
+      version = object.__product.version_obj_or_closest(@target_version_name)
+      base_url = version.cai_base_url || version.base_url
+      product_backend_name = base_url.split('://')[1].split('.googleapis.com')[0]
+      ["#{product_backend_name.downcase}.googleapis.com/#{object.name}"]
+    end
+
+    # Properties read from the asset data. terraform_labels and
+    # effective_labels are derived from labels by the provider.
+    def cai2hcl_read_properties(object)
+      object.gettable_properties.reject do |prop|
+        prop.ignore_read || cai2hcl_derived_labels?(prop)
+      end
+    end
+
+    def cai2hcl_derived_labels?(prop)
+      prop.is_a?(Api::Type::KeyValueTerraformLabels) ||
+        prop.is_a?(Api::Type::KeyValueEffectiveLabels)
+    end
+
+    # Labels and annotations are read from the asset rather than filtered by
+    # the ones in the state like in the provider, as there is no state.
+    def build_flatten_method(prefix, property, object, pwd)
+      return super unless property.is_a?(Api::Type::KeyValuePairs) && property.custom_flatten.nil?
+
+      compile_template "#{pwd}/templates/cai2hcl/flatten_labels_method.erb",
+                       prefix:,
+                       property:,
+                       object:,
+                       pwd:
+    end
+
+    # The attributes parsed from the asset name, by the collection preceding
+    # them in the name, e.g. {"project" => "projects", "region" => "regions"}.
+    # These are the fields of the id format of the resource, or of its self
+    # link, that aren't read from the asset data.
+    def cai2hcl_name_fields(object)
+      read = cai2hcl_read_properties(object).map { |p| p.name.underscore }
+      [id_format(object), object.self_link_uri].each_with_object({}) do |format, fields|
+        format.split('/').each_cons(2) do |collection, segment|
+          field = segment[/\A\{\{%?(\w+)\}\}\z/, 1]&.underscore
+          next if field.nil? || collection.include?('{{')
+          next if read.include?(field) || fields.key?(field)
+
+          fields[field] = collection
+        end
+      end
+    end
+
+    # The attributes with a default value, by path in the converted HCL,
+    # e.g. {"settings.tier" => "BASIC"}. Attributes equal to their default
+    # are left out of the converted HCL, as users rarely set them.
+    def cai2hcl_default_values(object)
+      cai2hcl_property_defaults(cai2hcl_read_properties(object))
+    end
+
+    def cai2hcl_property_defaults(properties, prefix = '')
+      properties.reject(&:ignore_read).each_with_object({}) do |prop, defaults|
+        if prop.is_a?(Api::Type::NestedObject)
+          # Flattened objects are only flattened into the top level.
+          nested_prefix = prop.flatten_object && prefix.empty? ? '' : "#{prefix}#{prop.name.underscore}."
+          defaults.merge!(cai2hcl_property_defaults(prop.nested_properties, nested_prefix))
+        elsif !prop.default_value.nil? && !prop.default_value.is_a?(Hash)
+          defaults["#{prefix}#{prop.name.underscore}"] = prop.default_value
+        end
+      end
+    end
+
+    # Objects with a generated converter, i.e. all the converted objects but
+    # the ones replaced by handwritten converters.
+    def cai2hcl_objects(products)
+      products.flat_map do |product|
+        product_definition = product[:definitions]
+        version = product_definition.version_obj_or_closest(@target_version_name)
+        product_definition.objects.reject do |object|
+          object.exclude || object.exclude_resource || exclude_cai2hcl?(object) ||
+            object.not_in_version?(version) ||
+            handwritten_converters.include?(cai2hcl_terraform_name(object))
+        end
+      end
+    end
+
+    def cai2hcl_service_objects(products, service)
+      cai2hcl_objects(products).select { |o| o.__product.name.downcase == service }
+    end
+
+    # Service packages with a converter map, generated or handwritten.
+    def cai2hcl_services(products)
+      services = cai2hcl_objects(products).map { |o| o.__product.name.downcase }
+      (services + handwritten_converters_by_service.keys).uniq.sort
+    end
+
+    # Terraform resources converted by handwritten converters.
+    def handwritten_converters
+      handwritten_converters_by_service.values.flatten.map { |c| c['resource'] }.uniq
+    end
+
+    # The name of the Terraform resource a converter produces, e.g. google_compute_forwarding_rule
+    def cai2hcl_terraform_name(object)
+      return object.legacy_name if object.legacy_name
+
+      tf_product = (object.__product.legacy_name || object.__product.name).underscore
+      "google_#{tf_product}_#{object.name.underscore}"
+    end
+
+    # Test cases of a generated converter, i.e. the assets in the testdata of
+    # its service named after the converter.
+    def cai2hcl_test_names(object, pwd)
+      return [] if object.exclude_tgc_test
+
+      product_name = object.__product.name.downcase
+      name = "#{product_name}_#{object.name.underscore}"
+      testdata = File.join(pwd, 'third_party/cai2hcl/services', product_name, 'testdata')
+      Dir.glob("#{name}.json", base: testdata).map { |f| File.basename(f, '.json') }
+    end
+
+    def compile_common_files(output_folder, products, _common_compile_file)
+      FileUtils.mkdir_p(output_folder)
+      write_coverage_report(output_folder, products)
+
+      Google::LOGGER.info 'Compiling cai2hcl common files.'
+      file_template = ProviderFileTemplate.new(
+        output_folder,
+        @target_version_name,
+        build_env,
+        products
+      )
+      compile_file_list(
+        output_folder,
+        [['converter_map.go', 'templates/cai2hcl/converter_map.go.erb']],
+        file_template
+      )
+
+      cai2hcl_services(products).each do |service|
+        service_template = ServiceFileTemplate.new(
+          output_folder,
+          @target_version_name,
+          build_env,
+          products,
+          service
+        )
+        compile_file_list(
+          output_folder,
+          [
+            ["services/#{service}/converter_map.go",
+             'templates/cai2hcl/service_converter_map.go.erb'],
+            ["services/#{service}/converter_map_test.go",
+             'templates/cai2hcl/service_converter_map_test.go.erb']
+          ],
+          service_template
+        )
+      end
+    end
 
     def copy_common_files(output_folder, generate_code, _generate_docs)
       return unless generate_code
@@ -35,7 +303,80 @@ module Provider
 
       FileUtils.mkdir_p(output_folder)
 
-      FileUtils.cp_r('third_party/cai2hcl/.', output_folder)
+      # Overrides are merged into generated converters instead of copied.
+      Dir.children('third_party/cai2hcl').each do |entry|
+        next if entry == 'overrides'
+
+        FileUtils.cp_r(File.join('third_party/cai2hcl', entry), output_folder)
+      end
+
+      # Handwritten files are written against the beta provider.
+      Dir["#{output_folder}/**/*.go"].each do |file|
+        replace_import_path(output_folder, file.delete_prefix("#{output_folder}/"))
+      end
+    end
+
+    # Removes converters left over from previous runs, e.g. for resources that
+    # were deleted or excluded since. The service and docs folders of products
+    # generated in this run are cleaned, as well as the folders of services no
+    # product of this version has converters for anymore, e.g. of deleted
+    # products. Files copied from third_party/cai2hcl and files without the
+    # autogen notice are never removed.
+    def clean_output_folder(output_folder, products, all_products)
+      handwritten = Dir.glob('**/*', base: 'third_party/cai2hcl')
+
+      products.each do |product|
+        folders = %w[services docs].map { |f| File.join(f, product.name.downcase) }
+        remove_stale_files(output_folder, folders, handwritten)
+      end
+
+      services = cai2hcl_services(all_products)
+      stale_folders = %w[services docs].flat_map do |f|
+        Dir.glob("#{f}/*", base: output_folder).select do |folder|
+          File.directory?(File.join(output_folder, folder)) && !services.include?(File.basename(folder))
+        end
+      end
+      remove_stale_files(output_folder, stale_folders, handwritten)
+      stale_folders.each { |folder| remove_empty_folders(File.join(output_folder, folder)) }
+    end
+
+    def remove_stale_files(output_folder, folders, handwritten)
+      Dir.glob(folders.map { |f| "#{f}/**/*" }, base: output_folder).each do |target|
+        path = File.join(output_folder, target)
+        next unless File.file?(path)
+        next if handwritten.include?(target)
+        next if File.mtime(path) > @start_time
+        next unless File.read(path).include?('***     AUTO GENERATED CODE    ***')
+
+        Google::LOGGER.info "Removing stale generated file #{target}"
+        FileUtils.rm(path)
+      end
+    end
+
+    # Removes the given folder and its subfolders once they're empty, deepest
+    # first.
+    def remove_empty_folders(folder)
+      Dir.glob('**/', base: folder).sort.reverse.each do |dir|
+        path = File.join(folder, dir)
+        Dir.rmdir(path) if Dir.empty?(path)
+      end
+      Dir.rmdir(folder) if Dir.exist?(folder) && Dir.empty?(folder)
+    end
+
+    # Release notes are drafted for the converters and converted fields
+    # added by generation.
+    def changelog_objects(product)
+      super(product).reject do |object|
+        exclude_cai2hcl?(object) || handwritten_converters.include?(cai2hcl_terraform_name(object))
+      end
+    end
+
+    def changelog_new_resource_note(_product, name)
+      "```release-note:enhancement\ncai2hcl: added converter for `#{name}`\n```\n"
+    end
+
+    def changelog_new_field_note(_product, name, field)
+      "```release-note:enhancement\ncai2hcl: added `#{field}` field to the `#{name}` converter\n```\n"
     end
 
     def generate_resource_tests(pwd, data) end
@@ -43,5 +384,25 @@ module Provider
     def generate_iam_policy(pwd, data, generate_code, _generate_docs) end
 
     def generate_resource_sweepers(pwd, data) end
+
+    # Converters are built against the provider matching the generated version,
+    # so that the GA library only references fields available in GA. The
+    # module paths can be overridden, see conversion_module and
+    # provider_import_path.
+    def replace_import_path(output_folder, target)
+      data = File.read("#{output_folder}/#{target}")
+      ga_import = "#{TERRAFORM_PROVIDER_GA}/#{RESOURCE_DIRECTORY_GA}"
+      beta_import = "#{TERRAFORM_PROVIDER_BETA}/#{RESOURCE_DIRECTORY_BETA}"
+      data = if @target_version_name == 'ga'
+               data.gsub(beta_import, ga_import)
+             else
+               data.gsub(%r{(?<!provider ")#{Regexp.escape(ga_import)}}, beta_import)
+             end
+      unless @provider_import_path.nil?
+        data = data.gsub(@target_version_name == 'ga' ? ga_import : beta_import, @provider_import_path)
+      end
+      data = data.gsub(CONVERSION_MODULE, @conversion_module) unless @conversion_module.nil?
+      File.write("#{output_folder}/#{target}", data)
+    end
   end
 end
@@ -0,0 +1,169 @@
+// Copyright 2024 Google Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/google"
+)
+
+// A flattener of a converter, reading a property of the asset data into its
+// Terraform representation, see templates/cai2hcl/flatten_property_method.go.tmpl.
+// Go port of flatten_property_method.erb, with the labels handling of
+// flatten_labels_method.erb.
+type Cai2hclFlattener struct {
+	// The names of the resource and of the parent properties, e.g.
+	// "PubsubTopicMessageStoragePolicy".
+	Prefix string
+
+	Property *api.Type
+}
+
+// The flatteners of the properties read from the asset data.
+func (i Cai2hclConverterInput) Flatteners() []Cai2hclFlattener {
+	var flatteners []Cai2hclFlattener
+	for _, prop := range i.ReadProperties {
+		flatteners = append(flatteners, Cai2hclFlattener{Prefix: i.Res.ResourceName(), Property: prop})
+	}
+	return flatteners
+}
+
+// The name of the flattener function, e.g. "flattenPubsubTopicName".
+func (f Cai2hclFlattener) Name() string {
+	return "flatten" + f.Prefix + cai2hclTitlelize(f.Property)
+}
+
+// The flatteners of the nested properties of an object, of the items of an
+// array of objects or of the values of a map, named after the property.
+func (f Cai2hclFlattener) Nested() []Cai2hclFlattener {
+	var props []*api.Type
+	switch {
+	case f.Property.IsA("NestedObject"):
+		props = f.Property.UserProperties()
+	case f.Property.IsA("Array") && f.Property.ItemType != nil && f.Property.ItemType.IsA("NestedObject"):
+		props = f.Property.ItemType.UserProperties()
+	case f.Property.IsA("Map") && f.Property.ValueType != nil:
+		props = f.Property.ValueType.UserProperties()
+	}
+
+	var flatteners []Cai2hclFlattener
+	for _, prop := range props {
+		flatteners = append(flatteners, Cai2hclFlattener{Prefix: f.Prefix + cai2hclTitlelize(f.Property), Property: prop})
+	}
+	return flatteners
+}
+
+// The flatteners of the properties read for each item of an array of
+// objects.
+func (f Cai2hclFlattener) ItemFlatteners() []Cai2hclFlattener {
+	return google.Reject(f.Nested(), func(n Cai2hclFlattener) bool {
+		return n.Property.IgnoreRead
+	})
+}
+
+// The kind of conversion of the property, selecting the body of the
+// flattener in the template.
+func (f Cai2hclFlattener) Kind() string {
+	p := f.Property
+	switch {
+	case p.CustomFlatten != "":
+		return "custom"
+	case cai2hclDerivedLabels(p):
+		return "derivedLabels"
+	case p.IsA("KeyValueLabels"):
+		return "labels"
+	case p.IsA("KeyValuePairs") || p.IsA("KeyValueAnnotations"):
+		return "value"
+	case p.IsA("NestedObject"):
+		return "nestedObject"
+	case p.IsA("Array") && p.ItemType != nil && p.ItemType.IsA("NestedObject"):
+		return "objectArray"
+	case p.IsA("Map"):
+		return "map"
+	case p.IsA("Integer"):
+		return "integer"
+	case p.IsA("Array") && p.ItemType != nil && p.ItemType.IsA("ResourceRef"):
+		return "resourceRefArray"
+	case p.IsA("ResourceRef"):
+		return "resourceRef"
+	case p.IsSet:
+		return "set"
+	}
+	return "value"
+}
+
+// The hash function of a set of primitive values.
+func (f Cai2hclFlattener) SetHashFunc() (string, error) {
+	p := f.Property
+	switch {
+	case p.SetHashFunc != "":
+		return p.SetHashFunc, nil
+	case p.ItemType != nil && (p.ItemType.IsA("String") || p.ItemType.IsA("Enum")):
+		return "schema.HashString", nil
+	}
+	return "", fmt.Errorf("unknown hash function for property %s", p.Name)
+}
+
+// Renders the custom flattener of the property, given the flattener as
+// data, e.g. custom_flatten/name_from_self_link.go.tmpl for
+// custom_flatten/name_from_self_link.erb.
+func (f Cai2hclFlattener) CustomFlatten() (string, error) {
+	return cai2hclCustomCode(f.Property.CustomFlatten, f)
+}
+
+// Renders the custom code of a converter. Custom code are Go templates next
+// to the ERB ones used by the Ruby generator, see cai2hclGoTemplatePath.
+// Generation fails if the custom code has no Go template, rather than
+// generating a converter without it.
+func cai2hclCustomCode(path string, data any) (string, error) {
+	templatePath := cai2hclGoTemplatePath(path)
+	if _, err := os.Stat(templatePath); err != nil {
+		return "", fmt.Errorf("custom code %s has no Go template: %v", path, err)
+	}
+
+	tmpl, err := template.New(filepath.Base(templatePath)).Funcs(TemplateFunctions).ParseFiles(templatePath)
+	if err != nil {
+		return "", err
+	}
+	contents := bytes.Buffer{}
+	if err := tmpl.Execute(&contents, data); err != nil {
+		return "", err
+	}
+	return contents.String(), nil
+}
+
+// Returns the path of the Go template of an ERB template, e.g.
+// foo.go.tmpl for foo.erb or foo.go.erb.
+func cai2hclGoTemplatePath(path string) string {
+	if strings.HasSuffix(path, ".tmpl") {
+		return path
+	}
+	path = strings.TrimSuffix(path, ".erb")
+	return strings.TrimSuffix(path, ".go") + ".go.tmpl"
+}
+
+// The name of a property as used in the name of its flattener.
+func cai2hclTitlelize(prop *api.Type) string {
+	if prop.Name == "" {
+		return ""
+	}
+	return strings.ToUpper(prop.Name[:1]) + prop.Name[1:]
+}
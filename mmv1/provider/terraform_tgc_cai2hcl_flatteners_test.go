@@ -0,0 +1,229 @@
+package provider
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api/product"
+)
+
+// Imports the packages used by generated converters from the stubs in
+// testdata/cai2hcl_stubs, and the standard library from source.
+type cai2hclStubImporter struct {
+	fset     *token.FileSet
+	stubsDir string
+	stdlib   types.Importer
+	packages map[string]*types.Package
+}
+
+func (i *cai2hclStubImporter) Import(path string) (*types.Package, error) {
+	if pkg, ok := i.packages[path]; ok {
+		return pkg, nil
+	}
+
+	dir := filepath.Join(i.stubsDir, filepath.FromSlash(path))
+	if _, err := os.Stat(dir); err != nil {
+		return i.stdlib.Import(path)
+	}
+
+	parsed, err := parser.ParseDir(i.fset, dir, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	var files []*ast.File
+	for _, p := range parsed {
+		for _, f := range p.Files {
+			files = append(files, f)
+		}
+	}
+
+	conf := types.Config{Importer: i}
+	pkg, err := conf.Check(path, i.fset, files, nil)
+	if err != nil {
+		return nil, err
+	}
+	i.packages[path] = pkg
+	return pkg, nil
+}
+
+func TestCai2hclFlattenersCompile(t *testing.T) {
+	stubsDir, err := filepath.Abs("testdata/cai2hcl_stubs")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	object := api.Resource{
+		NamedObject: api.NamedObject{
+			Name: "Widget",
+		},
+		ProductMetadata: &api.Product{
+			NamedObject: api.NamedObject{Name: "Shop"},
+		},
+		Properties: []*api.Type{
+			{NamedObject: api.NamedObject{Name: "name", ApiName: "name"}, Type: "String", CustomFlatten: "templates/terraform/custom_flatten/name_from_self_link.erb"},
+			{NamedObject: api.NamedObject{Name: "labels", ApiName: "labels"}, Type: "KeyValueLabels"},
+			{NamedObject: api.NamedObject{Name: "network", ApiName: "network"}, Type: "ResourceRef"},
+			{
+				NamedObject: api.NamedObject{Name: "tags", ApiName: "tags"},
+				Type:        "Array",
+				IsSet:       true,
+				ItemType:    &api.Type{Type: "String"},
+			},
+			{
+				NamedObject: api.NamedObject{Name: "settings", ApiName: "settings"},
+				Type:        "NestedObject",
+				Properties: []*api.Type{
+					{NamedObject: api.NamedObject{Name: "size", ApiName: "size"}, Type: "Integer"},
+					{
+						NamedObject: api.NamedObject{Name: "schedule", ApiName: "schedule"},
+						Type:        "NestedObject",
+						Properties: []*api.Type{
+							{NamedObject: api.NamedObject{Name: "cron", ApiName: "cron"}, Type: "String"},
+						},
+					},
+					{
+						NamedObject:   api.NamedObject{Name: "limits", ApiName: "limits"},
+						Type:          "NestedObject",
+						FlattenObject: true,
+						Properties: []*api.Type{
+							{NamedObject: api.NamedObject{Name: "maxSize", ApiName: "maxSize"}, Type: "Integer"},
+						},
+					},
+				},
+			},
+			{
+				NamedObject: api.NamedObject{Name: "rules", ApiName: "rules"},
+				Type:        "Array",
+				ItemType: &api.Type{
+					Type: "NestedObject",
+					Properties: []*api.Type{
+						{NamedObject: api.NamedObject{Name: "action", ApiName: "action"}, Type: "String"},
+						{NamedObject: api.NamedObject{Name: "secret", ApiName: "secret"}, Type: "String", IgnoreRead: true},
+						{
+							NamedObject: api.NamedObject{Name: "networks", ApiName: "networks"},
+							Type:        "Array",
+							ItemType:    &api.Type{Type: "ResourceRef"},
+						},
+					},
+				},
+			},
+			{
+				NamedObject: api.NamedObject{Name: "variants", ApiName: "variants"},
+				Type:        "Map",
+				KeyName:     "variant",
+				ValueType: &api.Type{
+					Type: "NestedObject",
+					Properties: []*api.Type{
+						{NamedObject: api.NamedObject{Name: "price", ApiName: "price"}, Type: "Integer"},
+					},
+				},
+			},
+			{
+				NamedObject:   api.NamedObject{Name: "flattened", ApiName: "config"},
+				Type:          "NestedObject",
+				FlattenObject: true,
+				Properties: []*api.Type{
+					{NamedObject: api.NamedObject{Name: "color", ApiName: "color"}, Type: "String"},
+				},
+			},
+		},
+	}
+	object.CustomCode.PreConvert = "provider/testdata/cai2hcl_custom_code/pre_convert.erb"
+	object.CustomCode.PostConvert = "provider/testdata/cai2hcl_custom_code/post_convert.go.tmpl"
+
+	input := Cai2hclConverterInput{
+		Res:            object,
+		Package:        "shop",
+		AssetTypes:     []string{"shop.googleapis.com/Widget"},
+		ReadProperties: object.Properties,
+	}
+
+	outputDir := t.TempDir()
+	outputPath := filepath.Join(outputDir, "shop_widget.go")
+
+	// Template paths are relative to mmv1.
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(".."); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	NewTemplateData(outputDir, product.Version{Name: "ga"}).GenerateCai2hclConverterFile(outputPath, input)
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, outputPath, nil, 0)
+	if err != nil {
+		t.Fatalf("generated converter does not parse: %v", err)
+	}
+
+	conf := types.Config{
+		Importer: &cai2hclStubImporter{
+			fset:     fset,
+			stubsDir: stubsDir,
+			stdlib:   importer.ForCompiler(fset, "source", nil),
+			packages: make(map[string]*types.Package),
+		},
+	}
+	if _, err := conf.Check("shop", fset, []*ast.File{file}, nil); err != nil {
+		t.Fatalf("generated converter does not compile: %v", err)
+	}
+
+	contents, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		`transformed["schedule"] =`,
+		`flattenShopWidgetSettingsSchedule(original["schedule"], d, config)`,
+		`func flattenShopWidgetSettingsScheduleCron(`,
+		`"action":   flattenShopWidgetRulesAction(original["action"], d, config),`,
+		`"variant": k,`,
+		`return tpgresource.NameFromSelfLinkStateFunc(v)`,
+		`return schema.NewSet(schema.HashString, v.([]interface{}))`,
+		`return nil, fmt.Errorf("Widget %s is deleted", asset.Name)`,
+		`delete(hclData, "tags")`,
+	} {
+		if !strings.Contains(string(contents), expected) {
+			t.Errorf("expected the generated converter to contain %q:\n%s", expected, contents)
+		}
+	}
+	if strings.Contains(string(contents), "flattenShopWidgetRulesSecret(original") {
+		t.Errorf("expected ignore_read properties not to be read from array items:\n%s", contents)
+	}
+}
+
+func TestCai2hclGoTemplatePath(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"templates/terraform/custom_flatten/foo.erb":     "templates/terraform/custom_flatten/foo.go.tmpl",
+		"templates/terraform/custom_flatten/foo.go.erb":  "templates/terraform/custom_flatten/foo.go.tmpl",
+		"templates/terraform/custom_flatten/foo.go.tmpl": "templates/terraform/custom_flatten/foo.go.tmpl",
+	}
+	for path, expected := range cases {
+		if got := cai2hclGoTemplatePath(path); got != expected {
+			t.Errorf("expected %s to be %s for %s", got, expected, path)
+		}
+	}
+}
+
+func TestCai2hclConverterInputPreConvertWithoutGoTemplate(t *testing.T) {
+	t.Parallel()
+
+	input := Cai2hclConverterInput{}
+	input.Res.CustomCode.PreConvert = "testdata/cai2hcl_custom_code/missing.erb"
+	if _, err := input.PreConvert(); err == nil {
+		t.Errorf("expected an error for custom code without a Go template")
+	}
+}
@@ -0,0 +1,241 @@
+// Copyright 2024 Google Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Local fixes to generated converters, kept in third_party/cai2hcl/overrides
+// so that they survive regeneration. An override has the path of the
+// converter it patches, e.g. overrides/compute/compute_forwarding_rule.go.
+// Each top-level declaration of the override replaces the generated
+// declaration of the same name, other declarations are appended to the
+// converter. Imports of the override are added to the converter.
+// Go port of provider/cai2hcl/overrides.rb.
+const cai2hclOverridesFolder = "third_party/cai2hcl/overrides"
+
+// Start of a top-level declaration, capturing the method receiver type and
+// the name of the declaration.
+var goDeclarationRegex = regexp.MustCompile(`^(?:func\s+(?:\(\s*\w*\s*\*?(\w+)\s*\)\s*)?|type\s+|var\s+|const\s+)(\w+)`)
+
+// Patches the generated converter at filePath with its override, if any.
+func ApplyCai2hclConverterOverride(productName, filePath string) error {
+	override := path.Join(cai2hclOverridesFolder, productName, filepath.Base(filePath))
+	overrideContent, err := os.ReadFile(override)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Applying cai2hcl override %s", override)
+	generated, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+	merged, err := MergeGoDeclarations(string(generated), string(overrideContent))
+	if err != nil {
+		return fmt.Errorf("%s: %v", override, err)
+	}
+	return os.WriteFile(filePath, []byte(merged), 0644)
+}
+
+// Returns generated with the declarations and imports of override.
+func MergeGoDeclarations(generated, override string) (string, error) {
+	lines := goLines(generated)
+	overrideLines := goLines(override)
+
+	overrides, err := goDeclarations(overrideLines)
+	if err != nil {
+		return "", err
+	}
+	declarations, err := goDeclarations(lines)
+	if err != nil {
+		return "", err
+	}
+
+	replaced := make(map[string]bool)
+	for i := len(declarations) - 1; i >= 0; i-- {
+		decl := declarations[i]
+		replacement, ok := findGoDeclaration(overrides, decl.key)
+		if !ok || replaced[decl.key] {
+			continue
+		}
+		replaced[decl.key] = true
+
+		merged := append([]string{}, lines[:decl.first]...)
+		merged = append(merged, replacement.lines...)
+		lines = append(merged, lines[decl.last+1:]...)
+	}
+
+	lines = mergeGoImports(lines, goImports(overrideLines))
+	for _, decl := range overrides {
+		if replaced[decl.key] {
+			continue
+		}
+		lines = append(lines, "\n")
+		lines = append(lines, decl.lines...)
+	}
+	return strings.Join(lines, ""), nil
+}
+
+// A top-level declaration of a Go file, with its doc comment.
+type goDeclaration struct {
+	// The name of the declaration, e.g. "Converter.convert" for methods.
+	key string
+
+	// The range of the declaration in the lines of the file.
+	first, last int
+
+	lines []string
+}
+
+// Returns the lines of a Go file, with their line endings.
+func goLines(content string) []string {
+	lines := strings.SplitAfter(content, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// Returns the top-level declarations of a Go file, in order. A declaration
+// declared twice is kept at its first position, with its last content.
+func goDeclarations(lines []string) ([]goDeclaration, error) {
+	var declarations []goDeclaration
+	index := make(map[string]int)
+	for i := 0; i < len(lines); {
+		match := goDeclarationRegex.FindStringSubmatch(lines[i])
+		if match == nil {
+			i++
+			continue
+		}
+
+		first := i
+		for first > 0 && strings.HasPrefix(lines[first-1], "//") {
+			first--
+		}
+		last, err := goDeclarationEnd(lines, i)
+		if err != nil {
+			return nil, err
+		}
+		key := match[2]
+		if match[1] != "" {
+			key = match[1] + "." + key
+		}
+		decl := goDeclaration{key: key, first: first, last: last, lines: lines[first : last+1]}
+		if j, ok := index[key]; ok {
+			declarations[j] = decl
+		} else {
+			index[key] = len(declarations)
+			declarations = append(declarations, decl)
+		}
+		i = last + 1
+	}
+	return declarations, nil
+}
+
+func findGoDeclaration(declarations []goDeclaration, key string) (goDeclaration, bool) {
+	for _, decl := range declarations {
+		if decl.key == key {
+			return decl, true
+		}
+	}
+	return goDeclaration{}, false
+}
+
+// Declarations spanning several lines are closed by a brace or parenthesis
+// at the start of a line.
+func goDeclarationEnd(lines []string, start int) (int, error) {
+	line := strings.TrimRight(lines[start], " \t\r\n")
+	if !strings.HasSuffix(line, "{") && !strings.HasSuffix(line, "(") {
+		return start, nil
+	}
+	for j := start + 1; j < len(lines); j++ {
+		if strings.HasPrefix(lines[j], "}") || strings.HasPrefix(lines[j], ")") {
+			return j, nil
+		}
+	}
+	return 0, fmt.Errorf("unterminated declaration: %s", line)
+}
+
+// Returns the import specs of a Go file, e.g. ["\t\"fmt\"\n"].
+func goImports(lines []string) []string {
+	var specs []string
+	start := -1
+	for i, l := range lines {
+		if strings.HasPrefix(l, "import (") {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		for _, l := range lines {
+			if strings.HasPrefix(l, `import "`) {
+				specs = append(specs, strings.TrimPrefix(l, "import "))
+			}
+		}
+	} else {
+		for _, l := range lines[start+1:] {
+			if strings.HasPrefix(l, ")") {
+				break
+			}
+			specs = append(specs, l)
+		}
+	}
+
+	var imports []string
+	for _, spec := range specs {
+		if spec = strings.TrimSpace(spec); spec != "" {
+			imports = append(imports, "\t"+spec+"\n")
+		}
+	}
+	return imports
+}
+
+func mergeGoImports(lines, imports []string) []string {
+	start := -1
+	for i, l := range lines {
+		if strings.HasPrefix(l, "import (") {
+			start = i
+			break
+		}
+	}
+	if len(imports) == 0 || start < 0 {
+		return lines
+	}
+
+	existing := make(map[string]bool)
+	for _, l := range lines {
+		existing[strings.TrimSpace(l)] = true
+	}
+	var missing []string
+	for _, i := range imports {
+		if !existing[strings.TrimSpace(i)] {
+			missing = append(missing, i)
+		}
+	}
+
+	merged := append([]string{}, lines[:start+1]...)
+	merged = append(merged, missing...)
+	return append(merged, lines[start+1:]...)
+}
@@ -0,0 +1,103 @@
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const generatedConverter = `package compute
+
+import (
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v5/cai2hcl/common"
+)
+
+// Convert converts asset to HCL resource blocks.
+func (c *AddressConverter) Convert(assets []*caiasset.Asset) ([]*common.HCLResourceBlock, error) {
+	return nil, nil
+}
+
+func flattenAddressName(v interface{}) interface{} {
+	return v
+}
+`
+
+const converterOverride = `package compute
+
+import "strings"
+
+func flattenAddressName(v interface{}) interface{} {
+	return strings.ToLower(v.(string))
+}
+
+func helper() {}
+`
+
+func TestMergeGoDeclarations(t *testing.T) {
+	t.Parallel()
+
+	merged, err := MergeGoDeclarations(generatedConverter, converterOverride)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cases := []struct {
+		description string
+		check       func(string) bool
+	}{
+		{
+			description: "replaces declarations of the same name",
+			check: func(s string) bool {
+				return strings.Contains(s, "\treturn strings.ToLower(v.(string))\n") && !strings.Contains(s, "\treturn v\n")
+			},
+		},
+		{
+			description: "keeps other generated declarations",
+			check: func(s string) bool {
+				return strings.Contains(s, "// Convert converts asset to HCL resource blocks.") && strings.Contains(s, "\treturn nil, nil\n")
+			},
+		},
+		{
+			description: "appends new declarations",
+			check: func(s string) bool {
+				return strings.HasSuffix(s, "\nfunc helper() {}\n")
+			},
+		},
+		{
+			description: "adds imports",
+			check: func(s string) bool {
+				return strings.Contains(s, "import (\n\t\"strings\"\n")
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		if !tc.check(merged) {
+			t.Errorf("%s: unexpected merge:\n%s", tc.description, merged)
+		}
+	}
+}
+
+func TestMergeGoDeclarations_unterminated(t *testing.T) {
+	t.Parallel()
+
+	if _, err := MergeGoDeclarations(generatedConverter, "func helper() {\n"); err == nil {
+		t.Errorf("expected an error merging an unterminated declaration")
+	}
+}
+
+func TestApplyCai2hclConverterOverride_noOverride(t *testing.T) {
+	t.Parallel()
+
+	filePath := filepath.Join(t.TempDir(), "compute_address.go")
+	if err := os.WriteFile(filePath, []byte(generatedConverter), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ApplyCai2hclConverterOverride("compute", filePath); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, _ := os.ReadFile(filePath); string(got) != generatedConverter {
+		t.Errorf("expected the converter without override to be unchanged, got:\n%s", got)
+	}
+}
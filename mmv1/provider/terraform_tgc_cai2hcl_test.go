@@ -0,0 +1,440 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api/product"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api/resource"
+)
+
+func TestCai2hclAssetTypes(t *testing.T) {
+	t.Parallel()
+
+	router := &api.Resource{
+		NamedObject: api.NamedObject{
+			Name: "Router",
+		},
+	}
+	forwardingRule := &api.Resource{
+		NamedObject: api.NamedObject{
+			Name: "ForwardingRule",
+		},
+		CaiAssetTypes: []string{
+			"compute.googleapis.com/ForwardingRule",
+			"compute.googleapis.com/GlobalForwardingRule",
+		},
+	}
+	routerNat := &api.Resource{
+		NamedObject: api.NamedObject{
+			Name: "RouterNat",
+		},
+		CaiNestedAsset: resource.CaiNestedAsset{
+			Parent: "Router",
+			Keys:   []string{"nats"},
+		},
+	}
+	orphan := &api.Resource{
+		NamedObject: api.NamedObject{
+			Name: "Orphan",
+		},
+		CaiNestedAsset: resource.CaiNestedAsset{
+			Parent: "Missing",
+			Keys:   []string{"orphans"},
+		},
+	}
+
+	cases := []struct {
+		description string
+		version     product.Version
+		obj         *api.Resource
+		expected    []string
+		expectedErr bool
+	}{
+		{
+			description: "asset type is derived from the base url",
+			version: product.Version{
+				Name:    "ga",
+				BaseUrl: "https://compute.googleapis.com/compute/v1/",
+			},
+			obj:      router,
+			expected: []string{"compute.googleapis.com/Router"},
+		},
+		{
+			description: "cai base url takes precedence over the base url",
+			version: product.Version{
+				Name:       "ga",
+				BaseUrl:    "https://www.googleapis.com/compute/v1/",
+				CaiBaseUrl: "https://compute.googleapis.com/compute/v1/",
+			},
+			obj:      router,
+			expected: []string{"compute.googleapis.com/Router"},
+		},
+		{
+			description: "declared asset types take precedence over the derived one",
+			version: product.Version{
+				Name:    "ga",
+				BaseUrl: "https://compute.googleapis.com/compute/v1/",
+			},
+			obj: forwardingRule,
+			expected: []string{
+				"compute.googleapis.com/ForwardingRule",
+				"compute.googleapis.com/GlobalForwardingRule",
+			},
+		},
+		{
+			description: "nested resources use the asset types of their parent",
+			version: product.Version{
+				Name:    "ga",
+				BaseUrl: "https://compute.googleapis.com/compute/v1/",
+			},
+			obj:      routerNat,
+			expected: []string{"compute.googleapis.com/Router"},
+		},
+		{
+			description: "nested resources with a missing parent are an error",
+			version: product.Version{
+				Name:    "ga",
+				BaseUrl: "https://compute.googleapis.com/compute/v1/",
+			},
+			obj:         orphan,
+			expectedErr: true,
+		},
+		{
+			description: "base url without a scheme is an error",
+			version: product.Version{
+				Name:    "ga",
+				BaseUrl: "compute/v1/",
+			},
+			obj:         router,
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			c := CaiToTerraformConversion{
+				Terraform: Terraform{
+					Version: tc.version,
+					Product: api.Product{
+						Objects: []*api.Resource{router, forwardingRule, routerNat, orphan},
+					},
+				},
+			}
+
+			got, err := c.Cai2hclAssetTypes(*tc.obj)
+			if tc.expectedErr {
+				if err == nil {
+					t.Errorf("expected an error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("expected %v to be %v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestExcludeCai2hcl(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		obj         api.Resource
+		expected    bool
+	}{
+		{
+			description: "resources are converted by default",
+			obj:         api.Resource{},
+			expected:    false,
+		},
+		{
+			description: "resources excluded from tgc are not converted",
+			obj: api.Resource{
+				ExcludeTgc: true,
+			},
+			expected: true,
+		},
+		{
+			description: "nested resources excluded from tgc are converted",
+			obj: api.Resource{
+				ExcludeTgc: true,
+				CaiNestedAsset: resource.CaiNestedAsset{
+					Parent: "Router",
+					Keys:   []string{"nats"},
+				},
+			},
+			expected: false,
+		},
+		{
+			description: "resources excluded from cai2hcl are not converted",
+			obj: api.Resource{
+				ExcludeCai2hcl: true,
+				CaiNestedAsset: resource.CaiNestedAsset{
+					Parent: "Router",
+					Keys:   []string{"nats"},
+				},
+			},
+			expected: true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			if got, want := ExcludeCai2hcl(tc.obj), tc.expected; got != want {
+				t.Errorf("expected %v to be %v", got, want)
+			}
+		})
+	}
+}
+
+func TestCai2hclConverterInputNames(t *testing.T) {
+	t.Parallel()
+
+	input := Cai2hclConverterInput{}
+	prop := &api.Type{
+		NamedObject: api.NamedObject{
+			Name: "region",
+		},
+	}
+
+	if got, want := input.TitlelizeProperty(prop), "Region"; got != want {
+		t.Errorf("expected %v to be %v", got, want)
+	}
+}
+
+func TestCai2hclPropertyDefaults(t *testing.T) {
+	t.Parallel()
+
+	props := []*api.Type{
+		{NamedObject: api.NamedObject{Name: "tier"}, Type: "Enum", DefaultValue: "BASIC"},
+		{NamedObject: api.NamedObject{Name: "name"}, Type: "String"},
+		{NamedObject: api.NamedObject{Name: "ignored"}, Type: "String", DefaultValue: "foo", IgnoreRead: true},
+		{
+			NamedObject: api.NamedObject{Name: "diskSettings"},
+			Type:        "NestedObject",
+			Properties: []*api.Type{
+				{NamedObject: api.NamedObject{Name: "sizeGb"}, Type: "Integer", DefaultValue: 10},
+			},
+		},
+		{
+			NamedObject:   api.NamedObject{Name: "flattened"},
+			Type:          "NestedObject",
+			FlattenObject: true,
+			Properties: []*api.Type{
+				{NamedObject: api.NamedObject{Name: "autoRestart"}, Type: "Boolean", DefaultValue: true},
+			},
+		},
+	}
+
+	got := make(map[string]interface{})
+	cai2hclPropertyDefaults(props, "", got)
+
+	expected := map[string]interface{}{
+		"tier":                  "BASIC",
+		"disk_settings.size_gb": 10,
+		"auto_restart":          true,
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v to be %v", got, expected)
+	}
+}
+
+func TestCai2hclDerivedLabels(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]bool{
+		"KeyValueLabels":          false,
+		"KeyValueAnnotations":     false,
+		"KeyValueTerraformLabels": true,
+		"KeyValueEffectiveLabels": true,
+	}
+	for typeName, expected := range cases {
+		if got := cai2hclDerivedLabels(&api.Type{Type: typeName}); got != expected {
+			t.Errorf("%s: expected %v to be %v", typeName, got, expected)
+		}
+	}
+}
+
+func TestCai2hclNameFields(t *testing.T) {
+	t.Parallel()
+
+	object := api.Resource{
+		NamedObject: api.NamedObject{
+			Name: "RouterNat",
+		},
+		BaseUrl:  "projects/{{project}}/regions/{{region}}/routers/{{router}}",
+		IdFormat: "{{project}}/{{region}}/{{router}}/{{name}}",
+	}
+	readProperties := []*api.Type{
+		{NamedObject: api.NamedObject{Name: "name"}},
+	}
+
+	expected := map[string]string{
+		"project": "projects",
+		"region":  "regions",
+		"router":  "routers",
+	}
+	if got := cai2hclNameFields(object, readProperties); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v to be %v", got, expected)
+	}
+}
+
+func TestCai2hclFieldDocs(t *testing.T) {
+	t.Parallel()
+
+	object := api.Resource{
+		NamedObject: api.NamedObject{
+			Name: "Instance",
+		},
+		BaseUrl: "projects/{{project}}/locations/{{region}}/instances",
+		Properties: []*api.Type{
+			{NamedObject: api.NamedObject{Name: "displayName", ApiName: "displayName"}, Type: "String"},
+			{NamedObject: api.NamedObject{Name: "secret", ApiName: "secret"}, Type: "String", IgnoreRead: true},
+			{NamedObject: api.NamedObject{Name: "betaField", ApiName: "betaField"}, Type: "String", MinVersion: "beta"},
+			{
+				NamedObject: api.NamedObject{Name: "settings", ApiName: "settings"},
+				Type:        "NestedObject",
+				Properties: []*api.Type{
+					{NamedObject: api.NamedObject{Name: "tier", ApiName: "tier"}, Type: "Enum", CustomFlatten: "templates/terraform/custom_flatten/tier.go.tmpl"},
+				},
+			},
+			{
+				NamedObject:   api.NamedObject{Name: "flattened", ApiName: "config"},
+				Type:          "NestedObject",
+				FlattenObject: true,
+				Properties: []*api.Type{
+					{NamedObject: api.NamedObject{Name: "size", ApiName: "size"}, Type: "Integer"},
+				},
+			},
+		},
+		Parameters: []*api.Type{
+			{NamedObject: api.NamedObject{Name: "region", ApiName: "region"}, Type: "String", UrlParamOnly: true},
+			{NamedObject: api.NamedObject{Name: "requestId", ApiName: "requestId"}, Type: "String", UrlParamOnly: true},
+		},
+	}
+	object.IdFormat = "projects/{{project}}/locations/{{region}}/instances/{{name}}"
+
+	c := CaiToTerraformConversion{
+		Terraform: Terraform{
+			Version: product.Version{Name: "ga"},
+		},
+	}
+
+	expected := []Cai2hclFieldDoc{
+		{AssetField: "displayName", Attribute: "display_name"},
+		{AssetField: "secret", Attribute: "secret", Gap: "Not returned by the API."},
+		{AssetField: "settings", Attribute: "settings"},
+		{AssetField: "settings.tier", Attribute: "settings.tier", Note: "Converted by a custom flattener."},
+		{AssetField: "config.size", Attribute: "size"},
+		{AssetField: "name", Attribute: "region", Note: "Parsed from the asset name."},
+		{AssetField: "name", Attribute: "request_id", Gap: "Only part of the request URL, not stored in the asset."},
+	}
+	if got := c.Cai2hclFieldDocs(object); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v to be %v", got, expected)
+	}
+}
+
+func TestCai2hclHandwritten(t *testing.T) {
+	t.Parallel()
+
+	handwritten, err := ReadCai2hclHandwrittenConverters("cai2hcl/handwritten_converters.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(handwritten["compute"]) == 0 || handwritten["compute"][0].Converter == "" || len(handwritten["compute"][0].AssetTypes) == 0 {
+		t.Fatalf("expected the handwritten compute converters, got %v", handwritten)
+	}
+
+	c := CaiToTerraformConversion{HandwrittenConvertersByService: handwritten}
+	instance := api.Resource{
+		NamedObject: api.NamedObject{Name: "Instance"},
+		LegacyName:  handwritten["compute"][0].Resource,
+	}
+	address := api.Resource{
+		NamedObject: api.NamedObject{Name: "Address"},
+		LegacyName:  "google_compute_address",
+	}
+	if !c.Cai2hclHandwritten(instance) {
+		t.Errorf("expected %s to be converted by a handwritten converter", instance.LegacyName)
+	}
+	if c.Cai2hclHandwritten(address) {
+		t.Errorf("expected %s to be converted by a generated converter", address.LegacyName)
+	}
+}
+
+func TestCai2hclServiceConverterMapInput(t *testing.T) {
+	t.Parallel()
+
+	versions := []*product.Version{{Name: "ga"}, {Name: "beta"}}
+	p := &api.Product{
+		NamedObject: api.NamedObject{Name: "Compute"},
+		Versions:    versions,
+	}
+	newResource := func(name string, opts func(*api.Resource)) *api.Resource {
+		r := &api.Resource{
+			NamedObject:     api.NamedObject{Name: name},
+			ProductMetadata: p,
+		}
+		if opts != nil {
+			opts(r)
+		}
+		return r
+	}
+	p.Objects = []*api.Resource{
+		newResource("Router", nil),
+		newResource("RouterNat", func(r *api.Resource) {
+			r.CaiNestedAsset = resource.CaiNestedAsset{Parent: "Router", Keys: []string{"nats"}}
+			r.ExcludeTgc = true
+		}),
+		newResource("RouterPeer", func(r *api.Resource) {
+			r.CaiNestedAsset = resource.CaiNestedAsset{Parent: "Router", Keys: []string{"bgpPeers"}}
+			r.Aliases = []string{"google_compute_router_bgp_peer"}
+		}),
+		newResource("Instance", func(r *api.Resource) { r.LegacyName = "google_compute_instance" }),
+		newResource("BetaOnly", func(r *api.Resource) { r.MinVersion = "beta" }),
+		newResource("Excluded", func(r *api.Resource) { r.ExcludeCai2hcl = true }),
+	}
+
+	c := CaiToTerraformConversion{
+		Terraform: Terraform{
+			Version: product.Version{Name: "ga", BaseUrl: "https://compute.googleapis.com/compute/v1/"},
+			Product: *p,
+		},
+		HandwrittenConvertersByService: map[string][]Cai2hclHandwrittenConverter{
+			"compute": {{Resource: "google_compute_instance", Converter: "NewComputeInstanceConverter", AssetTypes: []string{"ComputeInstanceAssetType"}}},
+		},
+	}
+
+	input := c.serviceConverterMapInput()
+	if input.Package != "compute" || len(input.Handwritten) != 1 {
+		t.Errorf("expected the compute package with its handwritten converter, got %v", input)
+	}
+	var names []string
+	for _, converter := range input.Converters {
+		names = append(names, converter.ResourceName)
+	}
+	if expected := []string{"ComputeRouter", "ComputeRouterNat", "ComputeRouterPeer"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected %v to be %v", names, expected)
+	}
+
+	expected := []Cai2hclNestedAssetType{
+		{AssetType: "compute.googleapis.com/Router", ResourceNames: []string{"ComputeRouterNat", "ComputeRouterPeer"}},
+	}
+	if got := input.NestedAssetTypes(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v to be %v", got, expected)
+	}
+}
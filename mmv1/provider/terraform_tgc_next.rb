@@ -0,0 +1,175 @@
+# Copyright 2024 Google Inc.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+require 'provider/terraform_tgc'
+require 'provider/terraform_tgc_cai2hcl'
+require 'fileutils'
+
+module Provider
+  # Code generator for the whole terraform-google-conversion library. Produces
+  # both conversion directions (tfplan2cai and cai2hcl) in a single run, sharing
+  # test data, CAI asset type constants and the converter registry between them.
+  class TerraformGoogleConversionNext < Provider::TerraformGoogleConversion
+    TFPLAN2CAI_FOLDER = 'tfplan2cai'.freeze
+    CAI2HCL_FOLDER = 'cai2hcl'.freeze
+
+    def initialize(api, version_name, start_time)
+      super(api, version_name, start_time)
+
+      @cai2hcl = Provider::CaiToTerraformConversion.new(api, version_name, start_time)
+    end
+
+    # Only the imports of the cai2hcl converters are configurable.
+    def conversion_module=(conversion_module)
+      @cai2hcl.conversion_module = conversion_module
+    end
+
+    def provider_import_path=(provider_import_path)
+      @cai2hcl.provider_import_path = provider_import_path
+    end
+
+    def generate(output_folder, types, _product_path, _dump_yaml, generate_code, generate_docs)
+      FileUtils.mkdir_p(File.join(output_folder, TFPLAN2CAI_FOLDER,
+                                  'converters/google/resources'))
+
+      @base_url = @version.cai_base_url || @version.base_url
+      generate_objects(
+        output_folder,
+        types,
+        generate_code,
+        generate_docs
+      )
+    end
+
+    # Objects are frozen once per run, so both directions are generated from
+    # the same pass over the product.
+    def generate_object(object, output_folder, version_name, generate_code, generate_docs)
+      super(object, File.join(output_folder, TFPLAN2CAI_FOLDER),
+            version_name, generate_code, generate_docs)
+      @cai2hcl.generate_object(object, File.join(output_folder, CAI2HCL_FOLDER),
+                               version_name, generate_code, generate_docs)
+    end
+
+    # Round-trip tests convert the examples of a resource to CAI assets and
+    # back, so they're only generated for resources with a generated converter
+    # in both directions.
+    def generate_resource_tests(pwd, data)
+      super(pwd, data)
+
+      object = data.object
+      return if object.exclude_tgc_test || @cai2hcl.exclude_cai2hcl?(object)
+      return if @cai2hcl.handwritten_converters.include?(conversion_terraform_name(object))
+      return if roundtrip_examples(object, data.version, pwd).empty?
+
+      FileUtils.mkpath 'test'
+      data.generate(
+        pwd,
+        'templates/tgc_next/roundtrip_test.go.erb',
+        "test/roundtrip_#{full_resource_name(data)}_generated_test.go",
+        self
+      )
+    end
+
+    # Examples whose config is planned without reading existing resources.
+    def roundtrip_examples(object, version, pwd)
+      object.examples
+            .reject(&:skip_test)
+            .reject do |e|
+              @api.version_obj_or_closest(version) < @api.version_obj_or_closest(e.min_version)
+            end
+            .reject { |e| e.config_test_body(pwd) =~ /data.*".*".*".*".*\{/ }
+    end
+
+    # Test data is shared by both directions, so it lives at the root of the
+    # output instead of inside tfplan2cai.
+    def test_data_folder
+      '../../testdata/templates'
+    end
+
+    def retrieve_full_list_of_test_files_with_location
+      files = retrieve_full_list_of_test_files
+      files.map do |file|
+        ["../testdata/templates/#{file}", "third_party/tgc/tests/data/#{file}"]
+      end
+    end
+
+    def compile_common_files(output_folder, products, common_compile_file)
+      super(File.join(output_folder, TFPLAN2CAI_FOLDER), products, common_compile_file)
+      @cai2hcl.compile_common_files(File.join(output_folder, CAI2HCL_FOLDER),
+                                    products, common_compile_file)
+
+      Google::LOGGER.info 'Compiling shared conversion files.'
+      file_template = ProviderFileTemplate.new(
+        output_folder,
+        @target_version_name,
+        build_env,
+        products
+      )
+      compile_file_list(
+        output_folder,
+        [
+          ['caiasset/asset_types.go',
+           'templates/tgc_next/asset_types.go.erb'],
+          ['converters/registry.go',
+           'templates/tgc_next/converter_registry.go.erb']
+        ],
+        file_template
+      )
+    end
+
+    def copy_common_files(output_folder, generate_code, generate_docs)
+      return unless generate_code
+
+      super(File.join(output_folder, TFPLAN2CAI_FOLDER), generate_code, generate_docs)
+      copy_file_list(File.join(output_folder, TFPLAN2CAI_FOLDER),
+                     [['test/roundtrip_test.go', 'third_party/tgc_next/tests/roundtrip_test.go']])
+      @cai2hcl.copy_common_files(File.join(output_folder, CAI2HCL_FOLDER),
+                                 generate_code, generate_docs)
+    end
+
+    # Release notes are drafted for the converters added to cai2hcl, for the
+    # whole library.
+    def write_changelog_draft(output_folder, products)
+      @cai2hcl.write_changelog_draft(output_folder, products)
+    end
+
+    def clean_output_folder(output_folder, products, all_products)
+      @cai2hcl.clean_output_folder(File.join(output_folder, CAI2HCL_FOLDER), products, all_products)
+    end
+
+    # Returns the resources converted by the library, with the asset type they
+    # map to. Used by the shared asset type constants and converter registry.
+    def conversion_objects(products)
+      products.flat_map do |product|
+        product_definition = product[:definitions]
+        product_definition.objects.reject do |object|
+          object.exclude || object.exclude_resource || object.exclude_tgc ||
+            object.not_in_version?(product_definition.version_obj_or_closest(@target_version_name))
+        end
+      end
+    end
+
+    def conversion_asset_type(object)
+      @cai2hcl.cai2hcl_asset_type(object)
+    end
+
+    # Asset types other than the primary one, which has a constant in caiasset.
+    def conversion_additional_asset_types(object)
+      @cai2hcl.cai2hcl_asset_types(object).drop(1)
+    end
+
+    def conversion_terraform_name(object)
+      @cai2hcl.cai2hcl_terraform_name(object)
+    end
+  end
+end
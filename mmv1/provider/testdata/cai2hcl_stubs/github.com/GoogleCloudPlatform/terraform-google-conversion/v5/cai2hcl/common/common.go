@@ -0,0 +1,40 @@
+package common
+
+import (
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v5/caiasset"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+)
+
+type Converter interface {
+	Convert(assets []*caiasset.Asset) ([]*HCLResourceBlock, error)
+}
+
+type Value struct{}
+
+type HCLResourceBlock struct {
+	Labels []string
+	Value  Value
+}
+
+func NewConfig() *transport_tpg.Config {
+	return nil
+}
+
+func NestedObjects(data map[string]interface{}, keys []string) []map[string]interface{} {
+	return nil
+}
+
+func ParseAssetNameFields(name string, collections map[string]string) map[string]interface{} {
+	return nil
+}
+
+func RemoveDefaultValues(data map[string]interface{}, defaults map[string]interface{}) {}
+
+func UserLabels(v interface{}) interface{} {
+	return v
+}
+
+func MapToCtyValWithSchema(m map[string]interface{}, resourceType string, s map[string]*schema.Schema) (Value, error) {
+	return Value{}, nil
+}
@@ -0,0 +1,11 @@
+package caiasset
+
+type Asset struct {
+	Name     string
+	Type     string
+	Resource *AssetResource
+}
+
+type AssetResource struct {
+	Data map[string]interface{}
+}
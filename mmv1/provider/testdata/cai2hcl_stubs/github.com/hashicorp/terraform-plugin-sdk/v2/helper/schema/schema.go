@@ -0,0 +1,27 @@
+package schema
+
+type ResourceData struct{}
+
+type Schema struct {
+	Elem interface{}
+}
+
+type Resource struct {
+	Schema map[string]*Schema
+}
+
+type Provider struct {
+	ResourcesMap map[string]*Resource
+}
+
+type SchemaSetFunc func(interface{}) int
+
+type Set struct{}
+
+func NewSet(f SchemaSetFunc, items []interface{}) *Set {
+	return &Set{}
+}
+
+func HashString(v interface{}) int {
+	return 0
+}
@@ -0,0 +1,21 @@
+package tpgresource
+
+func StringToFixed64(v string) (int64, error) {
+	return 0, nil
+}
+
+func GetResourceNameFromSelfLink(link string) string {
+	return link
+}
+
+func ConvertAndMapStringArr(ifaceArr []interface{}, f func(string) string) []string {
+	return nil
+}
+
+func ConvertSelfLinkToV1(link string) string {
+	return link
+}
+
+func NameFromSelfLinkStateFunc(v interface{}) string {
+	return ""
+}
@@ -0,0 +1,3 @@
+package transport
+
+type Config struct{}
@@ -0,0 +1,59 @@
+# Copyright 2024 Google Inc.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+require 'spec_helper'
+require 'tmpdir'
+
+describe Google::GenerationManifest do
+  around do |example|
+    Dir.mktmpdir do |dir|
+      Dir.chdir(dir) do
+        FileUtils.mkpath 'products/foo/nested'
+        File.write('products/foo/product.yaml', 'name: Foo')
+        File.write('products/foo/nested/Bar.yaml', 'name: Bar')
+        example.run
+      end
+    end
+  end
+
+  let(:manifest) do
+    described_class.new('ga', 'terraform', true, false, 'shared',
+                        { 'products/foo' => described_class.hash_directories('products/foo') })
+  end
+
+  describe '#hash_directories' do
+    # Same digest as TestHashDirectories_relativePaths in google/file_hash_test.go.
+    subject { described_class.hash_directories('products/foo') }
+    it { is_expected.to eq '69ce42418c6a667071b1482a27b66a475b6b3b7967e5a91b70af122b37564571' }
+  end
+
+  describe '#unchanged?' do
+    before { manifest.write('.', nil, ['products/foo']) }
+
+    it 'is unchanged for the same run' do
+      expect(manifest.unchanged?(manifest.read_previous('.'), 'products/foo')).to be true
+    end
+
+    it 'is changed for another provider' do
+      other = described_class.new('ga', 'tgc_cai2hcl', true, false, 'shared', manifest.products)
+      expect(other.unchanged?(other.read_previous('.'), 'products/foo')).to be false
+    end
+
+    it 'is changed when the product changes' do
+      File.write('products/foo/product.yaml', 'name: Bar')
+      changed = described_class.new('ga', 'terraform', true, false, 'shared',
+                                    { 'products/foo' => described_class.hash_directories('products/foo') })
+      expect(changed.unchanged?(changed.read_previous('.'), 'products/foo')).to be false
+    end
+  end
+end
@@ -0,0 +1,72 @@
+# Copyright 2024 Google Inc.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+require 'spec_helper'
+require 'provider/cai2hcl/overrides'
+
+describe Provider::Cai2hcl::Overrides do
+  let(:overrides) { Class.new { include Provider::Cai2hcl::Overrides }.new }
+
+  let(:generated) do
+    <<~GO
+      package compute
+
+      import (
+      \t"github.com/GoogleCloudPlatform/terraform-google-conversion/v5/cai2hcl/common"
+      )
+
+      // Convert converts asset to HCL resource blocks.
+      func (c *AddressConverter) Convert(assets []*caiasset.Asset) ([]*common.HCLResourceBlock, error) {
+      \treturn nil, nil
+      }
+
+      func flattenAddressName(v interface{}) interface{} {
+      \treturn v
+      }
+    GO
+  end
+
+  context '#merge_go_declarations' do
+    subject do
+      overrides.merge_go_declarations(generated, <<~GO)
+        package compute
+
+        import "strings"
+
+        func flattenAddressName(v interface{}) interface{} {
+        \treturn strings.ToLower(v.(string))
+        }
+
+        func helper() {}
+      GO
+    end
+
+    it 'replaces declarations of the same name' do
+      is_expected.to include("\treturn strings.ToLower(v.(string))\n")
+      is_expected.not_to include("\treturn v\n")
+    end
+
+    it 'keeps other generated declarations' do
+      is_expected.to include('// Convert converts asset to HCL resource blocks.')
+      is_expected.to include("\treturn nil, nil\n")
+    end
+
+    it 'appends new declarations' do
+      is_expected.to end_with("\nfunc helper() {}\n")
+    end
+
+    it 'adds imports' do
+      is_expected.to include("import (\n\t\"strings\"\n")
+    end
+  end
+end
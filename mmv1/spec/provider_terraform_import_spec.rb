@@ -50,6 +50,31 @@ describe Provider::Terraform do
       end
     end
 
+    describe '#import_test_formats' do
+      subject do
+        provider.import_test_formats(
+          resource(
+            'base_url: "projects/{{project}}/regions/{{region}}/subnetworks"',
+            'parameters:',
+            '  - !ruby/object:Api::Type::String',
+            '    name: region',
+            '    url_param_only: true',
+            'properties:',
+            '  - !ruby/object:Api::Type::String',
+            '    name: name'
+          )
+        )
+      end
+
+      it do
+        is_expected.to contain_exactly(
+          '{{project}}/{{region}}/{{name}}',
+          '{{region}}/{{name}}',
+          '{{name}}'
+        )
+      end
+    end
+
     def allow_open(file_name)
       IO.expects(:read).with(file_name).returns(File.real_read(file_name))
         .at_least(0)
@@ -100,6 +100,82 @@ describe Provider::Terraform do
       end
     end
 
+    describe '#framework_model_type' do
+      subject do
+        %w[String Enum Boolean Integer Double Array].map do |type|
+          provider.framework_model_type(typed_property(type, 'foo'))
+        end
+      end
+
+      it do
+        is_expected.to eq(
+          %w[types.String types.String types.Bool types.Int64 types.Float64 types.List]
+        )
+      end
+    end
+
+    describe '#framework_plan_modifier' do
+      subject { provider.framework_plan_modifier(typed_property('Integer', 'foo')) }
+      it { is_expected.to eq 'int64planmodifier' }
+    end
+
+    describe '#changelog_notes' do
+      subject do
+        provider.changelog_notes(
+          { 'compute' => { 'google_compute_address' => %w[name] } },
+          {
+            'compute' => {
+              'google_compute_address' => %w[name labels],
+              'google_compute_network' => %w[name]
+            }
+          }
+        )
+      end
+
+      it do
+        is_expected.to eq [
+          "```release-note:enhancement\ncompute: added `labels` field to " \
+          "`google_compute_address` resource\n```\n",
+          "```release-note:new-resource\n`google_compute_network`\n```\n"
+        ]
+      end
+    end
+
+    describe '#generated_resources_manifest' do
+      before do
+        provider.resources_for_version = [
+          {
+            product: 'Compute', terraform_name: 'google_compute_address',
+            resource_name: 'compute.ResourceComputeAddress',
+            datasource_name: 'compute.DataSourceComputeAddress'
+          },
+          {
+            product: 'Pubsub', terraform_name: 'google_pubsub_topic',
+            resource_name: 'pubsub.ResourcePubsubTopic', iam_class_name: 'pubsub.PubsubTopic'
+          }
+        ]
+      end
+
+      subject { provider.generated_resources_manifest }
+
+      it 'lists resources' do
+        expect(subject[:resources].map { |r| [r[:name], r[:mode]] }).to eq [
+          %w[google_compute_address sdk],
+          %w[google_pubsub_topic sdk],
+          %w[google_pubsub_topic_iam_binding iam],
+          %w[google_pubsub_topic_iam_member iam],
+          %w[google_pubsub_topic_iam_policy iam]
+        ]
+      end
+
+      it 'lists data sources' do
+        expect(subject[:datasources]).to eq [
+          { name: 'google_compute_address', product: 'Compute', mode: 'sdk' },
+          { name: 'google_pubsub_topic_iam_policy', product: 'Pubsub', mode: 'iam' }
+        ]
+      end
+    end
+
     describe '#properties_by_custom_update' do
       let(:postUrl1) { custom_update_property('p1', 'url1', :POST) }
       let(:otherPostUrl1) { custom_update_property('p2', 'url1', :POST) }
@@ -244,6 +320,92 @@ describe Provider::Terraform do
         )
       end
     end
+
+    describe '#constraint_schema_fields' do
+      let(:constraint) do
+        Google::YamlValidator.parse(<<~YAML)
+          --- !ruby/object:Api::Resource::Constraint
+          type: conflicts
+          fields:
+            - string_one
+            - object_one.0.object_one_string
+        YAML
+      end
+      let(:property) do
+        override_resource.properties.find { |p| p.name == 'stringOne' }
+      end
+
+      before { override_resource.instance_variable_set(:@constraints, [constraint]) }
+
+      it 'lists the other fields of the constraint' do
+        expect(provider.constraint_schema_fields(property, override_resource, 'conflicts'))
+          .to eq(['object_one.0.object_one_string'])
+      end
+
+      it 'ignores constraints of other types' do
+        expect(provider.constraint_schema_fields(property, override_resource, 'exactly_one_of'))
+          .to eq([])
+      end
+    end
+
+    describe '#generate_datasource?' do
+      let(:another_resource) do
+        product.objects.find { |o| o.name == 'AnotherResource' }
+      end
+
+      it 'is generated when the identifying fields are in the schema' do
+        expect(provider.generate_datasource?(resource)).to be true
+      end
+
+      it 'is not generated when an identifying field is not in the schema' do
+        expect(provider.generate_datasource?(another_resource)).to be false
+      end
+
+      it 'is not generated when generate_datasource is false' do
+        resource.instance_variable_set(:@generate_datasource, false)
+        expect(provider.generate_datasource?(resource)).to be false
+      end
+
+      it 'is not generated when the resource cannot be read' do
+        resource.instance_variable_set(:@skip_read, true)
+        expect(provider.generate_datasource?(resource)).to be false
+      end
+    end
+
+    context 'data source fields' do
+      before do
+        provider.stubs(:id_format)
+                .returns('projects/{{project}}/regions/{{region}}/things/{{name}}')
+      end
+
+      it 'requires the fields without a provider default' do
+        expect(provider.datasource_required_fields(resource)).to eq(['name'])
+      end
+
+      it 'makes the fields with a provider default optional' do
+        expect(provider.datasource_optional_fields(resource)).to eq(%w[project region])
+      end
+    end
+
+    describe '#check_datasource_collisions' do
+      it 'fails on a handwritten data source of the same name' do
+        provider.stubs(:handwritten_datasources)
+                .returns(['google_my_product_referenced_resource'])
+        expect { provider.check_datasource_collisions(resource) }
+          .to raise_error(/collides with the handwritten one/)
+      end
+
+      it 'passes without a handwritten data source of the same name' do
+        provider.stubs(:handwritten_datasources).returns([])
+        expect { provider.check_datasource_collisions(resource) }.not_to raise_error
+      end
+
+      it 'fails on data sources of the same name generated twice' do
+        provider.stubs(:handwritten_datasources).returns([])
+        expect { provider.check_all_datasource_collisions([resource, resource]) }
+          .to raise_error(/is also generated for ReferencedResource/)
+      end
+    end
   end
 
   def allow_open(file_name)
@@ -258,6 +420,12 @@ describe Provider::Terraform do
     )
   end
 
+  def typed_property(type, name)
+    Google::YamlValidator.parse(
+      "--- !ruby/object:Api::Type::#{type}\nname: '#{name}'"
+    )
+  end
+
   def custom_update_property(name, update_url = nil, update_verb = nil)
     lines = []
     lines.push '--- !ruby/object:Api::Type::String'
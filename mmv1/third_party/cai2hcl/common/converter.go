@@ -15,4 +15,8 @@ type Converter interface {
 type HCLResourceBlock struct {
 	Labels []string
 	Value  cty.Value
+
+	// MovedFrom are previous types of the resource, written as `moved`
+	// blocks to the resource.
+	MovedFrom []string
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/hcl/hcl/printer"
+	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/zclconf/go-cty/cty"
 )
@@ -18,11 +19,28 @@ func HclWriteBlocks(blocks []*HCLResourceBlock) ([]byte, error) {
 		if err := hclWriteBlock(resourceBlock.Value, hclBlock.Body()); err != nil {
 			return nil, err
 		}
+		for _, previousType := range resourceBlock.MovedFrom {
+			hclWriteMovedBlock(rootBody, previousType, resourceBlock.Labels)
+		}
 	}
 
 	return printer.Format(f.Bytes())
 }
 
+// hclWriteMovedBlock writes a `moved` block from the resource of the same name
+// of the previous type, e.g. of a resource type renamed by the provider.
+func hclWriteMovedBlock(body *hclwrite.Body, previousType string, labels []string) {
+	movedBody := body.AppendNewBlock("moved", nil).Body()
+	movedBody.SetAttributeTraversal("from", hcl.Traversal{
+		hcl.TraverseRoot{Name: previousType},
+		hcl.TraverseAttr{Name: labels[1]},
+	})
+	movedBody.SetAttributeTraversal("to", hcl.Traversal{
+		hcl.TraverseRoot{Name: labels[0]},
+		hcl.TraverseAttr{Name: labels[1]},
+	})
+}
+
 func hclWriteBlock(val cty.Value, body *hclwrite.Body) error {
 	if val.IsNull() {
 		return nil
@@ -0,0 +1,9 @@
+package common
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	tpg_provider "github.com/hashicorp/terraform-provider-google-beta/google-beta/provider"
+)
+
+// Provider is the Terraform provider the converters take their resource schemas from.
+var Provider *schema.Provider = tpg_provider.Provider()
@@ -7,20 +7,121 @@ import (
 
 	hashicorpcty "github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-provider-google-beta/google-beta/resourcename"
+	"github.com/hashicorp/terraform-provider-google-beta/google-beta/tpgresource"
 	transport_tpg "github.com/hashicorp/terraform-provider-google-beta/google-beta/transport"
 	"github.com/zclconf/go-cty/cty"
 	ctyjson "github.com/zclconf/go-cty/cty/json"
 )
 
-// ParseFieldValue extracts named part from resource url.
+// ParseFieldValue extracts named part from resource url, see
+// resourcename.FieldValue.
 func ParseFieldValue(url string, name string) string {
-	fragments := strings.Split(url, "/")
-	for ix, item := range fragments {
-		if item == name && ix+1 < len(fragments) {
-			return fragments[ix+1]
+	return resourcename.FieldValue(url, name)
+}
+
+// ParseAssetNameFields extracts the fields of a resource from the name of its
+// asset, given the collection preceding each field in the name, e.g.
+// "regions" for region. Fields missing from the name are left out.
+func ParseAssetNameFields(name string, collections map[string]string) map[string]interface{} {
+	fields := make(map[string]interface{})
+	for field, collection := range collections {
+		if value := ParseFieldValue(name, collection); value != "" {
+			fields[field] = value
+		}
+	}
+	return fields
+}
+
+// NestedObjects returns the objects found by traversing keys in the asset data.
+// If the value at the end of the path is a list, every object in it is returned.
+func NestedObjects(data map[string]interface{}, keys []string) []map[string]interface{} {
+	var node interface{} = data
+	for _, key := range keys {
+		obj, ok := node.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		node = obj[key]
+	}
+
+	switch node.(type) {
+	case map[string]interface{}:
+		return []map[string]interface{}{node.(map[string]interface{})}
+	case []interface{}:
+		var result []map[string]interface{}
+		for _, item := range node.([]interface{}) {
+			if obj, ok := item.(map[string]interface{}); ok {
+				result = append(result, obj)
+			}
+		}
+		return result
+	default:
+		return nil
+	}
+}
+
+// RemoveDefaultValues removes the attributes equal to their default value from
+// the flattened resource data. Defaults are keyed by attribute path, e.g.
+// "settings.tier", and nested objects are traversed whether flattened to a
+// list or not. Values are compared by their string form, as flatteners don't
+// return numbers with the type of the defaults.
+func RemoveDefaultValues(data map[string]interface{}, defaults map[string]interface{}) {
+	for path, defaultValue := range defaults {
+		removeDefaultValue(data, strings.Split(path, "."), defaultValue)
+	}
+}
+
+func removeDefaultValue(node interface{}, keys []string, defaultValue interface{}) {
+	switch node := node.(type) {
+	case []interface{}:
+		for _, item := range node {
+			removeDefaultValue(item, keys, defaultValue)
+		}
+	case map[string]interface{}:
+		value, ok := node[keys[0]]
+		if !ok || value == nil {
+			return
+		}
+		if len(keys) > 1 {
+			removeDefaultValue(value, keys[1:], defaultValue)
+		} else if fmt.Sprint(value) == fmt.Sprint(defaultValue) {
+			delete(node, keys[0])
 		}
 	}
-	return ""
+}
+
+// SystemLabelPrefixes are the prefixes of labels applied by GCP services
+// rather than by users, e.g. goog-terraform-provisioned.
+var SystemLabelPrefixes = []string{"goog-"}
+
+// UserLabels returns the labels of an asset that were set by users, as
+// system-applied labels aren't part of the labels field of resources.
+func UserLabels(v interface{}) interface{} {
+	labels, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	transformed := make(map[string]interface{})
+	for k, label := range labels {
+		if !isSystemLabel(k) {
+			transformed[k] = label
+		}
+	}
+	if len(transformed) == 0 {
+		return nil
+	}
+	return transformed
+}
+
+func isSystemLabel(key string) bool {
+	for _, prefix := range SystemLabelPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
 }
 
 // DecodeJSON decodes the map object into the target struct.
@@ -40,7 +141,9 @@ func DecodeJSON(data map[string]interface{}, v interface{}) error {
 // Normalization is a post-processing of the output map, which does the following:
 // * Converts unmarshallable "schema.Set" to marshallable counterpart.
 // * Strips out properties, which are not part ofthe resource TF schema.
-func MapToCtyValWithSchema(m map[string]interface{}, s map[string]*schema.Schema) (cty.Value, error) {
+//
+// The type of the schema is cached by resource type, see tpgresource.SchemaImpliedType.
+func MapToCtyValWithSchema(m map[string]interface{}, resourceType string, s map[string]*schema.Schema) (cty.Value, error) {
 	m = normalizeFlattenedObj(m, s).(map[string]interface{})
 
 	b, err := json.Marshal(&m)
@@ -48,7 +151,7 @@ func MapToCtyValWithSchema(m map[string]interface{}, s map[string]*schema.Schema
 		return cty.NilVal, fmt.Errorf("error marshaling map as JSON: %v", err)
 	}
 
-	ty, err := hashicorpCtyTypeToZclconfCtyType(schema.InternalMap(s).CoreConfigSchema().ImpliedType())
+	ty, err := hashicorpCtyTypeToZclconfCtyType(tpgresource.SchemaImpliedType(resourceType, s))
 	if err != nil {
 		return cty.NilVal, fmt.Errorf("error casting type: %v", err)
 	}
@@ -18,7 +18,7 @@ func TestSubsetOfFieldsMapsToCtyValue(t *testing.T) {
 		"name": "forwarding-rule-1",
 	}
 
-	val, err := MapToCtyValWithSchema(outputMap, schema)
+	val, err := MapToCtyValWithSchema(outputMap, "google_compute_forwarding_rule", schema)
 
 	assert.Nil(t, err)
 	assert.Equal(t, "forwarding-rule-1", val.GetAttr("name").AsString())
@@ -31,7 +31,7 @@ func TestWrongFieldTypeBreaksConversion(t *testing.T) {
 		"description": []string{"unknownValue"}, // string is required, not array.
 	}
 
-	val, err := MapToCtyValWithSchema(outputMap, resourceSchema)
+	val, err := MapToCtyValWithSchema(outputMap, "google_compute_backend_service", resourceSchema)
 
 	assert.True(t, val.IsNull())
 	assert.Contains(t, err.Error(), "string is required")
@@ -44,7 +44,7 @@ func TestNilValue(t *testing.T) {
 		"description": nil,
 	}
 
-	val, err := MapToCtyValWithSchema(outputMap, resourceSchema)
+	val, err := MapToCtyValWithSchema(outputMap, "google_compute_forwarding_rule", resourceSchema)
 
 	assert.Nil(t, err)
 	assert.Equal(t, cty.Value(cty.StringVal("fr-1")), val.GetAttr("name"))
@@ -57,7 +57,7 @@ func TestNilValueInRequiredField(t *testing.T) {
 		"name": nil,
 	}
 
-	val, err := MapToCtyValWithSchema(outputMap, resourceSchema)
+	val, err := MapToCtyValWithSchema(outputMap, "google_compute_forwarding_rule", resourceSchema)
 
 	// In future we may want to fail in this case.
 	assert.Nil(t, err)
@@ -71,7 +71,7 @@ func TestFieldsWithTypeSlice(t *testing.T) {
 		"ports": []string{"80"},
 	}
 
-	val, err := MapToCtyValWithSchema(outputMap, resourceSchema)
+	val, err := MapToCtyValWithSchema(outputMap, "google_compute_forwarding_rule", resourceSchema)
 
 	assert.Nil(t, err)
 
@@ -85,7 +85,7 @@ func TestMissingFieldDoesNotBreakConversionConversion(t *testing.T) {
 		"unknownField": "unknownValue",
 	}
 
-	val, err := MapToCtyValWithSchema(outputMap, resourceSchema)
+	val, err := MapToCtyValWithSchema(outputMap, "google_compute_forwarding_rule", resourceSchema)
 
 	assert.Nil(t, err)
 
@@ -100,7 +100,7 @@ func TestFieldWithTypeSchemaSet(t *testing.T) {
 		"ports": schema.NewSet(schema.HashString, tpgresource.ConvertStringArrToInterface([]string{"80"})),
 	}
 
-	val, err := MapToCtyValWithSchema(outputMap, resourceSchema)
+	val, err := MapToCtyValWithSchema(outputMap, "google_compute_forwarding_rule", resourceSchema)
 
 	assert.Nil(t, err)
 	assert.Equal(t, []cty.Value{cty.StringVal("80")}, val.GetAttr("ports").AsValueSlice())
@@ -128,7 +128,7 @@ func TestFieldWithTypeSchemaListAndNestedObject(t *testing.T) {
 		},
 	}
 
-	val, err := MapToCtyValWithSchema(flattenedMap, resourceSchema)
+	val, err := MapToCtyValWithSchema(flattenedMap, "test_list_nested_object", resourceSchema)
 
 	assert.Nil(t, err)
 	assert.Equal(t,
@@ -167,7 +167,7 @@ func TestFieldWithTypeSchemaSetAndNestedObject(t *testing.T) {
 		}),
 	}
 
-	val, err := MapToCtyValWithSchema(flattenedMap, resourceSchema)
+	val, err := MapToCtyValWithSchema(flattenedMap, "test_set_nested_object", resourceSchema)
 
 	assert.Nil(t, err)
 	assert.Equal(t,
@@ -180,6 +180,93 @@ func TestFieldWithTypeSchemaSetAndNestedObject(t *testing.T) {
 		val.GetAttr("list").AsValueSlice())
 }
 
+func TestParseAssetNameFields(t *testing.T) {
+	name := "//compute.googleapis.com/projects/project-1/regions/us-central1/routers/router-1"
+
+	assert.Equal(t,
+		map[string]interface{}{
+			"project": "project-1",
+			"region":  "us-central1",
+			"router":  "router-1",
+		},
+		ParseAssetNameFields(name, map[string]string{
+			"project": "projects",
+			"region":  "regions",
+			"router":  "routers",
+			"zone":    "zones",
+		}))
+}
+
+func TestNestedObjects(t *testing.T) {
+	data := map[string]interface{}{
+		"name": "router-1",
+		"nats": []interface{}{
+			map[string]interface{}{"name": "nat-1"},
+			map[string]interface{}{"name": "nat-2"},
+		},
+		"bgp": map[string]interface{}{
+			"asn": 64514,
+		},
+	}
+
+	assert.Equal(t,
+		[]map[string]interface{}{{"name": "nat-1"}, {"name": "nat-2"}},
+		NestedObjects(data, []string{"nats"}))
+	assert.Equal(t,
+		[]map[string]interface{}{{"asn": 64514}},
+		NestedObjects(data, []string{"bgp"}))
+	assert.Nil(t, NestedObjects(data, []string{"bgp", "asn", "missing"}))
+	assert.Nil(t, NestedObjects(data, []string{"interfaces"}))
+}
+
+func TestRemoveDefaultValues(t *testing.T) {
+	data := map[string]interface{}{
+		"name":         "instance-1",
+		"tier":         "BASIC",
+		"memory_size":  1,
+		"auto_restart": false,
+		"settings": []interface{}{
+			map[string]interface{}{
+				"edition":   "STANDARD",
+				"disk_size": 10,
+			},
+		},
+	}
+
+	RemoveDefaultValues(data, map[string]interface{}{
+		"tier":               "BASIC",
+		"memory_size":        2,
+		"auto_restart":       false,
+		"settings.edition":   "STANDARD",
+		"settings.disk_size": 20,
+		"missing.field":      "value",
+	})
+
+	assert.Equal(t,
+		map[string]interface{}{
+			"name":        "instance-1",
+			"memory_size": 1,
+			"settings": []interface{}{
+				map[string]interface{}{
+					"disk_size": 10,
+				},
+			},
+		},
+		data)
+}
+
+func TestUserLabels(t *testing.T) {
+	labels := map[string]interface{}{
+		"env":                        "prod",
+		"goog-terraform-provisioned": "true",
+		"goog-dataproc-cluster-name": "cluster-1",
+	}
+
+	assert.Equal(t, map[string]interface{}{"env": "prod"}, UserLabels(labels))
+	assert.Nil(t, UserLabels(map[string]interface{}{"goog-terraform-provisioned": "true"}))
+	assert.Nil(t, UserLabels(nil))
+}
+
 func createSchema(name string) map[string]*schema.Schema {
 	provider := tpg_provider.Provider()
 
@@ -2,6 +2,7 @@ package cai2hcl
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/GoogleCloudPlatform/terraform-google-conversion/v5/caiasset"
 
@@ -13,6 +14,10 @@ import (
 // require updating function signatures all along the pipe.
 type Options struct {
 	ErrorLogger *zap.Logger
+
+	// EmitMovedBlocks adds a `moved` block from each previous name of the
+	// converted resources, see ConverterAliases.
+	EmitMovedBlocks bool
 }
 
 // Converts CAI Assets into HCL string.
@@ -30,6 +35,16 @@ func Convert(assets []*caiasset.Asset, options *Options) ([]byte, error) {
 		if name != "" {
 			groups[name] = append(groups[name], asset)
 		}
+
+		// Fine-grained resources are converted from the asset of their parent.
+		for _, name := range NestedAssetTypeToConverters[asset.Type] {
+			groups[name] = append(groups[name], asset)
+		}
+	}
+
+	var previousNames map[string][]string
+	if options.EmitMovedBlocks {
+		previousNames = converterPreviousNames()
 	}
 
 	allBlocks := []*common.HCLResourceBlock{}
@@ -42,6 +57,9 @@ func Convert(assets []*caiasset.Asset, options *Options) ([]byte, error) {
 		if err != nil {
 			return nil, err
 		}
+		for _, block := range newBlocks {
+			block.MovedFrom = previousNames[name]
+		}
 
 		allBlocks = append(allBlocks, newBlocks...)
 	}
@@ -52,3 +70,16 @@ func Convert(assets []*caiasset.Asset, options *Options) ([]byte, error) {
 
 	return t, err
 }
+
+// converterPreviousNames is the inverse of ConverterAliases: the previous
+// names of Terraform resources, by converter name.
+func converterPreviousNames() map[string][]string {
+	result := make(map[string][]string)
+	for alias, name := range ConverterAliases {
+		result[name] = append(result[name], alias)
+	}
+	for _, aliases := range result {
+		sort.Strings(aliases)
+	}
+	return result
+}
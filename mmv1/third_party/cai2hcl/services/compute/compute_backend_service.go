@@ -211,7 +211,7 @@ func (c *ComputeBackendServiceConverter) convertResourceData(asset *caiasset.Ass
 
 	hcl, _ := flattenComputeBackendService(assetResourceData, config)
 
-	ctyVal, err := common.MapToCtyValWithSchema(hcl, c.schema)
+	ctyVal, err := common.MapToCtyValWithSchema(hcl, c.name, c.schema)
 	if err != nil {
 		return nil, err
 	}
@@ -79,7 +79,7 @@ func (c *ComputeForwardingRuleConverter) convertResourceData(asset *caiasset.Ass
 
 	hcl, _ := flattenComputeForwardingRule(assetResourceData, config)
 
-	ctyVal, err := common.MapToCtyValWithSchema(hcl, c.schema)
+	ctyVal, err := common.MapToCtyValWithSchema(hcl, c.name, c.schema)
 	if err != nil {
 		return nil, err
 	}
@@ -127,7 +127,7 @@ func (c *ComputeInstanceConverter) convertResourceData(asset *caiasset.Asset) (*
 		hclData["zone"] = common.ParseFieldValue(instance.Zone, "zones")
 	}
 
-	ctyVal, err := common.MapToCtyValWithSchema(hclData, c.schema)
+	ctyVal, err := common.MapToCtyValWithSchema(hclData, c.name, c.schema)
 	if err != nil {
 		return nil, err
 	}
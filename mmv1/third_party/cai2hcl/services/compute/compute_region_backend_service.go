@@ -151,7 +151,7 @@ func (c *ComputeRegionBackendServiceConverter) convertResourceData(asset *caiass
 
 	hcl, _ := resourceComputeRegionBackendServiceRead(assetResourceData, config)
 
-	ctyVal, err := common.MapToCtyValWithSchema(hcl, c.schema)
+	ctyVal, err := common.MapToCtyValWithSchema(hcl, c.name, c.schema)
 	if err != nil {
 		return nil, err
 	}
@@ -133,7 +133,7 @@ func (c *ProjectConverter) convertResourceData(asset *caiasset.Asset) (*common.H
 		hclData["billing_account"] = billingAccount
 	}
 
-	ctyVal, err := common.MapToCtyValWithSchema(hclData, c.schema)
+	ctyVal, err := common.MapToCtyValWithSchema(hclData, c.name, c.schema)
 	if err != nil {
 		return nil, err
 	}
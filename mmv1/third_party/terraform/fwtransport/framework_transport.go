@@ -17,6 +17,14 @@ func SendFrameworkRequest(p *FrameworkProviderConfig, method, project, rawurl, u
 }
 
 func SendFrameworkRequestWithTimeout(p *FrameworkProviderConfig, method, project, rawurl, userAgent string, body map[string]interface{}, timeout time.Duration, errorRetryPredicates ...transport_tpg.RetryErrorPredicateFunc) (map[string]interface{}, diag.Diagnostics) {
+	res, diags, _ := SendFrameworkRequestWithError(p, method, project, rawurl, userAgent, body, timeout, errorRetryPredicates...)
+	return res, diags
+}
+
+// SendFrameworkRequestWithError behaves like SendFrameworkRequestWithTimeout, but
+// also returns the error of a failed request so that callers can handle
+// specific API errors, e.g. a resource that doesn't exist anymore.
+func SendFrameworkRequestWithError(p *FrameworkProviderConfig, method, project, rawurl, userAgent string, body map[string]interface{}, timeout time.Duration, errorRetryPredicates ...transport_tpg.RetryErrorPredicateFunc) (map[string]interface{}, diag.Diagnostics, error) {
 	var diags diag.Diagnostics
 
 	reqHeaders := make(http.Header)
@@ -77,12 +85,12 @@ func SendFrameworkRequestWithTimeout(p *FrameworkProviderConfig, method, project
 	})
 	if err != nil {
 		diags.AddError("error sending request", err.Error())
-		return nil, diags
+		return nil, diags, err
 	}
 
 	if res == nil {
 		diags.AddError("Unable to parse server response.", "This is most likely a terraform problem, please file a bug at https://github.com/hashicorp/terraform-provider-google/issues.")
-		return nil, diags
+		return nil, diags, nil
 	}
 
 	// The defer call must be made outside of the retryFunc otherwise it's closed too soon.
@@ -91,13 +99,13 @@ func SendFrameworkRequestWithTimeout(p *FrameworkProviderConfig, method, project
 	// 204 responses will have no body, so we're going to error with "EOF" if we
 	// try to parse it. Instead, we can just return nil.
 	if res.StatusCode == 204 {
-		return nil, diags
+		return nil, diags, nil
 	}
 	result := make(map[string]interface{})
 	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
 		diags.AddError("error decoding response body", err.Error())
-		return nil, diags
+		return nil, diags, nil
 	}
 
-	return result, diags
+	return result, diags, nil
 }
@@ -53,8 +53,15 @@ func GetCurrentUserEmailFramework(p *FrameworkProviderConfig, userAgent string,
 }
 
 func GenerateFrameworkUserAgentString(metaData *fwmodels.ProviderMetaModel, currUserAgent string) string {
-	if metaData != nil && !metaData.ModuleName.IsNull() && metaData.ModuleName.ValueString() != "" {
-		return strings.Join([]string{currUserAgent, metaData.ModuleName.ValueString()}, " ")
+	if metaData == nil {
+		return currUserAgent
+	}
+	m := transport_tpg.ProviderMeta{
+		ModuleName:    metaData.ModuleName.ValueString(),
+		ModuleVersion: metaData.ModuleVersion.ValueString(),
+	}
+	if product := m.UserAgentProduct(); product != "" {
+		return strings.Join([]string{currUserAgent, product}, " ")
 	}
 
 	return currUserAgent
@@ -0,0 +1,406 @@
+// Package mockgcp is an in-process fake of the GCP REST APIs, serving canned
+// resources with basic CRUD semantics, so that the logic of resources can be
+// unit tested without real projects.
+//
+// The requests of a service are served under the base path returned by
+// Server.BasePath, e.g. "http://127.0.0.1:1234/compute/v1/", to be set on the
+// matching *BasePath field of the provider config. Resources are stored by
+// their path, e.g. "compute/v1/projects/p/global/networks/n", and are created
+// by POST requests to their collections, read and listed by GET requests,
+// updated by PATCH, PUT and custom method requests, and deleted by DELETE
+// requests, following the conventions of their service, see service.
+//
+// Resources may also be loaded from fixtures with Put and LoadFixtures, and
+// responses may be canned with AddResponse, e.g. to inject errors.
+package mockgcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// globalCollection is the segment of the paths of the global compute
+// resources, e.g. "projects/p/global/networks/n", that isn't followed by an ID.
+const globalCollection = "global"
+
+// Request is a request served by the server.
+type Request struct {
+	Method string
+	// Path is the path of the request without its leading slash, e.g.
+	// "compute/v1/projects/p/global/networks".
+	Path  string
+	Query url.Values
+	Body  []byte
+}
+
+type cannedResponse struct {
+	status int
+	body   interface{}
+}
+
+// Server is a fake of the GCP REST APIs. It's an httptest.Server, to be
+// closed once done.
+type Server struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	resources map[string]map[string]interface{}
+	policies  map[string]map[string]interface{}
+	canned    map[string][]cannedResponse
+	requests  []Request
+	// operations and etags count the operations returned and the policies
+	// set, to name them.
+	operations int
+	etags      int
+}
+
+// NewServer starts and returns a server without resources.
+func NewServer() *Server {
+	s := &Server{
+		resources: make(map[string]map[string]interface{}),
+		policies:  make(map[string]map[string]interface{}),
+		canned:    make(map[string][]cannedResponse),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// BasePath returns the base path of the given service and version, e.g.
+// "compute" and "v1", ending with a slash like the base paths of the provider.
+func (s *Server) BasePath(service, version string) string {
+	return fmt.Sprintf("%s/%s/%s/", s.URL, service, version)
+}
+
+// Put stores the given resource at the given path, e.g.
+// "compute/v1/projects/p/global/networks/n", as is.
+func (s *Server) Put(path string, resource map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resources[strings.Trim(path, "/")] = copyResource(resource)
+}
+
+// Get returns a copy of the resource stored at the given path, and whether
+// there's one.
+func (s *Server) Get(path string) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	resource, ok := s.resources[strings.Trim(path, "/")]
+	if !ok {
+		return nil, false
+	}
+	return copyResource(resource), true
+}
+
+// LoadFixtures stores the resources of the JSON files of the given file
+// system at their paths without the ".json" extension, e.g. the network of
+// "compute/v1/projects/p/global/networks/n.json".
+func (s *Server) LoadFixtures(fsys fs.FS) error {
+	return fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || path.Ext(p) != ".json" {
+			return err
+		}
+		contents, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		var resource map[string]interface{}
+		if err := json.Unmarshal(contents, &resource); err != nil {
+			return fmt.Errorf("fixture %s is not a JSON object: %s", p, err)
+		}
+		s.Put(strings.TrimSuffix(p, ".json"), resource)
+		return nil
+	})
+}
+
+// AddResponse cans a response to the next request with the given method and
+// path, e.g. "compute/v1/projects/p/global/networks/n", served instead of the
+// stored resources. Responses canned for the same request are served once
+// each, in order. Bodies other than []byte are encoded to JSON.
+func (s *Server) AddResponse(method, path string, status int, body interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := method + " " + strings.Trim(path, "/")
+	s.canned[key] = append(s.canned[key], cannedResponse{status: status, body: body})
+}
+
+// Requests returns the requests served so far, in order.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "unable to read the request: %s", err)
+		return
+	}
+	p := strings.Trim(r.URL.Path, "/")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, Request{Method: r.Method, Path: p, Query: r.URL.Query(), Body: body})
+
+	key := r.Method + " " + p
+	if canned := s.canned[key]; len(canned) > 0 {
+		s.canned[key] = canned[1:]
+		writeResponse(w, canned[0].status, canned[0].body)
+		return
+	}
+
+	var fields map[string]interface{}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &fields); err != nil {
+			writeError(w, http.StatusBadRequest, "request body is not a JSON object: %s", err)
+			return
+		}
+	}
+
+	parts := strings.SplitN(p, "/", 3)
+	if len(parts) < 3 {
+		writeError(w, http.StatusNotFound, "%q is not under the base path of a service", p)
+		return
+	}
+	svc := services[parts[0]]
+	if svc == nil {
+		svc = defaultService
+	}
+
+	// Custom methods follow a colon, e.g. "projects/p/serviceAccounts/a:getIamPolicy",
+	// or a slash for compute, e.g. "projects/p/zones/z/instances/i/setLabels".
+	resourcePath, method, _ := strings.Cut(p, ":")
+	if method == "" && isCollection(resourcePath) {
+		dir, last := path.Split(resourcePath)
+		if _, ok := s.resources[strings.TrimSuffix(dir, "/")]; ok && isCustomMethod(last) {
+			resourcePath, method = strings.TrimSuffix(dir, "/"), last
+		}
+	}
+
+	switch {
+	case method != "":
+		s.customMethod(w, svc, resourcePath, method, fields)
+	case isCollection(resourcePath) && r.Method == http.MethodGet:
+		s.list(w, svc, resourcePath)
+	case isCollection(resourcePath) && r.Method == http.MethodPost:
+		s.create(w, svc, resourcePath, r.URL.Query(), fields)
+	case isCollection(resourcePath):
+		writeError(w, http.StatusMethodNotAllowed, "%s is not supported on collection %q", r.Method, resourcePath)
+	case r.Method == http.MethodGet:
+		if resource, ok := s.resources[resourcePath]; ok {
+			writeResponse(w, http.StatusOK, resource)
+		} else {
+			writeError(w, http.StatusNotFound, "%q was not found", resourcePath)
+		}
+	case r.Method == http.MethodPatch || r.Method == http.MethodPut:
+		s.update(w, svc, resourcePath, fields, r.Method == http.MethodPut, "update")
+	case r.Method == http.MethodDelete:
+		s.delete(w, svc, resourcePath)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "%s is not supported on %q", r.Method, resourcePath)
+	}
+}
+
+func (s *Server) list(w http.ResponseWriter, svc *service, collection string) {
+	var keys []string
+	for key := range s.resources {
+		if parent, _ := path.Split(key); parent == collection+"/" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	items := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		items = append(items, s.resources[key])
+	}
+	writeResponse(w, http.StatusOK, map[string]interface{}{svc.listField(path.Base(collection)): items})
+}
+
+func (s *Server) create(w http.ResponseWriter, svc *service, collection string, query url.Values, fields map[string]interface{}) {
+	id, resource := svc.newResource(collection, query, fields)
+	if id == "" {
+		writeError(w, http.StatusBadRequest, "the ID of the resource to create in %q is missing", collection)
+		return
+	}
+	key := collection + "/" + id
+	if _, ok := s.resources[key]; ok {
+		writeError(w, http.StatusConflict, "%q already exists", key)
+		return
+	}
+	svc.decorate(s.URL, key, resource)
+	s.resources[key] = resource
+	s.respond(w, svc, "insert", key, resource)
+}
+
+func (s *Server) update(w http.ResponseWriter, svc *service, key string, fields map[string]interface{}, replace bool, operationType string) {
+	resource, ok := s.resources[key]
+	if !ok {
+		writeError(w, http.StatusNotFound, "%q was not found", key)
+		return
+	}
+	if replace {
+		resource = make(map[string]interface{})
+	}
+	for k, v := range fields {
+		resource[k] = v
+	}
+	svc.decorate(s.URL, key, resource)
+	s.resources[key] = resource
+	s.respond(w, svc, operationType, key, resource)
+}
+
+func (s *Server) delete(w http.ResponseWriter, svc *service, key string) {
+	if _, ok := s.resources[key]; !ok {
+		writeError(w, http.StatusNotFound, "%q was not found", key)
+		return
+	}
+	delete(s.resources, key)
+	delete(s.policies, key)
+	switch {
+	case svc.operations:
+		s.respond(w, svc, "delete", key, nil)
+	case svc.emptyDelete:
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeResponse(w, http.StatusOK, map[string]interface{}{})
+	}
+}
+
+// customMethod serves the IAM methods of resources, and updates resources
+// with the fields of other custom methods, e.g. "setLabels".
+func (s *Server) customMethod(w http.ResponseWriter, svc *service, key, method string, fields map[string]interface{}) {
+	if _, ok := s.resources[key]; !ok {
+		writeError(w, http.StatusNotFound, "%q was not found", key)
+		return
+	}
+	switch method {
+	case "getIamPolicy":
+		writeResponse(w, http.StatusOK, s.policy(key))
+	case "setIamPolicy":
+		policy, _ := fields["policy"].(map[string]interface{})
+		if policy == nil {
+			writeError(w, http.StatusBadRequest, "the policy to set on %q is missing", key)
+			return
+		}
+		current := s.policy(key)
+		if etag, ok := policy["etag"].(string); ok && etag != "" && etag != current["etag"] {
+			writeError(w, http.StatusConflict, "the policy of %q was changed concurrently, its etag is %q", key, current["etag"])
+			return
+		}
+		policy = copyResource(policy)
+		s.etags++
+		policy["etag"] = fmt.Sprintf("BwX%d", s.etags)
+		s.policies[key] = policy
+		writeResponse(w, http.StatusOK, policy)
+	case "testIamPermissions":
+		writeResponse(w, http.StatusOK, map[string]interface{}{"permissions": fields["permissions"]})
+	default:
+		s.update(w, svc, key, fields, false, method)
+	}
+}
+
+// policy returns the IAM policy of the given resource, an empty policy if
+// none was set.
+func (s *Server) policy(key string) map[string]interface{} {
+	if policy, ok := s.policies[key]; ok {
+		return policy
+	}
+	return map[string]interface{}{"etag": "BwAAAAAAAAA=", "version": 1}
+}
+
+// respond writes the response of a change of the given resource, a done
+// operation for services returning operations.
+func (s *Server) respond(w http.ResponseWriter, svc *service, operationType, key string, resource map[string]interface{}) {
+	if !svc.operations {
+		writeResponse(w, http.StatusOK, resource)
+		return
+	}
+	s.operations++
+	op := computeOperation(s.URL, key, fmt.Sprintf("operation-%d", s.operations), operationType)
+	// Operations are stored, so that they may be polled.
+	s.resources[strings.TrimPrefix(op["selfLink"].(string), s.URL+"/")] = op
+	writeResponse(w, http.StatusOK, op)
+}
+
+// isCollection returns whether the given path, e.g.
+// "compute/v1/projects/p/global/networks", is the one of a collection rather
+// than a resource, its segments after the service and version alternating
+// between collections and IDs, besides the global segment of compute.
+func isCollection(p string) bool {
+	parts := strings.Split(p, "/")
+	n := 0
+	for _, part := range parts[2:] {
+		if part != globalCollection {
+			n++
+		}
+	}
+	return n%2 == 1
+}
+
+func isCustomMethod(name string) bool {
+	switch name {
+	case "getIamPolicy", "setIamPolicy", "testIamPermissions":
+		return true
+	}
+	// e.g. "setLabels", but not a "settings" collection.
+	return len(name) > 3 && strings.HasPrefix(name, "set") && unicode.IsUpper(rune(name[3]))
+}
+
+func copyResource(resource map[string]interface{}) map[string]interface{} {
+	c := make(map[string]interface{}, len(resource))
+	for k, v := range resource {
+		c[k] = v
+	}
+	return c
+}
+
+func writeResponse(w http.ResponseWriter, status int, body interface{}) {
+	if b, ok := body.([]byte); ok {
+		w.WriteHeader(status)
+		w.Write(b)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// writeError writes an error in the format of the GCP APIs, so that it's
+// parsed as a googleapi.Error.
+func writeError(w http.ResponseWriter, status int, format string, a ...interface{}) {
+	message := fmt.Sprintf(format, a...)
+	writeResponse(w, status, map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":    status,
+			"message": message,
+			"status":  errorStatuses[status],
+			"errors": []interface{}{
+				map[string]interface{}{"message": message, "reason": errorReasons[status]},
+			},
+		},
+	})
+}
+
+var errorStatuses = map[int]string{
+	http.StatusBadRequest:       "INVALID_ARGUMENT",
+	http.StatusNotFound:         "NOT_FOUND",
+	http.StatusConflict:         "ABORTED",
+	http.StatusMethodNotAllowed: "UNIMPLEMENTED",
+}
+
+var errorReasons = map[int]string{
+	http.StatusBadRequest:       "badRequest",
+	http.StatusNotFound:         "notFound",
+	http.StatusConflict:         "conflict",
+	http.StatusMethodNotAllowed: "methodNotAllowed",
+}
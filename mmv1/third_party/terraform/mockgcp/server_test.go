@@ -0,0 +1,236 @@
+package mockgcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"testing/fstest"
+)
+
+func do(t *testing.T, method, url string, body interface{}) (int, map[string]interface{}) {
+	t.Helper()
+	var reader *bytes.Reader
+	if body == nil {
+		reader = bytes.NewReader(nil)
+	} else {
+		b, err := json.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reader = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	var decoded map[string]interface{}
+	if res.StatusCode != http.StatusNoContent {
+		if err := json.NewDecoder(res.Body).Decode(&decoded); err != nil {
+			t.Fatalf("response to %s %s is not a JSON object: %s", method, url, err)
+		}
+	}
+	return res.StatusCode, decoded
+}
+
+func TestServer_compute(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	base := s.BasePath("compute", "v1")
+
+	status, op := do(t, "POST", base+"projects/p/zones/z/instances", map[string]interface{}{"name": "i", "machineType": "e2-small"})
+	if status != http.StatusOK || op["status"] != "DONE" || op["operationType"] != "insert" {
+		t.Fatalf("want a done insert operation, got %d %v", status, op)
+	}
+	if op["zone"] != base+"projects/p/zones/z" || op["targetLink"] != base+"projects/p/zones/z/instances/i" {
+		t.Errorf("want operation of the zonal instance, got %v", op)
+	}
+	if status, polled := do(t, "GET", op["selfLink"].(string), nil); status != http.StatusOK || polled["name"] != op["name"] {
+		t.Errorf("want the operation to be polled, got %d %v", status, polled)
+	}
+
+	if status, _ := do(t, "POST", base+"projects/p/zones/z/instances", map[string]interface{}{"name": "i"}); status != http.StatusConflict {
+		t.Errorf("want %d creating an existing instance, got %d", http.StatusConflict, status)
+	}
+
+	status, instance := do(t, "GET", base+"projects/p/zones/z/instances/i", nil)
+	if status != http.StatusOK || instance["machineType"] != "e2-small" || instance["selfLink"] != base+"projects/p/zones/z/instances/i" {
+		t.Errorf("want the created instance, got %d %v", status, instance)
+	}
+
+	if status, op := do(t, "POST", base+"projects/p/zones/z/instances/i/setLabels", map[string]interface{}{"labels": map[string]interface{}{"k": "v"}}); status != http.StatusOK || op["operationType"] != "setLabels" {
+		t.Errorf("want a done setLabels operation, got %d %v", status, op)
+	}
+	if _, instance := do(t, "GET", base+"projects/p/zones/z/instances/i", nil); instance["labels"] == nil {
+		t.Errorf("want the labels to be set, got %v", instance)
+	}
+
+	status, list := do(t, "GET", base+"projects/p/zones/z/instances", nil)
+	if items, _ := list["items"].([]interface{}); status != http.StatusOK || len(items) != 1 {
+		t.Errorf("want one instance listed, got %d %v", status, list)
+	}
+
+	if status, op := do(t, "DELETE", base+"projects/p/zones/z/instances/i", nil); status != http.StatusOK || op["operationType"] != "delete" {
+		t.Errorf("want a done delete operation, got %d %v", status, op)
+	}
+	status, body := do(t, "GET", base+"projects/p/zones/z/instances/i", nil)
+	if errorBody, _ := body["error"].(map[string]interface{}); status != http.StatusNotFound || errorBody["code"] != float64(http.StatusNotFound) {
+		t.Errorf("want %d reading a deleted instance, got %d %v", http.StatusNotFound, status, body)
+	}
+}
+
+func TestServer_computeGlobal(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	base := s.BasePath("compute", "v1")
+
+	_, op := do(t, "POST", base+"projects/p/global/networks", map[string]interface{}{"name": "n"})
+	if op["selfLink"] != base+"projects/p/global/operations/"+op["name"].(string) || op["zone"] != nil || op["region"] != nil {
+		t.Errorf("want a global operation, got %v", op)
+	}
+	if _, ok := s.Get("compute/v1/projects/p/global/networks/n"); !ok {
+		t.Errorf("want the network to be stored")
+	}
+	_, list := do(t, "GET", base+"projects/p/global/networks", nil)
+	if items, _ := list["items"].([]interface{}); len(items) != 1 {
+		t.Errorf("want one network listed, got %v", list)
+	}
+}
+
+func TestServer_storage(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	base := s.BasePath("storage", "v1")
+
+	status, bucket := do(t, "POST", base+"b?project=p", map[string]interface{}{"name": "b1", "location": "US"})
+	if status != http.StatusOK || bucket["id"] != "b1" || bucket["location"] != "US" {
+		t.Fatalf("want the created bucket, got %d %v", status, bucket)
+	}
+	if status, bucket := do(t, "PATCH", base+"b/b1", map[string]interface{}{"storageClass": "COLDLINE"}); status != http.StatusOK || bucket["storageClass"] != "COLDLINE" || bucket["location"] != "US" {
+		t.Errorf("want the patched bucket, got %d %v", status, bucket)
+	}
+	if status, _ := do(t, "DELETE", base+"b/b1", nil); status != http.StatusNoContent {
+		t.Errorf("want %d deleting a bucket, got %d", http.StatusNoContent, status)
+	}
+	_, list := do(t, "GET", base+"b?project=p", nil)
+	if items, ok := list["items"].([]interface{}); !ok || len(items) != 0 {
+		t.Errorf("want no buckets listed, got %v", list)
+	}
+}
+
+func TestServer_iam(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	base := s.BasePath("iam", "v1")
+
+	status, account := do(t, "POST", base+"projects/p/serviceAccounts", map[string]interface{}{
+		"accountId":      "a",
+		"serviceAccount": map[string]interface{}{"displayName": "A"},
+	})
+	email := "a@p.iam.gserviceaccount.com"
+	if status != http.StatusOK || account["email"] != email || account["name"] != "projects/p/serviceAccounts/"+email || account["displayName"] != "A" {
+		t.Fatalf("want the created service account, got %d %v", status, account)
+	}
+	_, list := do(t, "GET", base+"projects/p/serviceAccounts", nil)
+	if accounts, _ := list["accounts"].([]interface{}); len(accounts) != 1 {
+		t.Errorf("want one service account listed, got %v", list)
+	}
+
+	status, role := do(t, "POST", base+"projects/p/roles", map[string]interface{}{
+		"roleId": "r",
+		"role":   map[string]interface{}{"title": "R"},
+	})
+	if status != http.StatusOK || role["name"] != "projects/p/roles/r" || role["title"] != "R" {
+		t.Errorf("want the created role, got %d %v", status, role)
+	}
+
+	resource := base + "projects/p/serviceAccounts/" + email
+	_, policy := do(t, "POST", resource+":getIamPolicy", nil)
+	etag := policy["etag"]
+	binding := map[string]interface{}{"role": "roles/iam.serviceAccountUser", "members": []interface{}{"user:u@example.com"}}
+	status, policy = do(t, "POST", resource+":setIamPolicy", map[string]interface{}{
+		"policy": map[string]interface{}{"etag": etag, "bindings": []interface{}{binding}},
+	})
+	if status != http.StatusOK || policy["etag"] == etag {
+		t.Fatalf("want the policy set with a new etag, got %d %v", status, policy)
+	}
+	// The etag read first is stale now.
+	status, _ = do(t, "POST", resource+":setIamPolicy", map[string]interface{}{
+		"policy": map[string]interface{}{"etag": etag},
+	})
+	if status != http.StatusConflict {
+		t.Errorf("want %d setting a policy with a stale etag, got %d", http.StatusConflict, status)
+	}
+	if _, read := do(t, "POST", resource+":getIamPolicy", nil); read["etag"] != policy["etag"] {
+		t.Errorf("want the policy set, got %v", read)
+	}
+
+	if status, body := do(t, "DELETE", resource, nil); status != http.StatusOK || len(body) != 0 {
+		t.Errorf("want an empty response deleting a service account, got %d %v", status, body)
+	}
+}
+
+func TestServer_defaultService(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	base := s.BasePath("redis", "v1")
+
+	status, instance := do(t, "POST", base+"projects/p/locations/l/instances?instanceId=i", map[string]interface{}{"tier": "BASIC"})
+	if status != http.StatusOK || instance["name"] != "projects/p/locations/l/instances/i" || instance["tier"] != "BASIC" {
+		t.Fatalf("want the created instance, got %d %v", status, instance)
+	}
+	if status, _ := do(t, "POST", base+"projects/p/locations/l/instances", map[string]interface{}{"tier": "BASIC"}); status != http.StatusBadRequest {
+		t.Errorf("want %d creating an instance without ID, got %d", http.StatusBadRequest, status)
+	}
+	if status, instance := do(t, "PUT", base+"projects/p/locations/l/instances/i", map[string]interface{}{"memorySizeGb": 2}); status != http.StatusOK || instance["tier"] != nil || instance["name"] != "projects/p/locations/l/instances/i" {
+		t.Errorf("want the replaced instance, got %d %v", status, instance)
+	}
+}
+
+func TestServer_fixturesAndCannedResponses(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	fixtures := fstest.MapFS{
+		"compute/v1/projects/p/global/networks/n.json": {Data: []byte(`{"name": "n", "autoCreateSubnetworks": false}`)},
+		"README.md": {Data: []byte("not a fixture")},
+	}
+	if err := s.LoadFixtures(fixtures); err != nil {
+		t.Fatal(err)
+	}
+	url := s.BasePath("compute", "v1") + "projects/p/global/networks/n"
+
+	s.AddResponse("GET", "compute/v1/projects/p/global/networks/n", http.StatusTooManyRequests, map[string]interface{}{"error": map[string]interface{}{"code": 429}})
+	if status, _ := do(t, "GET", url, nil); status != http.StatusTooManyRequests {
+		t.Errorf("want the canned response, got %d", status)
+	}
+	if status, network := do(t, "GET", url, nil); status != http.StatusOK || network["autoCreateSubnetworks"] != false {
+		t.Errorf("want the network of the fixture once the canned response is served, got %d %v", status, network)
+	}
+
+	requests := s.Requests()
+	if len(requests) != 2 || requests[0].Method != "GET" || requests[0].Path != "compute/v1/projects/p/global/networks/n" {
+		t.Errorf("want the requests recorded, got %v", requests)
+	}
+}
+
+func TestIsCollection(t *testing.T) {
+	cases := map[string]bool{
+		"compute/v1/projects/p/global/networks":     true,
+		"compute/v1/projects/p/global/networks/n":   false,
+		"compute/v1/projects/p/zones/z/instances":   true,
+		"compute/v1/projects/p/zones/z/instances/i": false,
+		"storage/v1/b":    true,
+		"storage/v1/b/b1": false,
+		"iam/v1/projects/p/serviceAccounts/a@p.test": false,
+	}
+	for p, want := range cases {
+		if got := isCollection(p); got != want {
+			t.Errorf("isCollection(%q) = %t, want %t", p, got, want)
+		}
+	}
+}
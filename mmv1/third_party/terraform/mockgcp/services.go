@@ -0,0 +1,159 @@
+package mockgcp
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// service holds the conventions of the API of a GCP service.
+type service struct {
+	// operations is whether changes return operations, done right away, rather
+	// than the changed resources.
+	operations bool
+	// emptyDelete is whether deletions return no content rather than an empty
+	// object, for services not returning operations.
+	emptyDelete bool
+	// listFields are the fields of the list responses holding the resources of
+	// a collection, by collection. The name of the collection by default.
+	listFields map[string]string
+	// defaultListField is the field of the list responses of the collections
+	// without listFields, their name if empty.
+	defaultListField string
+	// newResource returns the ID and the resource created in the given
+	// collection, e.g. "compute/v1/projects/p/global/networks", by a POST
+	// request with the given query and fields.
+	newResource func(collection string, query url.Values, fields map[string]interface{}) (string, map[string]interface{})
+	// decorate sets the output only fields of the given resource stored at the
+	// given path, served by a server at the given URL.
+	decorate func(serverURL, key string, resource map[string]interface{})
+}
+
+func (svc *service) listField(collection string) string {
+	if field, ok := svc.listFields[collection]; ok {
+		return field
+	}
+	if svc.defaultListField != "" {
+		return svc.defaultListField
+	}
+	return collection
+}
+
+// computeOperation returns a done compute operation of the given type on the
+// resource stored at the given path, located in the scope of the resource,
+// e.g. "compute/v1/projects/p/zones/z" for the zonal operations.
+func computeOperation(serverURL, key, name, operationType string) map[string]interface{} {
+	scope := path.Dir(path.Dir(key))
+	op := map[string]interface{}{
+		"kind":          "compute#operation",
+		"name":          name,
+		"operationType": operationType,
+		"status":        "DONE",
+		"progress":      100,
+		"targetLink":    serverURL + "/" + key,
+		"selfLink":      fmt.Sprintf("%s/%s/operations/%s", serverURL, scope, name),
+	}
+	switch path.Base(path.Dir(scope)) {
+	case "zones":
+		op["zone"] = serverURL + "/" + scope
+	case "regions":
+		op["region"] = serverURL + "/" + scope
+	}
+	return op
+}
+
+// services are the services with conventions of their own, by the first
+// segment of their base path.
+var services = map[string]*service{
+	"compute": {
+		operations:       true,
+		defaultListField: "items",
+		newResource:      nameFromFields,
+		decorate: func(serverURL, key string, resource map[string]interface{}) {
+			resource["selfLink"] = serverURL + "/" + key
+			if _, ok := resource["creationTimestamp"]; !ok {
+				resource["creationTimestamp"] = time.Now().Format(time.RFC3339)
+			}
+		},
+	},
+	"storage": {
+		emptyDelete:      true,
+		defaultListField: "items",
+		newResource:      nameFromFields,
+		decorate: func(serverURL, key string, resource map[string]interface{}) {
+			resource["selfLink"] = serverURL + "/" + key
+			resource["id"] = path.Base(key)
+			if _, ok := resource["metageneration"]; !ok {
+				resource["metageneration"] = "1"
+			}
+		},
+	},
+	"iam": {
+		listFields: map[string]string{
+			"serviceAccounts": "accounts",
+		},
+		newResource: func(collection string, query url.Values, fields map[string]interface{}) (string, map[string]interface{}) {
+			if path.Base(collection) != "serviceAccounts" {
+				return idFromFields(collection, query, fields)
+			}
+			accountID, _ := fields["accountId"].(string)
+			if accountID == "" {
+				return "", nil
+			}
+			account, _ := fields["serviceAccount"].(map[string]interface{})
+			account = copyResource(account)
+			project := path.Base(path.Dir(collection))
+			account["email"] = fmt.Sprintf("%s@%s.iam.gserviceaccount.com", accountID, project)
+			account["projectId"] = project
+			return account["email"].(string), account
+		},
+		decorate: func(serverURL, key string, resource map[string]interface{}) {
+			resource["name"] = relativeName(key)
+		},
+	},
+}
+
+// defaultService holds the conventions of resource oriented APIs, whose
+// resources are created with the ID of their query parameter, e.g. "instanceId",
+// or field, e.g. "roleId" and "role" for the IAM roles, and are named by their
+// relative name, e.g. "projects/p/locations/l/instances/i". Changes return the
+// changed resources rather than long-running operations.
+var defaultService = &service{
+	newResource: idFromFields,
+	decorate: func(serverURL, key string, resource map[string]interface{}) {
+		resource["name"] = relativeName(key)
+	},
+}
+
+// nameFromFields returns the name field of the resource as its ID.
+func nameFromFields(collection string, query url.Values, fields map[string]interface{}) (string, map[string]interface{}) {
+	name, _ := fields["name"].(string)
+	return name, copyResource(fields)
+}
+
+// idFromFields returns the ID of the resource from the query parameter of its
+// singular collection, e.g. "instanceId", or from its field along with the
+// resource, e.g. "roleId" and "role".
+func idFromFields(collection string, query url.Values, fields map[string]interface{}) (string, map[string]interface{}) {
+	singular := path.Base(collection)
+	if strings.HasSuffix(singular, "ies") {
+		singular = strings.TrimSuffix(singular, "ies") + "y"
+	} else {
+		singular = strings.TrimSuffix(singular, "s")
+	}
+	if id := query.Get(singular + "Id"); id != "" {
+		return id, copyResource(fields)
+	}
+	id, _ := fields[singular+"Id"].(string)
+	resource, _ := fields[singular].(map[string]interface{})
+	return id, copyResource(resource)
+}
+
+// relativeName returns the relative name of the resource stored at the given
+// path, without its service and version.
+func relativeName(key string) string {
+	parts := strings.SplitN(key, "/", 3)
+	return parts[len(parts)-1]
+}
@@ -0,0 +1,45 @@
+package provider
+
+import (
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// ResourceAllowlistEnvVar is the environment variable of the comma-separated
+// list of the resources and datasources of the provider, e.g.
+// "google_compute_*,google_storage_bucket", for users managing a few services
+// to only load the schemas of their resources, see filterResourceMap.
+const ResourceAllowlistEnvVar = "GOOGLE_RESOURCE_ALLOWLIST"
+
+// resourceAllowlist returns the entries of GOOGLE_RESOURCE_ALLOWLIST, or nil
+// if it's unset to allow all resources.
+func resourceAllowlist() []string {
+	var allowlist []string
+	for _, entry := range strings.Split(os.Getenv(ResourceAllowlistEnvVar), ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			allowlist = append(allowlist, entry)
+		}
+	}
+	return allowlist
+}
+
+// filterResourceMap returns the resources of the given map allowed by the
+// given allowlist, whose entries are either the name of a resource or a prefix
+// of names followed by "*", or all of them for an empty allowlist.
+func filterResourceMap(resources map[string]*schema.Resource, allowlist []string) map[string]*schema.Resource {
+	if len(allowlist) == 0 {
+		return resources
+	}
+	filtered := make(map[string]*schema.Resource)
+	for name, resource := range resources {
+		for _, entry := range allowlist {
+			if prefix, ok := strings.CutSuffix(entry, "*"); (ok && strings.HasPrefix(name, prefix)) || name == entry {
+				filtered[name] = resource
+				break
+			}
+		}
+	}
+	return filtered
+}
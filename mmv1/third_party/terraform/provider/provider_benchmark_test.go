@@ -0,0 +1,52 @@
+package provider_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	"github.com/hashicorp/terraform-provider-google/google/provider"
+)
+
+// The benchmarks measure the startup of the provider, run on every Terraform
+// command. scripts/run_startup_bench.sh compares them with the latest release.
+
+func BenchmarkProvider(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		provider.Provider()
+	}
+}
+
+func BenchmarkProvider_InternalValidate(b *testing.B) {
+	p := provider.Provider()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := p.InternalValidate(); err != nil {
+			b.Fatalf("provider schema is not valid: %s", err)
+		}
+	}
+}
+
+func BenchmarkProvider_Configure(b *testing.B) {
+	p := provider.Provider()
+	// An access token is configured so that no credentials are looked up.
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"access_token": "foo",
+		"project":      "my-project-id",
+		"region":       "us-central1",
+	})
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// The batchers of each configuration run until its context is done.
+		ctx, cancel := context.WithCancel(context.Background())
+		diags := p.Configure(ctx, config)
+		cancel()
+		if diags.HasError() {
+			b.Fatalf("configuring the provider failed: %v", diags)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-provider-google/google/acctest"
 	"github.com/hashicorp/terraform-provider-google/google/provider"
@@ -1763,3 +1764,109 @@ func TestProvider_ProviderConfigure_batching(t *testing.T) {
 		})
 	}
 }
+
+func TestProvider_ProviderConfigure_batchers(t *testing.T) {
+	cases := map[string]struct {
+		ConfigValues                  map[string]interface{}
+		ExpectError                   bool
+		ExpectedIamEnableBatching     bool
+		ExpectedIamSendAfter          time.Duration
+		ExpectedServiceUsageBatching  bool
+		ExpectedServiceUsageSendAfter time.Duration
+	}{
+		"batchers use the batching block by default": {
+			ConfigValues: map[string]interface{}{
+				"credentials": transport_tpg.TestFakeCredentialsPath,
+				"batching": []interface{}{
+					map[string]interface{}{
+						"enable_batching": true,
+						"send_after":      "45s",
+					},
+				},
+			},
+			ExpectedIamEnableBatching:     true,
+			ExpectedIamSendAfter:          45 * time.Second,
+			ExpectedServiceUsageBatching:  true,
+			ExpectedServiceUsageSendAfter: 45 * time.Second,
+		},
+		"batcher blocks override the batching block": {
+			ConfigValues: map[string]interface{}{
+				"credentials": transport_tpg.TestFakeCredentialsPath,
+				"batching": []interface{}{
+					map[string]interface{}{
+						"enable_batching": true,
+						"send_after":      "45s",
+						"iam": []interface{}{
+							map[string]interface{}{
+								"enable_batching": false,
+							},
+						},
+						"service_usage": []interface{}{
+							map[string]interface{}{
+								"send_after": "5s",
+							},
+						},
+					},
+				},
+			},
+			ExpectedIamEnableBatching:     false,
+			ExpectedIamSendAfter:          45 * time.Second,
+			ExpectedServiceUsageBatching:  true,
+			ExpectedServiceUsageSendAfter: 5 * time.Second,
+		},
+		"if a batcher block is configured with send_after as an invalid value, there's an error": {
+			ConfigValues: map[string]interface{}{
+				"credentials": transport_tpg.TestFakeCredentialsPath,
+				"batching": []interface{}{
+					map[string]interface{}{
+						"iam": []interface{}{
+							map[string]interface{}{
+								"send_after": "invalid value",
+							},
+						},
+					},
+				},
+			},
+			ExpectError: true,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+
+			// Arrange
+			ctx := context.Background()
+			acctest.UnsetTestProviderConfigEnvs(t)
+			p := provider.Provider()
+			d := tpgresource.SetupTestResourceDataFromConfigMap(t, p.Schema, tc.ConfigValues)
+
+			// Act
+			c, diags := provider.ProviderConfigure(ctx, d, p)
+
+			// Assert
+			if diags.HasError() && !tc.ExpectError {
+				t.Fatalf("unexpected error(s): %#v", diags)
+			}
+			if !diags.HasError() && tc.ExpectError {
+				t.Fatal("expected error(s) but got none")
+			}
+			if tc.ExpectError {
+				return
+			}
+
+			config := c.(*transport_tpg.Config)
+			if config.IamBatchingConfig.EnableBatching != tc.ExpectedIamEnableBatching {
+				t.Fatalf("expected IAM enable_batching to be %v, got %v", tc.ExpectedIamEnableBatching, config.IamBatchingConfig.EnableBatching)
+			}
+			if config.IamBatchingConfig.SendAfter != tc.ExpectedIamSendAfter {
+				t.Fatalf("expected IAM send_after to be %s, got %s", tc.ExpectedIamSendAfter, config.IamBatchingConfig.SendAfter)
+			}
+			if config.ServiceUsageBatchingConfig.EnableBatching != tc.ExpectedServiceUsageBatching {
+				t.Fatalf("expected Service Usage enable_batching to be %v, got %v", tc.ExpectedServiceUsageBatching, config.ServiceUsageBatchingConfig.EnableBatching)
+			}
+			if config.ServiceUsageBatchingConfig.SendAfter != tc.ExpectedServiceUsageSendAfter {
+				t.Fatalf("expected Service Usage send_after to be %s, got %s", tc.ExpectedServiceUsageSendAfter, config.ServiceUsageBatchingConfig.SendAfter)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"sort"
+)
+
+// Sources of the resources and datasources of a Manifest, i.e. how they're
+// implemented.
+const (
+	// ManifestSourceMMv1 is the source of the resources generated by Magic
+	// Modules from their YAML definitions, including their IAM resources.
+	ManifestSourceMMv1 = "mmv1"
+	// ManifestSourceHandwritten is the source of the handwritten resources.
+	ManifestSourceHandwritten = "handwritten"
+	// ManifestSourceDCL is the source of the resources generated by tpgtools
+	// from the DCL.
+	ManifestSourceDCL = "dcl"
+	// ManifestSourceRegistered is the source of the resources added with
+	// RegisterResource and RegisterDatasource.
+	ManifestSourceRegistered = "registered"
+)
+
+// Manifest lists the resources and datasources of the provider, sorted by
+// name, for tools to introspect the provider without building its schemas
+// themselves.
+type Manifest struct {
+	Resources   []ManifestEntry `json:"resources"`
+	Datasources []ManifestEntry `json:"datasources"`
+}
+
+// ManifestEntry describes a resource or datasource of a Manifest.
+type ManifestEntry struct {
+	Name          string `json:"name"`
+	SchemaVersion int    `json:"schema_version"`
+	// Source is how the resource is implemented, e.g. ManifestSourceMMv1.
+	Source string `json:"source"`
+}
+
+// ProviderManifest returns the manifest of the resources and datasources of
+// the provider, including the registered ones, regardless of
+// GOOGLE_RESOURCE_ALLOWLIST. Resources listed several times are reported with
+// the source of the one served by the provider, see ResourceMapWithErrors.
+func ProviderManifest() Manifest {
+	return Manifest{
+		Resources:   manifestEntries(resourceMapSources()),
+		Datasources: manifestEntries(datasourceMapSources()),
+	}
+}
+
+func manifestEntries(sources []resourceMapSource) []ManifestEntry {
+	byName := make(map[string]ManifestEntry)
+	for _, source := range sources {
+		for name, resource := range source.resources {
+			byName[name] = ManifestEntry{
+				Name:          name,
+				SchemaVersion: resource.SchemaVersion,
+				Source:        manifestSource(source.name),
+			}
+		}
+	}
+
+	entries := make([]ManifestEntry, 0, len(byName))
+	for _, entry := range byName {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+	return entries
+}
+
+// manifestSource returns the manifest source of the given resourceMapSource
+// name.
+func manifestSource(name string) string {
+	switch name {
+	case "generated", "generated IAM":
+		return ManifestSourceMMv1
+	case "DCL":
+		return ManifestSourceDCL
+	case "registered":
+		return ManifestSourceRegistered
+	default:
+		return ManifestSourceHandwritten
+	}
+}
@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+)
+
+// withModuleAttribution wraps the create functions of the given resources to
+// record the resources created by each module with
+// transport_tpg.RecordModuleAttribution. Resources listed several times are
+// wrapped once.
+func withModuleAttribution(sources []resourceMapSource) {
+	wrapped := make(map[*schema.Resource]bool)
+	for _, source := range sources {
+		for name, resource := range source.resources {
+			if wrapped[resource] {
+				continue
+			}
+			wrapped[resource] = true
+			wrapCreateWithModuleAttribution(name, resource)
+		}
+	}
+}
+
+func wrapCreateWithModuleAttribution(name string, resource *schema.Resource) {
+	switch {
+	case resource.CreateContext != nil:
+		create := resource.CreateContext
+		resource.CreateContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			diags := create(ctx, d, meta)
+			if !diags.HasError() {
+				recordModuleAttribution(name, d)
+			}
+			return diags
+		}
+	case resource.CreateWithoutTimeout != nil:
+		create := resource.CreateWithoutTimeout
+		resource.CreateWithoutTimeout = func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			diags := create(ctx, d, meta)
+			if !diags.HasError() {
+				recordModuleAttribution(name, d)
+			}
+			return diags
+		}
+	case resource.Create != nil:
+		create := resource.Create
+		resource.Create = func(d *schema.ResourceData, meta interface{}) error {
+			if err := create(d, meta); err != nil {
+				return err
+			}
+			recordModuleAttribution(name, d)
+			return nil
+		}
+	}
+}
+
+func recordModuleAttribution(name string, d *schema.ResourceData) {
+	if d.Id() == "" || !transport_tpg.ModuleAttributionEnabled() {
+		return
+	}
+	var m transport_tpg.ProviderMeta
+	if err := d.GetProviderMeta(&m); err != nil {
+		log.Printf("[WARN] Unable to read the provider_meta of %s %q: %s", name, d.Id(), err)
+		return
+	}
+	transport_tpg.RecordModuleAttribution(m, name, d.Id())
+}
@@ -0,0 +1,46 @@
+package provider
+
+import (
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var (
+	registeredMu          sync.Mutex
+	registeredResources   = make(map[string]*schema.Resource)
+	registeredDatasources = make(map[string]*schema.Resource)
+)
+
+// RegisterResource adds a resource to the ones of the providers returned by
+// Provider, so that builds of the provider can add resources, e.g. private
+// ones, without changing the lists of this package. It must be called before
+// Provider, typically from an init function, as the schemas of the resources
+// are read when the provider is created. Registered resources override the
+// ones of this package with the same name, which ResourceMapWithErrors reports
+// as duplicates.
+func RegisterResource(name string, resource *schema.Resource) {
+	registeredMu.Lock()
+	defer registeredMu.Unlock()
+	registeredResources[name] = resource
+}
+
+// RegisterDatasource is RegisterResource for datasources, reported by
+// DatasourceMapWithErrors.
+func RegisterDatasource(name string, datasource *schema.Resource) {
+	registeredMu.Lock()
+	defer registeredMu.Unlock()
+	registeredDatasources[name] = datasource
+}
+
+// registeredResourceMapSource returns a copy of the given registered resources
+// or datasources to merge with the ones of this package.
+func registeredResourceMapSource(registered map[string]*schema.Resource) resourceMapSource {
+	registeredMu.Lock()
+	defer registeredMu.Unlock()
+	resources := make(map[string]*schema.Resource, len(registered))
+	for name, resource := range registered {
+		resources[name] = resource
+	}
+	return resourceMapSource{"registered", resources}
+}
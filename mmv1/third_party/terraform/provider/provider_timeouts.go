@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// TimeoutMultiplierEnvVar is the environment variable of the factor applied to
+// the default timeouts of the resources, e.g. "2" for slow organizations, see
+// scaleResourceTimeouts. Timeouts set in the timeouts block of a resource
+// aren't scaled.
+const TimeoutMultiplierEnvVar = "GOOGLE_TIMEOUT_MULTIPLIER"
+
+// timeoutMultiplier returns the value of GOOGLE_TIMEOUT_MULTIPLIER, or 1 if
+// it's unset or invalid.
+func timeoutMultiplier() float64 {
+	v := os.Getenv(TimeoutMultiplierEnvVar)
+	if v == "" {
+		return 1
+	}
+	multiplier, err := strconv.ParseFloat(v, 64)
+	if err != nil || multiplier <= 0 {
+		log.Printf("[WARN] Ignoring %s, it must be a positive number, got %q", TimeoutMultiplierEnvVar, v)
+		return 1
+	}
+	return multiplier
+}
+
+// scaleResourceTimeouts returns the resources of the given map with their
+// default timeouts multiplied by the given multiplier. The default timeouts
+// are set by the schemas of the resources before the provider is configured,
+// so they're scaled when the schemas are built rather than with a provider
+// argument. The resources are copied, as the schemas of the maps are shared.
+func scaleResourceTimeouts(resources map[string]*schema.Resource, multiplier float64) map[string]*schema.Resource {
+	if multiplier == 1 {
+		return resources
+	}
+	scaled := make(map[string]*schema.Resource, len(resources))
+	for name, resource := range resources {
+		if resource.Timeouts == nil {
+			scaled[name] = resource
+			continue
+		}
+		timeouts := *resource.Timeouts
+		for _, timeout := range []**time.Duration{&timeouts.Create, &timeouts.Read, &timeouts.Update, &timeouts.Delete, &timeouts.Default} {
+			if *timeout != nil {
+				*timeout = schema.DefaultTimeout(time.Duration(float64(**timeout) * multiplier))
+			}
+		}
+		r := *resource
+		r.Timeouts = &timeouts
+		scaled[name] = &r
+	}
+	return scaled
+}
@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// InternalValidateConcurrently is schema.Provider.InternalValidate, validating
+// the resources and datasources of the provider concurrently rather than one
+// after the other, which takes a while for the thousand or so of them. The
+// errors are reported in the order of the resource and datasource names.
+func InternalValidateConcurrently(p *schema.Provider) error {
+	// The provider level checks, without any resource or datasource.
+	var validationErrors error
+	if err := (&schema.Provider{Schema: p.Schema, ProviderMetaSchema: p.ProviderMetaSchema}).InternalValidate(); err != nil {
+		validationErrors = multierror.Append(validationErrors, err)
+	}
+
+	type validation struct {
+		name     string
+		resource *schema.Resource
+		writable bool
+		err      error
+	}
+	var validations []*validation
+	for _, name := range sortedResourceNames(p.ResourcesMap) {
+		validations = append(validations, &validation{name: "resource " + name, resource: p.ResourcesMap[name], writable: true})
+	}
+	for _, name := range sortedResourceNames(p.DataSourcesMap) {
+		validations = append(validations, &validation{name: "data source " + name, resource: p.DataSourcesMap[name]})
+	}
+
+	queue := make(chan *validation)
+	var wg sync.WaitGroup
+	for i := 0; i < runtime.GOMAXPROCS(0); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for v := range queue {
+				v.err = v.resource.InternalValidate(nil, v.writable)
+			}
+		}()
+	}
+	for _, v := range validations {
+		queue <- v
+	}
+	close(queue)
+	wg.Wait()
+
+	for _, v := range validations {
+		if v.err != nil {
+			validationErrors = multierror.Append(validationErrors, fmt.Errorf("%s: %s", v.name, v.err))
+		}
+	}
+	return validationErrors
+}
+
+func sortedResourceNames(resources map[string]*schema.Resource) []string {
+	names := make([]string, 0, len(resources))
+	for name := range resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,176 @@
+// Package resourcename parses the names of GCP resources, whether self links,
+// e.g. "https://www.googleapis.com/compute/v1/projects/p/zones/z/instances/i",
+// full resource names, e.g. "//compute.googleapis.com/projects/p/zones/z/instances/i",
+// or relative resource names, e.g. "projects/p/zones/z/instances/i".
+//
+// It has no dependencies on the rest of the provider, so that tools built
+// around it, such as cai2hcl, can parse names the same way.
+package resourcename
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// GlobalCollection is the segment of the names of the global compute
+// resources, e.g. "projects/p/global/networks/n", that isn't followed by an ID.
+const GlobalCollection = "global"
+
+// rootCollections are the collections resource names start with, after the
+// service and version of self links.
+var rootCollections = map[string]bool{
+	"projects":        true,
+	"organizations":   true,
+	"folders":         true,
+	"billingAccounts": true,
+}
+
+// Segment is a collection and the ID of a resource in it, e.g. "zones" and
+// "us-central1-a".
+type Segment struct {
+	Collection string
+	// ID is empty for GlobalCollection.
+	ID string
+}
+
+// ResourceName is a parsed resource name.
+type ResourceName struct {
+	// Host of the self link, e.g. "www.googleapis.com", or service of the full
+	// resource name, e.g. "compute.googleapis.com". Empty for relative names.
+	Host string
+	// Segments of the relative name, from its root collection on.
+	Segments []Segment
+}
+
+// Parse parses a self link, full resource name or relative resource name.
+// The service and version of self links, e.g. "compute/v1", are dropped.
+func Parse(name string) (ResourceName, error) {
+	var parsed ResourceName
+	path := name
+	if strings.HasPrefix(name, "//") {
+		parsed.Host, path, _ = strings.Cut(strings.TrimPrefix(name, "//"), "/")
+	} else if strings.Contains(name, "://") {
+		u, err := url.Parse(name)
+		if err != nil {
+			return ResourceName{}, err
+		}
+		parsed.Host, path = u.Host, u.Path
+	}
+
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	start := -1
+	for i, part := range parts {
+		if rootCollections[part] {
+			start = i
+			break
+		}
+	}
+	if start == -1 {
+		// Relative names of resources outside of the root collections, e.g.
+		// "zones/z/machineTypes/m", are parsed from their start.
+		if parsed.Host != "" {
+			return ResourceName{}, fmt.Errorf("%q is not a resource name", name)
+		}
+		start = 0
+	}
+
+	for i := start; i < len(parts); i += 2 {
+		if parts[i] == GlobalCollection {
+			parsed.Segments = append(parsed.Segments, Segment{Collection: GlobalCollection})
+			i--
+			continue
+		}
+		if parts[i] == "" || i+1 >= len(parts) || parts[i+1] == "" {
+			return ResourceName{}, fmt.Errorf("%q is not a resource name", name)
+		}
+		parsed.Segments = append(parsed.Segments, Segment{Collection: parts[i], ID: parts[i+1]})
+	}
+	if len(parsed.Segments) == 0 || parsed.Segments[len(parsed.Segments)-1].ID == "" {
+		return ResourceName{}, fmt.Errorf("%q is not a resource name", name)
+	}
+	return parsed, nil
+}
+
+// Field returns the ID of the given collection in the name, e.g. the zone of
+// "zones", or "" if it's not in the name.
+func (n ResourceName) Field(collection string) string {
+	for _, segment := range n.Segments {
+		if segment.Collection == collection {
+			return segment.ID
+		}
+	}
+	return ""
+}
+
+// Project returns the project of the name, or "" if it's not in a project.
+func (n ResourceName) Project() string {
+	return n.Field("projects")
+}
+
+// Zone returns the zone of the name, or "" if it's not zonal.
+func (n ResourceName) Zone() string {
+	return n.Field("zones")
+}
+
+// Region returns the region of the name, from its "regions" or "locations"
+// collection, or "" if it has neither.
+func (n ResourceName) Region() string {
+	if region := n.Field("regions"); region != "" {
+		return region
+	}
+	return n.Field("locations")
+}
+
+// Location returns the zone, region or location of the name, GlobalCollection
+// for the global compute resources, or "" if it has none of them.
+func (n ResourceName) Location() string {
+	if zone := n.Zone(); zone != "" {
+		return zone
+	}
+	if region := n.Region(); region != "" {
+		return region
+	}
+	for _, segment := range n.Segments {
+		if segment.Collection == GlobalCollection {
+			return GlobalCollection
+		}
+	}
+	return ""
+}
+
+// Collection returns the collection of the named resource, e.g. "instances".
+func (n ResourceName) Collection() string {
+	return n.Segments[len(n.Segments)-1].Collection
+}
+
+// Name returns the ID of the named resource, e.g. the instance name.
+func (n ResourceName) Name() string {
+	return n.Segments[len(n.Segments)-1].ID
+}
+
+// RelativeName returns the relative resource name, e.g.
+// "projects/p/zones/z/instances/i".
+func (n ResourceName) RelativeName() string {
+	parts := make([]string, 0, 2*len(n.Segments))
+	for _, segment := range n.Segments {
+		parts = append(parts, segment.Collection)
+		if segment.ID != "" {
+			parts = append(parts, segment.ID)
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// FieldValue returns the part of the given name following the given
+// collection, e.g. the zone following "zones", or "" if there's none. Unlike
+// Parse, it accepts partial and malformed names.
+func FieldValue(name, collection string) string {
+	parts := strings.Split(name, "/")
+	for i, part := range parts {
+		if part == collection && i+1 < len(parts) {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
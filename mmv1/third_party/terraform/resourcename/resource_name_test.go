@@ -0,0 +1,112 @@
+package resourcename_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-provider-google/google/resourcename"
+)
+
+func TestParse(t *testing.T) {
+	cases := map[string]struct {
+		Name         string
+		Host         string
+		Project      string
+		Location     string
+		Collection   string
+		ResourceName string
+		RelativeName string
+	}{
+		"self link": {
+			Name:         "https://www.googleapis.com/compute/v1/projects/my-project/zones/us-central1-a/instances/my-instance",
+			Host:         "www.googleapis.com",
+			Project:      "my-project",
+			Location:     "us-central1-a",
+			Collection:   "instances",
+			ResourceName: "my-instance",
+			RelativeName: "projects/my-project/zones/us-central1-a/instances/my-instance",
+		},
+		"full resource name": {
+			Name:         "//compute.googleapis.com/projects/my-project/regions/us-central1/subnetworks/my-subnetwork",
+			Host:         "compute.googleapis.com",
+			Project:      "my-project",
+			Location:     "us-central1",
+			Collection:   "subnetworks",
+			ResourceName: "my-subnetwork",
+			RelativeName: "projects/my-project/regions/us-central1/subnetworks/my-subnetwork",
+		},
+		"relative name": {
+			Name:         "projects/my-project/locations/europe-north1/datasets/my-dataset",
+			Project:      "my-project",
+			Location:     "europe-north1",
+			Collection:   "datasets",
+			ResourceName: "my-dataset",
+			RelativeName: "projects/my-project/locations/europe-north1/datasets/my-dataset",
+		},
+		"global": {
+			Name:         "https://www.googleapis.com/compute/beta/projects/my-project/global/networks/my-network",
+			Host:         "www.googleapis.com",
+			Project:      "my-project",
+			Location:     "global",
+			Collection:   "networks",
+			ResourceName: "my-network",
+			RelativeName: "projects/my-project/global/networks/my-network",
+		},
+		"outside of a project": {
+			Name:         "//cloudresourcemanager.googleapis.com/folders/123",
+			Host:         "cloudresourcemanager.googleapis.com",
+			Collection:   "folders",
+			ResourceName: "123",
+			RelativeName: "folders/123",
+		},
+		"partial": {
+			Name:         "zones/us-central1-a/machineTypes/n1-standard-1",
+			Location:     "us-central1-a",
+			Collection:   "machineTypes",
+			ResourceName: "n1-standard-1",
+			RelativeName: "zones/us-central1-a/machineTypes/n1-standard-1",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			name, err := resourcename.Parse(tc.Name)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			got := []string{name.Host, name.Project(), name.Location(), name.Collection(), name.Name(), name.RelativeName()}
+			want := []string{tc.Host, tc.Project, tc.Location, tc.Collection, tc.ResourceName, tc.RelativeName}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("want %q, got %q", want, got)
+			}
+		})
+	}
+}
+
+func TestParse_invalid(t *testing.T) {
+	for _, name := range []string{
+		"",
+		"my-instance",
+		"projects/my-project/zones/us-central1-a/instances",
+		"projects/my-project/global",
+		"https://www.googleapis.com/compute/v1/",
+		"projects//zones/us-central1-a",
+	} {
+		if _, err := resourcename.Parse(name); err == nil {
+			t.Errorf("expected an error parsing %q", name)
+		}
+	}
+}
+
+func TestFieldValue(t *testing.T) {
+	cases := map[string]string{
+		"zones":    "us-central1-a",
+		"projects": "my-project",
+		"regions":  "",
+	}
+	for collection, expected := range cases {
+		if got := resourcename.FieldValue("//compute.googleapis.com/projects/my-project/zones/us-central1-a", collection); got != expected {
+			t.Errorf("expected %q for %q, got %q", expected, collection, got)
+		}
+	}
+}
@@ -44,7 +44,7 @@ func ResourceBigtableInstance() *schema.Resource {
 		SchemaVersion: 1,
 		StateUpgraders: []schema.StateUpgrader{
 			{
-				Type:    resourceBigtableInstanceResourceV0().CoreConfigSchema().ImpliedType(),
+				Type:    tpgresource.ImpliedType("google_bigtable_instance/v0", resourceBigtableInstanceResourceV0),
 				Upgrade: ResourceBigtableInstanceUpgradeV0,
 				Version: 0,
 			},
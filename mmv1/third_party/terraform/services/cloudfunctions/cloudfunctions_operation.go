@@ -28,7 +28,7 @@ func CloudFunctionsOperationWait(config *transport_tpg.Config, op *cloudfunction
 	if err := w.SetOp(op); err != nil {
 		return err
 	}
-	return tpgresource.OperationWait(w, activity, timeout, config.PollInterval)
+	return tpgresource.OperationWaitContext(config.Context, w, activity, timeout, config.ServicePollInterval("cloud_functions"))
 }
 
 func IsCloudFunctionsSourceCodeError(err error) (bool, string) {
@@ -50,7 +50,7 @@ func RunAdminV2OperationWaitTimeWithResponse(config *transport_tpg.Config, op *r
 	if err != nil {
 		return err
 	}
-	if err := tpgresource.OperationWait(w, activity, timeout, config.PollInterval); err != nil {
+	if err := tpgresource.OperationWaitContext(config.Context, w, activity, timeout, config.ServicePollInterval("cloud_run_v2")); err != nil {
 		return err
 	}
 	return json.Unmarshal([]byte(w.CommonOperationWaiter.Op.Response), response)
@@ -64,5 +64,5 @@ func RunAdminV2OperationWaitTime(config *transport_tpg.Config, op *run.GoogleLon
 	if err != nil {
 		return err
 	}
-	return tpgresource.OperationWait(w, activity, timeout, config.PollInterval)
+	return tpgresource.OperationWaitContext(config.Context, w, activity, timeout, config.ServicePollInterval("cloud_run_v2"))
 }
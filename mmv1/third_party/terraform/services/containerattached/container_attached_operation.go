@@ -53,7 +53,7 @@ func ContainerAttachedOperationWaitTimeWithResponse(config *transport_tpg.Config
 	if err != nil {
 		return err
 	}
-	if err := tpgresource.OperationWait(w, activity, timeout, config.PollInterval); err != nil {
+	if err := tpgresource.OperationWaitContext(config.Context, w, activity, timeout, config.ServicePollInterval("container_attached")); err != nil {
 		return err
 	}
 	return json.Unmarshal([]byte(w.CommonOperationWaiter.Op.Response), response)
@@ -69,5 +69,5 @@ func ContainerAttachedOperationWaitTime(config *transport_tpg.Config, op map[str
 		// If w is nil, the op was synchronous.
 		return err
 	}
-	return tpgresource.OperationWait(w, activity, timeout, config.PollInterval)
+	return tpgresource.OperationWaitContext(config.Context, w, activity, timeout, config.ServicePollInterval("container_attached"))
 }
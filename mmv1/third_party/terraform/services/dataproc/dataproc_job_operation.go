@@ -76,7 +76,7 @@ func DataprocJobOperationWait(config *transport_tpg.Config, region, projectId, j
 		ProjectId: projectId,
 		JobId:     jobId,
 	}
-	return tpgresource.OperationWait(w, activity, timeout, config.PollInterval)
+	return tpgresource.OperationWaitContext(config.Context, w, activity, timeout, config.ServicePollInterval("dataproc"))
 }
 
 type DataprocDeleteJobOperationWaiter struct {
@@ -116,5 +116,5 @@ func DataprocDeleteOperationWait(config *transport_tpg.Config, region, projectId
 			JobId:     jobId,
 		},
 	}
-	return tpgresource.OperationWait(w, activity, timeout, config.PollInterval)
+	return tpgresource.OperationWaitContext(config.Context, w, activity, timeout, config.ServicePollInterval("dataproc"))
 }
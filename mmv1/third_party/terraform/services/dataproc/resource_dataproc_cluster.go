@@ -172,7 +172,7 @@ func ResourceDataprocCluster() *schema.Resource {
 		SchemaVersion: 1,
 		StateUpgraders: []schema.StateUpgrader{
 			{
-				Type:    resourceDataprocClusterResourceV0().CoreConfigSchema().ImpliedType(),
+				Type:    tpgresource.ImpliedType("google_dataproc_cluster/v0", resourceDataprocClusterResourceV0),
 				Upgrade: ResourceDataprocClusterStateUpgradeV0,
 				Version: 0,
 			},
@@ -26,7 +26,7 @@ func DataSourceGoogleServiceAccountIdToken() *schema.Resource {
 		Schema: map[string]*schema.Schema{
 			"target_audience": {
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
 			},
 			"target_service_account": {
 				Type:         schema.TypeString,
@@ -44,7 +44,6 @@ func DataSourceGoogleServiceAccountIdToken() *schema.Resource {
 			"include_email": {
 				Type:     schema.TypeBool,
 				Optional: true,
-				Default:  false,
 			},
 			// Not used currently
 			// https://github.com/googleapis/google-api-go-client/issues/542
@@ -71,7 +70,20 @@ func dataSourceGoogleServiceAccountIdTokenRead(d *schema.ResourceData, meta inte
 		return err
 	}
 
+	// The audience and include_email default to the provider's id_token_audience
+	// and id_token_include_email.
 	targetAudience := d.Get("target_audience").(string)
+	if targetAudience == "" {
+		targetAudience = config.IdTokenAudience
+	}
+	if targetAudience == "" {
+		return fmt.Errorf("one of target_audience and the provider's id_token_audience is required")
+	}
+	includeEmail := config.IdTokenIncludeEmail
+	if v := d.GetRawConfig().GetAttr("include_email"); !v.IsNull() {
+		includeEmail = v.True()
+	}
+
 	creds, err := config.GetCredentials([]string{userInfoScope}, false)
 	if err != nil {
 		return fmt.Errorf("error calling getCredentials(): %v", err)
@@ -86,7 +98,7 @@ func dataSourceGoogleServiceAccountIdTokenRead(d *schema.ResourceData, meta inte
 		name := fmt.Sprintf("projects/-/serviceAccounts/%s", targetServiceAccount)
 		tokenRequest := &iamcredentials.GenerateIdTokenRequest{
 			Audience:     targetAudience,
-			IncludeEmail: d.Get("include_email").(bool),
+			IncludeEmail: includeEmail,
 			Delegates:    tpgresource.ConvertStringSet(d.Get("delegates").(*schema.Set)),
 		}
 		at, err := service.Projects.ServiceAccounts.GenerateIdToken(name, tokenRequest).Do()
@@ -0,0 +1,158 @@
+package resourcemanager
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-provider-google/google/fwtransport"
+	"github.com/hashicorp/terraform-provider-google/google/tpgresource"
+	"github.com/hashicorp/terraform-provider-google/google/verify"
+	iamcredentials "google.golang.org/api/iamcredentials/v1"
+)
+
+// The lifetime of access tokens if none is configured, and the maximum
+// lifetime allowed by the API.
+const defaultServiceAccountAccessTokenLifetime = "3600s"
+
+// Ensure the ephemeral resource satisfies the expected interfaces.
+var (
+	_ ephemeral.EphemeralResource              = &GoogleServiceAccountAccessTokenEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithConfigure = &GoogleServiceAccountAccessTokenEphemeralResource{}
+)
+
+func NewGoogleServiceAccountAccessTokenEphemeralResource() ephemeral.EphemeralResource {
+	return &GoogleServiceAccountAccessTokenEphemeralResource{}
+}
+
+// GoogleServiceAccountAccessTokenEphemeralResource generates access tokens
+// like the google_service_account_access_token data source, without
+// persisting them to plan or state.
+type GoogleServiceAccountAccessTokenEphemeralResource struct {
+	client *iamcredentials.Service
+}
+
+type GoogleServiceAccountAccessTokenModel struct {
+	TargetServiceAccount types.String `tfsdk:"target_service_account"`
+	AccessToken          types.String `tfsdk:"access_token"`
+	Scopes               types.Set    `tfsdk:"scopes"`
+	Delegates            types.Set    `tfsdk:"delegates"`
+	Lifetime             types.String `tfsdk:"lifetime"`
+}
+
+func (r *GoogleServiceAccountAccessTokenEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_service_account_access_token"
+}
+
+func (r *GoogleServiceAccountAccessTokenEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description:         "Produces an access token for an impersonated service account, without storing it in plan or state.",
+		MarkdownDescription: "Produces an `access_token` for an impersonated service account, without storing it in plan or state.",
+		Attributes: map[string]schema.Attribute{
+			"target_service_account": schema.StringAttribute{
+				Description:         "The service account to impersonate, e.g. service_B@your-project-id.iam.gserviceaccount.com.",
+				MarkdownDescription: "The service account to impersonate, e.g. `service_B@your-project-id.iam.gserviceaccount.com`.",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile("("+strings.Join(verify.PossibleServiceAccountNames, "|")+")"), "must be a service account email"),
+				},
+			},
+			"scopes": schema.SetAttribute{
+				Description:         "The scopes the new credential should have, e.g. cloud-platform.",
+				MarkdownDescription: "The scopes the new credential should have, e.g. `cloud-platform`.",
+				ElementType:         types.StringType,
+				Required:            true,
+			},
+			"delegates": schema.SetAttribute{
+				Description:         "The delegate chain of approvals needed to perform full impersonation, as fully qualified service account names.",
+				MarkdownDescription: "The delegate chain of approvals needed to perform full impersonation, as fully qualified service account names.",
+				ElementType:         types.StringType,
+				Optional:            true,
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(stringvalidator.RegexMatches(regexp.MustCompile(verify.ServiceAccountLinkRegex), "must be a fully qualified service account name")),
+				},
+			},
+			"lifetime": schema.StringAttribute{
+				Description:         "The lifetime of the token, defaults to its maximum of 3600s.",
+				MarkdownDescription: "The lifetime of the token, defaults to its maximum of `3600s`.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^\d+(\.\d{1,9})?s$`), "must be a duration in seconds, e.g. 300s"),
+				},
+			},
+			"access_token": schema.StringAttribute{
+				Description:         "The access token representing the impersonated identity.",
+				MarkdownDescription: "The `access_token` representing the impersonated identity.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+		},
+	}
+}
+
+func (r *GoogleServiceAccountAccessTokenEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	p, ok := req.ProviderData.(*fwtransport.FrameworkProviderConfig)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *fwtransport.FrameworkProviderConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = p.NewIamCredentialsClient(p.UserAgent, &resp.Diagnostics)
+}
+
+func (r *GoogleServiceAccountAccessTokenEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data GoogleServiceAccountAccessTokenModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var scopes, delegates []string
+	resp.Diagnostics.Append(data.Scopes.ElementsAs(ctx, &scopes, false)...)
+	if !data.Delegates.IsNull() {
+		resp.Diagnostics.Append(data.Delegates.ElementsAs(ctx, &delegates, false)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	lifetime := data.Lifetime.ValueString()
+	if lifetime == "" {
+		lifetime = defaultServiceAccountAccessTokenLifetime
+	}
+
+	name := fmt.Sprintf("projects/-/serviceAccounts/%s", data.TargetServiceAccount.ValueString())
+	tokenRequest := &iamcredentials.GenerateAccessTokenRequest{
+		Lifetime:  lifetime,
+		Delegates: delegates,
+		Scope:     tpgresource.CanonicalizeServiceScopes(scopes),
+	}
+	at, err := r.client.Projects.ServiceAccounts.GenerateAccessToken(name, tokenRequest).Do()
+	if err != nil {
+		resp.Diagnostics.AddError("Error generating access token", err.Error())
+		return
+	}
+
+	data.Lifetime = types.StringValue(lifetime)
+	data.AccessToken = types.StringValue(at.AccessToken)
+
+	// Save data into the ephemeral result
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
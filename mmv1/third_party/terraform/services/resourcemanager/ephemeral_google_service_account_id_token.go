@@ -0,0 +1,160 @@
+package resourcemanager
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-provider-google/google/fwtransport"
+	"github.com/hashicorp/terraform-provider-google/google/verify"
+	iamcredentials "google.golang.org/api/iamcredentials/v1"
+)
+
+// Ensure the ephemeral resource satisfies the expected interfaces.
+var (
+	_ ephemeral.EphemeralResource              = &GoogleServiceAccountIdTokenEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithConfigure = &GoogleServiceAccountIdTokenEphemeralResource{}
+)
+
+func NewGoogleServiceAccountIdTokenEphemeralResource() ephemeral.EphemeralResource {
+	return &GoogleServiceAccountIdTokenEphemeralResource{}
+}
+
+// GoogleServiceAccountIdTokenEphemeralResource generates ID tokens like the
+// google_service_account_id_token data source in impersonation mode, without
+// persisting them to plan or state.
+type GoogleServiceAccountIdTokenEphemeralResource struct {
+	client         *iamcredentials.Service
+	providerConfig *fwtransport.FrameworkProviderConfig
+}
+
+type GoogleServiceAccountIdTokenModel struct {
+	TargetAudience       types.String `tfsdk:"target_audience"`
+	TargetServiceAccount types.String `tfsdk:"target_service_account"`
+	Delegates            types.Set    `tfsdk:"delegates"`
+	IncludeEmail         types.Bool   `tfsdk:"include_email"`
+	IdToken              types.String `tfsdk:"id_token"`
+}
+
+func (r *GoogleServiceAccountIdTokenEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_service_account_id_token"
+}
+
+func (r *GoogleServiceAccountIdTokenEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description:         "Produces an OpenID Connect ID token for an impersonated service account, without storing it in plan or state.",
+		MarkdownDescription: "Produces an OpenID Connect `id_token` for an impersonated service account, without storing it in plan or state.",
+		Attributes: map[string]schema.Attribute{
+			"target_audience": schema.StringAttribute{
+				Description:         "The audience claim of the ID token. Defaults to the id_token_audience of the provider, one of which is required.",
+				MarkdownDescription: "The audience claim of the `id_token`. Defaults to the `id_token_audience` of the provider, one of which is required.",
+				Optional:            true,
+			},
+			"target_service_account": schema.StringAttribute{
+				Description:         "The email of the service account to impersonate.",
+				MarkdownDescription: "The email of the service account to impersonate.",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile("("+strings.Join(verify.PossibleServiceAccountNames, "|")+")"), "must be a service account email"),
+				},
+			},
+			"delegates": schema.SetAttribute{
+				Description:         "The delegate chain of approvals needed to perform full impersonation, as fully qualified service account names.",
+				MarkdownDescription: "The delegate chain of approvals needed to perform full impersonation, as fully qualified service account names.",
+				ElementType:         types.StringType,
+				Optional:            true,
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(stringvalidator.RegexMatches(regexp.MustCompile(verify.ServiceAccountLinkRegex), "must be a fully qualified service account name")),
+				},
+			},
+			"include_email": schema.BoolAttribute{
+				Description:         "Include the verified email in the claims. Defaults to the id_token_include_email of the provider.",
+				MarkdownDescription: "Include the verified email in the claims. Defaults to the `id_token_include_email` of the provider.",
+				Optional:            true,
+			},
+			"id_token": schema.StringAttribute{
+				Description:         "The ID token representing the impersonated identity.",
+				MarkdownDescription: "The `id_token` representing the impersonated identity.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+		},
+	}
+}
+
+func (r *GoogleServiceAccountIdTokenEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	p, ok := req.ProviderData.(*fwtransport.FrameworkProviderConfig)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *fwtransport.FrameworkProviderConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = p.NewIamCredentialsClient(p.UserAgent, &resp.Diagnostics)
+	// Required for the id_token_audience and id_token_include_email defaults
+	r.providerConfig = p
+}
+
+func (r *GoogleServiceAccountIdTokenEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data GoogleServiceAccountIdTokenModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var delegates []string
+	if !data.Delegates.IsNull() {
+		resp.Diagnostics.Append(data.Delegates.ElementsAs(ctx, &delegates, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// The audience and include_email default to the provider's id_token_audience
+	// and id_token_include_email.
+	if data.TargetAudience.IsNull() {
+		data.TargetAudience = r.providerConfig.IdTokenAudience
+	}
+	if data.TargetAudience.ValueString() == "" {
+		resp.Diagnostics.AddError("Missing target audience", "one of target_audience and the provider's id_token_audience is required")
+		return
+	}
+	if data.IncludeEmail.IsNull() {
+		data.IncludeEmail = types.BoolValue(r.providerConfig.IdTokenIncludeEmail.ValueBool())
+	}
+
+	// Use
+	// https://cloud.google.com/iam/docs/reference/credentials/rest/v1/projects.serviceAccounts/generateIdToken
+	name := fmt.Sprintf("projects/-/serviceAccounts/%s", data.TargetServiceAccount.ValueString())
+	tokenRequest := &iamcredentials.GenerateIdTokenRequest{
+		Audience:     data.TargetAudience.ValueString(),
+		IncludeEmail: data.IncludeEmail.ValueBool(),
+		Delegates:    delegates,
+	}
+	at, err := r.client.Projects.ServiceAccounts.GenerateIdToken(name, tokenRequest).Do()
+	if err != nil {
+		resp.Diagnostics.AddError("Error generating ID token", fmt.Sprintf("error calling iamcredentials.GenerateIdToken: %v", err))
+		return
+	}
+
+	data.IdToken = types.StringValue(at.Token)
+
+	// Save data into the ephemeral result
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
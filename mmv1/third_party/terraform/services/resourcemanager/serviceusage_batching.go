@@ -11,9 +11,18 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
-const (
-	batchKeyTmplServiceUsageEnableServices = "project/%s/services:batchEnable"
-	batchKeyTmplServiceUsageListServices   = "project/%s/services"
+var (
+	batchTypeServiceUsageEnableServices = transport_tpg.BatchType[[]string]{
+		Name:        "Enable Project Services",
+		KeyTemplate: "project/%s/services:batchEnable",
+		Combine:     transport_tpg.AppendBatchBodies[string],
+	}
+	// The request is exactly the same no matter how many services we read.
+	batchTypeServiceUsageListServices = transport_tpg.BatchType[interface{}]{
+		Name:        "List Project Services",
+		KeyTemplate: "project/%s/services",
+		Combine:     transport_tpg.KeepBatchBody[interface{}],
+	}
 )
 
 // BatchRequestEnableServices can be used to batch requests to enable services
@@ -36,17 +45,12 @@ func BatchRequestEnableService(service string, project string, d *schema.Resourc
 		billingProject = bp
 	}
 
-	req := &transport_tpg.BatchRequest{
-		ResourceName: project,
-		Body:         []string{service},
-		CombineF:     combineServiceUsageServicesBatches,
-		SendF:        sendBatchFuncEnableServices(config, userAgent, billingProject, d.Timeout(schema.TimeoutCreate)),
-		DebugId:      fmt.Sprintf("Enable Project Service %q for project %q", service, project),
-	}
-
-	_, err = config.RequestBatcherServiceUsage.SendRequestWithTimeout(
-		fmt.Sprintf(batchKeyTmplServiceUsageEnableServices, project),
-		req,
+	_, err = batchTypeServiceUsageEnableServices.Send(
+		config.RequestBatcherServiceUsage,
+		project,
+		[]string{service},
+		sendBatchFuncEnableServices(config, userAgent, billingProject, d.Timeout(schema.TimeoutCreate)),
+		fmt.Sprintf("Enable Project Service %q for project %q", service, project),
 		d.Timeout(schema.TimeoutCreate))
 	return err
 }
@@ -90,45 +94,22 @@ func BatchRequestReadServices(project string, d *schema.ResourceData, config *tr
 		billingProject = bp
 	}
 
-	req := &transport_tpg.BatchRequest{
-		ResourceName: project,
-		Body:         nil,
-		// Use empty CombineF since the request is exactly the same no matter how many services we read.
-		CombineF: func(body interface{}, toAdd interface{}) (interface{}, error) { return nil, nil },
-		SendF:    sendListServices(config, billingProject, userAgent, d.Timeout(schema.TimeoutRead)),
-		DebugId:  fmt.Sprintf("List Project Services %s", project),
-	}
-
-	return config.RequestBatcherServiceUsage.SendRequestWithTimeout(
-		fmt.Sprintf(batchKeyTmplServiceUsageListServices, project),
-		req,
+	return batchTypeServiceUsageListServices.Send(
+		config.RequestBatcherServiceUsage,
+		project,
+		nil,
+		sendListServices(config, billingProject, userAgent, d.Timeout(schema.TimeoutRead)),
+		fmt.Sprintf("List Project Services %s", project),
 		d.Timeout(schema.TimeoutRead))
 }
 
-func combineServiceUsageServicesBatches(srvsRaw interface{}, toAddRaw interface{}) (interface{}, error) {
-	srvs, ok := srvsRaw.([]string)
-	if !ok {
-		return nil, fmt.Errorf("Expected batch body type to be []string, got %v. This is a provider error.", srvsRaw)
-	}
-	toAdd, ok := toAddRaw.([]string)
-	if !ok {
-		return nil, fmt.Errorf("Expected new request body type to be []string, got %v. This is a provider error.", toAdd)
-	}
-
-	return append(srvs, toAdd...), nil
-}
-
-func sendBatchFuncEnableServices(config *transport_tpg.Config, userAgent, billingProject string, timeout time.Duration) transport_tpg.BatcherSendFunc {
-	return func(project string, toEnableRaw interface{}) (interface{}, error) {
-		toEnable, ok := toEnableRaw.([]string)
-		if !ok {
-			return nil, fmt.Errorf("Expected batch body type to be []string, got %v. This is a provider error.", toEnableRaw)
-		}
+func sendBatchFuncEnableServices(config *transport_tpg.Config, userAgent, billingProject string, timeout time.Duration) func(string, []string) (interface{}, error) {
+	return func(project string, toEnable []string) (interface{}, error) {
 		return nil, EnableServiceUsageProjectServices(toEnable, project, billingProject, userAgent, config, timeout)
 	}
 }
 
-func sendListServices(config *transport_tpg.Config, billingProject, userAgent string, timeout time.Duration) transport_tpg.BatcherSendFunc {
+func sendListServices(config *transport_tpg.Config, billingProject, userAgent string, timeout time.Duration) func(string, interface{}) (interface{}, error) {
 	return func(project string, _ interface{}) (interface{}, error) {
 		return ListCurrentlyEnabledServices(project, billingProject, userAgent, config, timeout)
 	}
@@ -117,7 +117,7 @@ func SqlAdminOperationWaitTime(config *transport_tpg.Config, res interface{}, pr
 	if err := w.SetOp(op); err != nil {
 		return err
 	}
-	return tpgresource.OperationWait(w, activity, timeout, config.PollInterval)
+	return tpgresource.OperationWaitContext(config.Context, w, activity, timeout, config.ServicePollInterval("sql"))
 }
 
 // SqlAdminOperationError wraps sqladmin.OperationError and implements the
@@ -1,53 +1,34 @@
 package tpgiamresource
 
 import (
-	"fmt"
 	"time"
 
 	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
 	"google.golang.org/api/cloudresourcemanager/v1"
 )
 
-const (
-	batchKeyTmplModifyIamPolicy = "%s modifyIamPolicy"
-)
+// batchTypeModifyIamPolicy batches the modifications of the IAM policy of a
+// resource, keyed by the mutex key of its updater.
+var batchTypeModifyIamPolicy = transport_tpg.BatchType[[]iamPolicyModifyFunc]{
+	Name:        "Modify IAM Policy",
+	KeyTemplate: "%s modifyIamPolicy",
+	Combine:     transport_tpg.AppendBatchBodies[iamPolicyModifyFunc],
+}
 
 func BatchRequestModifyIamPolicy(updater ResourceIamUpdater, modify iamPolicyModifyFunc, config *transport_tpg.Config, reqDesc string) error {
-	batchKey := fmt.Sprintf(batchKeyTmplModifyIamPolicy, updater.GetMutexKey())
-
-	request := &transport_tpg.BatchRequest{
-		ResourceName: updater.GetResourceId(),
-		Body:         []iamPolicyModifyFunc{modify},
-		CombineF:     combineBatchIamPolicyModifiers,
-		SendF:        sendBatchModifyIamPolicy(updater),
-		DebugId:      reqDesc,
-	}
-
-	_, err := config.RequestBatcherIam.SendRequestWithTimeout(batchKey, request, time.Minute*30)
+	_, err := batchTypeModifyIamPolicy.Send(
+		config.RequestBatcherIam,
+		updater.GetMutexKey(),
+		[]iamPolicyModifyFunc{modify},
+		sendBatchModifyIamPolicy(updater, config),
+		reqDesc,
+		time.Minute*30)
 	return err
 }
 
-func combineBatchIamPolicyModifiers(currV interface{}, toAddV interface{}) (interface{}, error) {
-	currModifiers, ok := currV.([]iamPolicyModifyFunc)
-	if !ok {
-		return nil, fmt.Errorf("provider error in batch combiner: expected data to be type []iamPolicyModifyFunc, got %v with type %T", currV, currV)
-	}
-
-	newModifiers, ok := toAddV.([]iamPolicyModifyFunc)
-	if !ok {
-		return nil, fmt.Errorf("provider error in batch combiner: expected data to be type []iamPolicyModifyFunc, got %v with type %T", currV, currV)
-	}
-
-	return append(currModifiers, newModifiers...), nil
-}
-
-func sendBatchModifyIamPolicy(updater ResourceIamUpdater) transport_tpg.BatcherSendFunc {
-	return func(resourceName string, body interface{}) (interface{}, error) {
-		modifiers, ok := body.([]iamPolicyModifyFunc)
-		if !ok {
-			return nil, fmt.Errorf("provider error: expected data to be type []iamPolicyModifyFunc, got %v with type %T", body, body)
-		}
-		return nil, iamPolicyReadModifyWrite(updater, func(policy *cloudresourcemanager.Policy) error {
+func sendBatchModifyIamPolicy(updater ResourceIamUpdater, config *transport_tpg.Config) func(string, []iamPolicyModifyFunc) (interface{}, error) {
+	return func(_ string, modifiers []iamPolicyModifyFunc) (interface{}, error) {
+		return nil, iamPolicyReadModifyWrite(updater, config, func(policy *cloudresourcemanager.Policy) error {
 			for _, modifyF := range modifiers {
 				if err := modifyF(policy); err != nil {
 					return err
@@ -0,0 +1,78 @@
+package tpgiamresource
+
+import (
+	"math/rand"
+	"time"
+)
+
+const (
+	// maxIamPolicyConflicts is how many times a read-modify-write cycle of an
+	// IAM policy is restarted after concurrent changes of the policy.
+	maxIamPolicyConflicts = 10
+	// minIamPolicyConflictBackoff and maxIamPolicyConflictBackoff bound the
+	// wait before restarting a read-modify-write cycle.
+	minIamPolicyConflictBackoff = time.Second
+	maxIamPolicyConflictBackoff = 30 * time.Second
+)
+
+// iamPolicyConflictRetry restarts the read-modify-write cycle of an IAM policy
+// whose write failed with a conflict (409 or 412), as the policy was changed
+// since it was read, e.g. by other Terraform runs or other tools.
+//
+// Waits grow exponentially and are jittered, so that the cycles conflicting
+// with each other don't restart in lockstep. The etags of the conflicting
+// writes are fenced: a read returning one of them is stale, as the policy was
+// changed since, so its write would conflict again and is skipped until the
+// read catches up with the latest policy.
+type iamPolicyConflictRetry struct {
+	conflicts int
+	backoff   time.Duration
+	fenced    map[string]bool
+	// jitter returns a random duration in [0, d), see rand.Int63n.
+	jitter func(d time.Duration) time.Duration
+}
+
+func newIamPolicyConflictRetry() *iamPolicyConflictRetry {
+	return &iamPolicyConflictRetry{
+		backoff: minIamPolicyConflictBackoff,
+		fenced:  make(map[string]bool),
+		jitter: func(d time.Duration) time.Duration {
+			return time.Duration(rand.Int63n(int64(d)))
+		},
+	}
+}
+
+// conflict records that the write of the policy read with the given etag
+// conflicted, and returns how long to wait before restarting the cycle, or
+// false once the cycle conflicted too many times.
+func (r *iamPolicyConflictRetry) conflict(etag string) (time.Duration, bool) {
+	if etag != "" {
+		r.fenced[etag] = true
+	}
+	return r.next()
+}
+
+// stale returns whether the policy read with the given etag is older than a
+// write that conflicted already, and if so how long to wait before reading it
+// again, or false once the cycle conflicted too many times.
+func (r *iamPolicyConflictRetry) stale(etag string) (bool, time.Duration, bool) {
+	if !r.fenced[etag] {
+		return false, 0, true
+	}
+	wait, ok := r.next()
+	return true, wait, ok
+}
+
+func (r *iamPolicyConflictRetry) next() (time.Duration, bool) {
+	r.conflicts++
+	if r.conflicts > maxIamPolicyConflicts {
+		return 0, false
+	}
+	// Half of the backoff is fixed, so that waits still grow.
+	wait := r.backoff/2 + r.jitter(r.backoff/2+1)
+	r.backoff *= 2
+	if r.backoff > maxIamPolicyConflictBackoff {
+		r.backoff = maxIamPolicyConflictBackoff
+	}
+	return wait, true
+}
@@ -0,0 +1,40 @@
+package tpgiamresource
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIamPolicyConflictRetry(t *testing.T) {
+	retry := newIamPolicyConflictRetry()
+	retry.jitter = func(d time.Duration) time.Duration {
+		return d - 1
+	}
+
+	if stale, _, _ := retry.stale("etag-1"); stale {
+		t.Fatalf("expected a policy to be fresh before any conflict")
+	}
+
+	wait, ok := retry.conflict("etag-1")
+	if !ok || wait != time.Second {
+		t.Fatalf("expected to retry after %s, got %s (%t)", time.Second, wait, ok)
+	}
+	if stale, wait, ok := retry.stale("etag-1"); !stale || !ok || wait != 2*time.Second {
+		t.Fatalf("expected the policy read with a conflicting etag to be read again after %s, got %t, %s (%t)", 2*time.Second, stale, wait, ok)
+	}
+	if stale, _, _ := retry.stale("etag-2"); stale {
+		t.Fatalf("expected a policy with a new etag to be fresh")
+	}
+
+	for i := 0; i < maxIamPolicyConflicts-2; i++ {
+		if wait, ok = retry.conflict("etag-2"); !ok {
+			t.Fatalf("expected to retry conflict %d", i+3)
+		}
+		if wait > maxIamPolicyConflictBackoff {
+			t.Fatalf("expected waits of at most %s, got %s", maxIamPolicyConflictBackoff, wait)
+		}
+	}
+	if _, ok := retry.conflict("etag-3"); ok {
+		t.Fatalf("expected to give up after %d conflicts", maxIamPolicyConflicts)
+	}
+}
@@ -88,7 +88,7 @@ func ResourceIamPolicyRead(newUpdaterFunc NewResourceIamUpdaterFunc) schema.Read
 			return err
 		}
 
-		policy, err := iamPolicyReadWithRetry(updater)
+		policy, err := iamPolicyReadWithRetry(updater, config)
 		if err != nil {
 			return transport_tpg.HandleNotFoundError(err, d, fmt.Sprintf("Resource %q with IAM Policy", updater.DescribeResource()))
 		}
@@ -113,3 +113,15 @@ func SetDataSourceAnnotations(d *schema.ResourceData) error {
 
 	return nil
 }
+
+// Sets the "annotations" field of an item of a list data source with the value of its "effective_annotations"
+// field, like SetDataSourceAnnotations does for data sources of a single resource. The item is flattened with
+// the resource flatteners, which only keep the annotations present in the configuration.
+func SetDataSourceItemAnnotations(item map[string]interface{}) {
+	effectiveAnnotations, ok := item["effective_annotations"]
+	if !ok {
+		return
+	}
+
+	item["annotations"] = effectiveAnnotations
+}
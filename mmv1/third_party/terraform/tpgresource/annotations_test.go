@@ -0,0 +1,41 @@
+package tpgresource_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-provider-google/google/tpgresource"
+)
+
+func TestSetDataSourceItemAnnotations(t *testing.T) {
+	cases := map[string]struct {
+		Item     map[string]interface{}
+		Expected map[string]interface{}
+	}{
+		"all of the annotations": {
+			Item: map[string]interface{}{
+				"name":                  "foo",
+				"annotations":           map[string]interface{}{},
+				"effective_annotations": map[string]interface{}{"owner": "team"},
+			},
+			Expected: map[string]interface{}{
+				"name":                  "foo",
+				"annotations":           map[string]interface{}{"owner": "team"},
+				"effective_annotations": map[string]interface{}{"owner": "team"},
+			},
+		},
+		"no annotations": {
+			Item:     map[string]interface{}{"name": "foo"},
+			Expected: map[string]interface{}{"name": "foo"},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			tpgresource.SetDataSourceItemAnnotations(tc.Item)
+			if !reflect.DeepEqual(tc.Item, tc.Expected) {
+				t.Fatalf("expected item %v, got %v", tc.Expected, tc.Item)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package tpgresource
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"time"
@@ -137,6 +138,16 @@ func CommonRefreshFunc(w Waiter) resource.StateRefreshFunc {
 }
 
 func OperationWait(w Waiter, activity string, timeout time.Duration, pollInterval time.Duration) error {
+	return OperationWaitContext(context.Background(), w, activity, timeout, pollInterval)
+}
+
+// OperationWaitContext is OperationWait, stopping to wait when the context is
+// done, e.g. when Terraform is interrupted and the context is the one of the
+// provider configuration, transport_tpg.Config.Context.
+func OperationWaitContext(ctx context.Context, w Waiter, activity string, timeout time.Duration, pollInterval time.Duration) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
 	if OperationDone(w) {
 		return w.Error()
 	}
@@ -149,7 +160,7 @@ func OperationWait(w Waiter, activity string, timeout time.Duration, pollInterva
 		MinTimeout:   2 * time.Second,
 		PollInterval: pollInterval,
 	}
-	opRaw, err := c.WaitForState()
+	opRaw, err := c.WaitForStateContext(ctx)
 	if err != nil {
 		return fmt.Errorf("Error waiting for %s: %w", activity, err)
 	}
@@ -1,6 +1,8 @@
 package tpgresource
 
 import (
+	"context"
+	"errors"
 	"net/url"
 	"testing"
 	"time"
@@ -67,3 +69,16 @@ func TestOperationWait_TimeoutsShouldRetry(t *testing.T) {
 			expectedRunCount, testWaiter.runCount)
 	}
 }
+
+func TestOperationWaitContext_StopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	testWaiter := TestWaiter{
+		runCount: 0,
+	}
+	err := OperationWaitContext(ctx, &testWaiter, "my-activity", 1*time.Minute, 0*time.Second)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("unexpected error waiting for operation: got '%v', want '%v'", err, context.Canceled)
+	}
+}
@@ -0,0 +1,118 @@
+package tpgresource
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+)
+
+// DefaultTagBindings returns the tag bindings attaching the provider's
+// default_tags to the resource of the given full name. Tags are given either
+// as tag key namespaced names mapped to tag value short names, e.g.
+// "my-project/env" = "prod", or as tag key IDs mapped to tag value IDs, e.g.
+// "tagKeys/123" = "tagValues/456".
+func DefaultTagBindings(parent string, defaultTags map[string]string) []map[string]interface{} {
+	keys := make([]string, 0, len(defaultTags))
+	for k := range defaultTags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	bindings := make([]map[string]interface{}, 0, len(keys))
+	for _, k := range keys {
+		binding := map[string]interface{}{
+			"parent": parent,
+		}
+		if v := defaultTags[k]; strings.HasPrefix(v, "tagValues/") {
+			binding["tagValue"] = v
+		} else {
+			binding["tagValueNamespacedName"] = fmt.Sprintf("%s/%s", k, v)
+		}
+		bindings = append(bindings, binding)
+	}
+	return bindings
+}
+
+// CreateDefaultTagBindings binds the provider's default_tags to a resource
+// that was just created. parentTmpl is the full resource name of the
+// resource, and locationTmpl its location if its tag bindings are
+// location-specific, both templated like URLs of the resource. Tags bound to
+// the resource already, e.g. through its own fields, are left as they are.
+func CreateDefaultTagBindings(d TerraformResourceData, config *transport_tpg.Config, parentTmpl, locationTmpl, userAgent string, timeout time.Duration) error {
+	if len(config.DefaultTags) == 0 {
+		return nil
+	}
+
+	parent, err := ReplaceVars(d, config, parentTmpl)
+	if err != nil {
+		return err
+	}
+
+	basePath := config.TagsBasePath
+	if locationTmpl != "" {
+		location, err := ReplaceVars(d, config, locationTmpl)
+		if err != nil {
+			return err
+		}
+		basePath = strings.Replace(config.TagsLocationBasePath, "{{location}}", strings.ToLower(location), 1)
+	}
+
+	for _, binding := range DefaultTagBindings(parent, config.DefaultTags) {
+		log.Printf("[DEBUG] Creating default tag binding: %#v", binding)
+		res, err := transport_tpg.SendRequest(transport_tpg.SendRequestOptions{
+			Config:    config,
+			Method:    "POST",
+			RawURL:    basePath + "tagBindings",
+			UserAgent: userAgent,
+			Body:      binding,
+			Timeout:   timeout,
+		})
+		if err != nil {
+			if transport_tpg.IsGoogleApiErrorWithCode(err, 409) {
+				log.Printf("[DEBUG] Tag binding %#v exists already, skipping", binding)
+				continue
+			}
+			return fmt.Errorf("Error creating default tag binding for %s: %s", parent, err)
+		}
+
+		if name, ok := res["name"]; !ok || name == "" {
+			// This was a synchronous call - there is no operation to wait for.
+			continue
+		}
+		w := &tagBindingOperationWaiter{
+			Config:    config,
+			BasePath:  basePath,
+			UserAgent: userAgent,
+		}
+		if err := w.CommonOperationWaiter.SetOp(res); err != nil {
+			return err
+		}
+		if err := OperationWaitContext(config.Context, w, "Creating default tag binding", timeout, config.ServicePollInterval("tags")); err != nil {
+			return fmt.Errorf("Error waiting to create default tag binding for %s: %s", parent, err)
+		}
+	}
+	return nil
+}
+
+type tagBindingOperationWaiter struct {
+	Config    *transport_tpg.Config
+	BasePath  string
+	UserAgent string
+	CommonOperationWaiter
+}
+
+func (w *tagBindingOperationWaiter) QueryOp() (interface{}, error) {
+	if w == nil {
+		return nil, fmt.Errorf("Cannot query operation, it's unset or nil.")
+	}
+	return transport_tpg.SendRequest(transport_tpg.SendRequestOptions{
+		Config:    w.Config,
+		Method:    "GET",
+		RawURL:    w.BasePath + w.CommonOperationWaiter.Op.Name,
+		UserAgent: w.UserAgent,
+	})
+}
@@ -0,0 +1,39 @@
+package tpgresource_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-provider-google/google/tpgresource"
+)
+
+func TestDefaultTagBindings(t *testing.T) {
+	parent := "//storage.googleapis.com/projects/_/buckets/my-bucket"
+	defaultTags := map[string]string{
+		"tagKeys/123":     "tagValues/456",
+		"my-project/env":  "prod",
+		"123456789/owner": "team-a",
+	}
+
+	expected := []map[string]interface{}{
+		{
+			"parent":                 parent,
+			"tagValueNamespacedName": "123456789/owner/team-a",
+		},
+		{
+			"parent":                 parent,
+			"tagValueNamespacedName": "my-project/env/prod",
+		},
+		{
+			"parent":   parent,
+			"tagValue": "tagValues/456",
+		},
+	}
+	if bindings := tpgresource.DefaultTagBindings(parent, defaultTags); !reflect.DeepEqual(bindings, expected) {
+		t.Errorf("expected %#v, got %#v", expected, bindings)
+	}
+
+	if bindings := tpgresource.DefaultTagBindings(parent, nil); len(bindings) != 0 {
+		t.Errorf("expected no tag bindings, got %#v", bindings)
+	}
+}
@@ -0,0 +1,41 @@
+package tpgresource
+
+import (
+	"sync"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceImpliedTypes and schemaImpliedTypes cache the cty types of the
+// values of resources and of schemas by key, see ImpliedType and
+// SchemaImpliedType.
+var resourceImpliedTypes sync.Map // map[string]cty.Type
+var schemaImpliedTypes sync.Map   // map[string]cty.Type
+
+// ImpliedType returns the cty type of the values of the resource built by the
+// given function, i.e. its CoreConfigSchema().ImpliedType(), cached by key.
+// Keys identify a schema for the lifetime of the process, e.g. the resource
+// type and the version of the previous schemas of state upgraders, which
+// would otherwise be built and reflected upon whenever the provider is. The
+// resource is only built if the key isn't cached yet.
+func ImpliedType(key string, resource func() *schema.Resource) cty.Type {
+	if ty, ok := resourceImpliedTypes.Load(key); ok {
+		return ty.(cty.Type)
+	}
+	ty := resource().CoreConfigSchema().ImpliedType()
+	resourceImpliedTypes.Store(key, ty)
+	return ty
+}
+
+// SchemaImpliedType returns the cty type of the values of the given schema,
+// cached by key like ImpliedType. Unlike the type of a resource, it doesn't
+// have the id and timeouts attributes the SDK adds to resources.
+func SchemaImpliedType(key string, s map[string]*schema.Schema) cty.Type {
+	if ty, ok := schemaImpliedTypes.Load(key); ok {
+		return ty.(cty.Type)
+	}
+	ty := schema.InternalMap(s).CoreConfigSchema().ImpliedType()
+	schemaImpliedTypes.Store(key, ty)
+	return ty
+}
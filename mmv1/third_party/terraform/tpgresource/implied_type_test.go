@@ -0,0 +1,58 @@
+package tpgresource_test
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/hashicorp/terraform-provider-google/google/tpgresource"
+)
+
+func TestImpliedType(t *testing.T) {
+	builds := 0
+	resource := func() *schema.Resource {
+		builds++
+		return &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+			},
+		}
+	}
+
+	expected := cty.Object(map[string]cty.Type{"id": cty.String, "name": cty.String})
+	for i := 0; i < 2; i++ {
+		if ty := tpgresource.ImpliedType("google_test_implied_type/v0", resource); !ty.Equals(expected) {
+			t.Fatalf("expected type %#v, got %#v", expected, ty)
+		}
+	}
+	if builds != 1 {
+		t.Fatalf("expected the resource to be built once, got %d builds", builds)
+	}
+}
+
+func TestSchemaImpliedType(t *testing.T) {
+	s := map[string]*schema.Schema{
+		"name": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+	}
+
+	expected := cty.Object(map[string]cty.Type{"name": cty.String})
+	if ty := tpgresource.SchemaImpliedType("google_test_schema_implied_type", s); !ty.Equals(expected) {
+		t.Fatalf("expected type %#v, got %#v", expected, ty)
+	}
+
+	// The cached type is returned even though the schema changed.
+	s["description"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+	}
+	if ty := tpgresource.SchemaImpliedType("google_test_schema_implied_type", s); !ty.Equals(expected) {
+		t.Fatalf("expected the cached type %#v, got %#v", expected, ty)
+	}
+}
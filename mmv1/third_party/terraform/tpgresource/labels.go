@@ -55,6 +55,19 @@ func SetDataSourceLabels(d *schema.ResourceData) error {
 	return nil
 }
 
+// Sets the "labels" and "terraform_labels" fields of an item of a list data source with the value of its
+// "effective_labels" field, like SetDataSourceLabels does for data sources of a single resource. The item
+// is flattened with the resource flatteners, which only keep the labels present in the configuration.
+func SetDataSourceItemLabels(item map[string]interface{}) {
+	effectiveLabels, ok := item["effective_labels"]
+	if !ok {
+		return
+	}
+
+	item["labels"] = effectiveLabels
+	item["terraform_labels"] = effectiveLabels
+}
+
 func SetLabelsDiff(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
 	raw := d.Get("labels")
 	if raw == nil {
@@ -91,15 +104,8 @@ func SetLabelsDiff(_ context.Context, d *schema.ResourceDiff, meta interface{})
 	}
 
 	// Append optional label indicating the resource was provisioned using Terraform
-	if config.AddTerraformAttributionLabel {
-		if el, ok := d.Get("effective_labels").(map[string]any); ok {
-			_, hasExistingLabel := el[transport_tpg.AttributionKey]
-			if hasExistingLabel ||
-				config.TerraformAttributionLabelAdditionStrategy == transport_tpg.ProactiveAttributionStrategy ||
-				(config.TerraformAttributionLabelAdditionStrategy == transport_tpg.CreateOnlyAttributionStrategy && d.Id() == "") {
-				terraformLabels[transport_tpg.AttributionKey] = transport_tpg.AttributionValue
-			}
-		}
+	if el, ok := d.Get("effective_labels").(map[string]any); ok {
+		AddTerraformAttributionLabel(terraformLabels, el, d.Id() == "", config)
 	}
 
 	labels := raw.(map[string]interface{})
@@ -131,6 +137,23 @@ func SetLabelsDiff(_ context.Context, d *schema.ResourceDiff, meta interface{})
 	return nil
 }
 
+// AddTerraformAttributionLabel adds the label indicating that a resource was
+// provisioned using Terraform to its terraform managed labels, if the provider
+// is configured to. Resources that already have the label keep it, so that it
+// isn't removed when the addition strategy changes.
+func AddTerraformAttributionLabel(terraformLabels map[string]string, effectiveLabels map[string]any, isNew bool, config *transport_tpg.Config) {
+	if !config.AddTerraformAttributionLabel {
+		return
+	}
+
+	_, hasExistingLabel := effectiveLabels[transport_tpg.AttributionKey]
+	if hasExistingLabel ||
+		config.TerraformAttributionLabelAdditionStrategy == transport_tpg.ProactiveAttributionStrategy ||
+		(config.TerraformAttributionLabelAdditionStrategy == transport_tpg.CreateOnlyAttributionStrategy && isNew) {
+		terraformLabels[transport_tpg.AttributionKey] = transport_tpg.AttributionValue
+	}
+}
+
 func SetMetadataLabelsDiff(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
 	l := d.Get("metadata").([]interface{})
 	if len(l) == 0 || l[0] == nil {
@@ -170,15 +193,8 @@ func SetMetadataLabelsDiff(_ context.Context, d *schema.ResourceDiff, meta inter
 	}
 
 	// Append optional label indicating the resource was provisioned using Terraform
-	if config.AddTerraformAttributionLabel {
-		if el, ok := d.Get("metadata.0.effective_labels").(map[string]any); ok {
-			_, hasExistingLabel := el[transport_tpg.AttributionKey]
-			if hasExistingLabel ||
-				config.TerraformAttributionLabelAdditionStrategy == transport_tpg.ProactiveAttributionStrategy ||
-				(config.TerraformAttributionLabelAdditionStrategy == transport_tpg.CreateOnlyAttributionStrategy && d.Id() == "") {
-				terraformLabels[transport_tpg.AttributionKey] = transport_tpg.AttributionValue
-			}
-		}
+	if el, ok := d.Get("metadata.0.effective_labels").(map[string]any); ok {
+		AddTerraformAttributionLabel(terraformLabels, el, d.Id() == "", config)
 	}
 
 	labels := raw.(map[string]interface{})
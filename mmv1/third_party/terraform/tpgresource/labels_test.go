@@ -0,0 +1,102 @@
+package tpgresource_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-provider-google/google/tpgresource"
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+)
+
+func TestAddTerraformAttributionLabel(t *testing.T) {
+	attribution := map[string]string{transport_tpg.AttributionKey: transport_tpg.AttributionValue}
+
+	cases := map[string]struct {
+		Enabled         bool
+		Strategy        string
+		EffectiveLabels map[string]any
+		IsNew           bool
+		Expected        map[string]string
+	}{
+		"disabled": {
+			Enabled:  false,
+			Strategy: transport_tpg.ProactiveAttributionStrategy,
+			IsNew:    true,
+			Expected: map[string]string{},
+		},
+		"creation only for new resources": {
+			Enabled:  true,
+			Strategy: transport_tpg.CreateOnlyAttributionStrategy,
+			IsNew:    true,
+			Expected: attribution,
+		},
+		"creation only for existing resources": {
+			Enabled:  true,
+			Strategy: transport_tpg.CreateOnlyAttributionStrategy,
+			IsNew:    false,
+			Expected: map[string]string{},
+		},
+		"creation only for existing resources with the label": {
+			Enabled:         true,
+			Strategy:        transport_tpg.CreateOnlyAttributionStrategy,
+			EffectiveLabels: map[string]any{transport_tpg.AttributionKey: transport_tpg.AttributionValue},
+			IsNew:           false,
+			Expected:        attribution,
+		},
+		"proactive for existing resources": {
+			Enabled:  true,
+			Strategy: transport_tpg.ProactiveAttributionStrategy,
+			IsNew:    false,
+			Expected: attribution,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			config := &transport_tpg.Config{
+				AddTerraformAttributionLabel:              tc.Enabled,
+				TerraformAttributionLabelAdditionStrategy: tc.Strategy,
+			}
+			terraformLabels := map[string]string{}
+			tpgresource.AddTerraformAttributionLabel(terraformLabels, tc.EffectiveLabels, tc.IsNew, config)
+			if !reflect.DeepEqual(terraformLabels, tc.Expected) {
+				t.Fatalf("expected terraform labels %v, got %v", tc.Expected, terraformLabels)
+			}
+		})
+	}
+}
+
+func TestSetDataSourceItemLabels(t *testing.T) {
+	cases := map[string]struct {
+		Item     map[string]interface{}
+		Expected map[string]interface{}
+	}{
+		"all of the labels": {
+			Item: map[string]interface{}{
+				"name":             "foo",
+				"labels":           map[string]interface{}{},
+				"terraform_labels": map[string]interface{}{},
+				"effective_labels": map[string]interface{}{"env": "test", "goog-terraform-provisioned": "true"},
+			},
+			Expected: map[string]interface{}{
+				"name":             "foo",
+				"labels":           map[string]interface{}{"env": "test", "goog-terraform-provisioned": "true"},
+				"terraform_labels": map[string]interface{}{"env": "test", "goog-terraform-provisioned": "true"},
+				"effective_labels": map[string]interface{}{"env": "test", "goog-terraform-provisioned": "true"},
+			},
+		},
+		"no labels": {
+			Item:     map[string]interface{}{"name": "foo"},
+			Expected: map[string]interface{}{"name": "foo"},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			tpgresource.SetDataSourceItemLabels(tc.Item)
+			if !reflect.DeepEqual(tc.Item, tc.Expected) {
+				t.Fatalf("expected item %v, got %v", tc.Expected, tc.Item)
+			}
+		})
+	}
+}
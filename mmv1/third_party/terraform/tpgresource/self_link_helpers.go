@@ -3,11 +3,11 @@ package tpgresource
 import (
 	"errors"
 	"fmt"
-	"net/url"
 	"regexp"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-provider-google/google/resourcename"
 	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
 )
 
@@ -142,31 +142,22 @@ func getResourcePropertiesFromSelfLinkOrSchema(d *schema.ResourceData, config *t
 
 // given a full locational (non-global) self link, returns the project + region/zone + name or an error
 func GetLocationalResourcePropertiesFromSelfLinkString(selfLink string) (string, string, string, error) {
-	parsed, err := url.Parse(selfLink)
+	name, err := resourcename.Parse(selfLink)
 	if err != nil {
 		return "", "", "", err
 	}
 
-	s := strings.Split(parsed.Path, "/")
-
-	// This is a pretty bad way to tell if this is a self link, but stops us
-	// from accessing an index out of bounds and causing a panic. generally, we
-	// expect bad values to be partial URIs and names, so this will catch them
-	if len(s) < 9 {
+	if name.Project() == "" || name.Location() == "" || name.Location() == resourcename.GlobalCollection {
 		return "", "", "", fmt.Errorf("value %s was not a self link", selfLink)
 	}
 
-	return s[4], s[6], s[8], nil
+	return name.Project(), name.Location(), name.Name(), nil
 }
 
 // This function supports selflinks that have regions and locations in their paths
 func GetRegionFromRegionalSelfLink(selfLink string) string {
-	re := regexp.MustCompile("projects/[a-zA-Z0-9-]*/(?:locations|regions)/([a-zA-Z0-9-]*)")
-	switch {
-	case re.MatchString(selfLink):
-		if res := re.FindStringSubmatch(selfLink); len(res) == 2 && res[1] != "" {
-			return res[1]
-		}
+	if name, err := resourcename.Parse(selfLink); err == nil && name.Project() != "" && name.Region() != "" {
+		return name.Region()
 	}
 	return selfLink
 }
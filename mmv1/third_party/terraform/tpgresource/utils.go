@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
+	"net/http"
 	"net/url"
 	"reflect"
 	"regexp"
@@ -119,6 +120,12 @@ func GetProjectFromDiff(d *schema.ResourceDiff, config *transport_tpg.Config) (s
 // back to the provider's value if not given. If the provider's value is not
 // given, an error is returned.
 func GetRegionFromDiff(d *schema.ResourceDiff, config *transport_tpg.Config) (string, error) {
+	return getRegionFromDiff(d, config.Region)
+}
+
+// getRegionFromDiff reads the "region" field from the given diff and falls
+// back to the given default region if not given.
+func getRegionFromDiff(d *schema.ResourceDiff, defaultRegion string) (string, error) {
 	res, ok := d.GetOk("region")
 	if ok {
 		return res.(string), nil
@@ -126,8 +133,8 @@ func GetRegionFromDiff(d *schema.ResourceDiff, config *transport_tpg.Config) (st
 	if d.GetRawConfig().GetAttr("region") == cty.UnknownVal(cty.String) {
 		return res.(string), nil
 	}
-	if config.Region != "" {
-		return config.Region, nil
+	if defaultRegion != "" {
+		return defaultRegion, nil
 	}
 	return "", fmt.Errorf("%s: required field is not set", "region")
 }
@@ -584,13 +591,34 @@ func GenerateUserAgentString(d TerraformResourceData, currentUserAgent string) (
 		return currentUserAgent, err
 	}
 
-	if m.ModuleName != "" {
-		return strings.Join([]string{currentUserAgent, m.ModuleName}, " "), nil
+	if product := m.UserAgentProduct(); product != "" {
+		return strings.Join([]string{currentUserAgent, product}, " "), nil
 	}
 
 	return currentUserAgent, nil
 }
 
+// GenerateRequestHeaders returns the headers of the requests made for a
+// resource, appending the request_reason of the module's provider_meta block,
+// if any, to the request_reason of the provider configuration in the
+// X-Goog-Request-Reason header. Errors reading provider_meta are returned by
+// GenerateUserAgentString.
+func GenerateRequestHeaders(d TerraformResourceData, currentRequestReason string) http.Header {
+	headers := make(http.Header)
+
+	var m transport_tpg.ProviderMeta
+	if err := d.GetProviderMeta(&m); err != nil || m.RequestReason == "" {
+		return headers
+	}
+
+	requestReason := m.RequestReason
+	if currentRequestReason != "" {
+		requestReason = strings.Join([]string{currentRequestReason, m.RequestReason}, "; ")
+	}
+	headers.Set("X-Goog-Request-Reason", requestReason)
+	return headers
+}
+
 func SnakeToPascalCase(s string) string {
 	split := strings.Split(s, "_")
 	for i := range split {
@@ -887,6 +915,27 @@ func DefaultProviderRegion(_ context.Context, diff *schema.ResourceDiff, meta in
 	return nil
 }
 
+// DefaultProviderServiceRegion returns a CustomizeDiffFunc like
+// DefaultProviderRegion for the resources of the given service, named like in
+// service_default_regions, defaulting to the region of the service if set.
+func DefaultProviderServiceRegion(service string) schema.CustomizeDiffFunc {
+	return func(_ context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+		config := meta.(*transport_tpg.Config)
+		if region := diff.Get("region"); region != nil {
+			region, err := getRegionFromDiff(diff, config.ServiceRegion(service))
+			if err != nil {
+				return fmt.Errorf("Failed to retrieve region, pid: %s, err: %s", region, err)
+			}
+			err = diff.SetNew("region", region)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
 func DefaultProviderZone(_ context.Context, diff *schema.ResourceDiff, meta interface{}) error {
 
 	config := meta.(*transport_tpg.Config)
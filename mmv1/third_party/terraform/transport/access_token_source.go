@@ -0,0 +1,107 @@
+package transport
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+	"golang.org/x/oauth2"
+
+	"github.com/hashicorp/terraform-provider-google/google/verify"
+)
+
+// AccessTokenSource returns a token source for the access_token provider
+// configuration, which is either an access token or the path of a file
+// containing one. Files are read again when they change.
+func AccessTokenSource(accessToken string) (oauth2.TokenSource, error) {
+	contents, fromPath, err := verify.PathOrContents(accessToken)
+	if err != nil {
+		return nil, err
+	}
+	if !fromPath {
+		return StaticTokenSource{oauth2.StaticTokenSource(&oauth2.Token{AccessToken: contents})}, nil
+	}
+
+	path, err := homedir.Expand(accessToken)
+	if err != nil {
+		return nil, err
+	}
+	return NewFileTokenSource(path, DefaultFileTokenLifetime)
+}
+
+// DefaultFileTokenLifetime is how long tokens read from access token files
+// are used before the file is checked again by token sources reusing tokens
+// until they expire.
+const DefaultFileTokenLifetime = time.Minute
+
+// FileTokenSource reads access tokens from a file, and reads it again when it
+// changes, so that tokens rotated by the environment are picked up before the
+// previous ones expire during long runs. Files don't tell when their tokens
+// expire, so tokens are given a short synthetic expiry. Without one, token
+// sources reusing tokens until they expire, e.g. the one of oauth2.NewClient,
+// would use the first token forever.
+type FileTokenSource struct {
+	path     string
+	lifetime time.Duration
+
+	mu      sync.Mutex
+	modTime time.Time
+	token   *oauth2.Token
+}
+
+// NewFileTokenSource returns a token source for the access token file at
+// the given path, failing if it can't be read. Tokens expire lifetime after
+// they are returned.
+func NewFileTokenSource(path string, lifetime time.Duration) (*FileTokenSource, error) {
+	s := &FileTokenSource{path: path, lifetime: lifetime}
+	if _, err := s.Token(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *FileTokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	info, err := os.Stat(s.path)
+	if err != nil {
+		// The file may be briefly missing while it's replaced.
+		if s.token != nil {
+			return s.expiringToken(), nil
+		}
+		return nil, fmt.Errorf("error reading access token file %q: %s", s.path, err)
+	}
+	if s.token != nil && info.ModTime().Equal(s.modTime) {
+		return s.expiringToken(), nil
+	}
+
+	contents, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading access token file %q: %s", s.path, err)
+	}
+	accessToken := strings.TrimSpace(string(contents))
+	if accessToken == "" {
+		// The file may be briefly empty while it's rewritten.
+		if s.token != nil {
+			return s.expiringToken(), nil
+		}
+		return nil, fmt.Errorf("access token file %q is empty", s.path)
+	}
+
+	s.modTime = info.ModTime()
+	s.token = &oauth2.Token{AccessToken: accessToken}
+	return s.expiringToken(), nil
+}
+
+// Returns a copy of the current token expiring after the lifetime of the
+// source, as callers may keep the returned tokens.
+func (s *FileTokenSource) expiringToken() *oauth2.Token {
+	return &oauth2.Token{
+		AccessToken: s.token.AccessToken,
+		Expiry:      time.Now().Add(s.lifetime),
+	}
+}
@@ -0,0 +1,177 @@
+package transport_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+)
+
+func TestAccessTokenSource_value(t *testing.T) {
+	ts, err := transport_tpg.AccessTokenSource("my-access-token")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, ok := ts.(transport_tpg.StaticTokenSource); !ok {
+		t.Fatalf("expected a static token source, got %T", ts)
+	}
+	token, err := ts.Token()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if token.AccessToken != "my-access-token" {
+		t.Fatalf("expected access token %q, got %q", "my-access-token", token.AccessToken)
+	}
+}
+
+func TestAccessTokenSource_file(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	writeToken := func(token string, modTime time.Time) {
+		if err := os.WriteFile(path, []byte(token+"\n"), 0600); err != nil {
+			t.Fatalf("cannot write token file: %s", err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatalf("cannot set modification time of token file: %s", err)
+		}
+	}
+
+	now := time.Now()
+	writeToken("first-token", now)
+
+	ts, err := transport_tpg.AccessTokenSource(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	cases := []struct {
+		description string
+		update      func()
+		expected    string
+	}{
+		{
+			description: "token is read from the file",
+			update:      func() {},
+			expected:    "first-token",
+		},
+		{
+			description: "rotated token is read again",
+			update:      func() { writeToken("second-token", now.Add(time.Minute)) },
+			expected:    "second-token",
+		},
+		{
+			description: "previous token is kept while the file is rewritten",
+			update:      func() { writeToken("", now.Add(2*time.Minute)) },
+			expected:    "second-token",
+		},
+		{
+			description: "previous token is kept while the file is replaced",
+			update: func() {
+				if err := os.Remove(path); err != nil {
+					t.Fatalf("cannot remove token file: %s", err)
+				}
+			},
+			expected: "second-token",
+		},
+	}
+
+	for _, tc := range cases {
+		tc.update()
+		token, err := ts.Token()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", tc.description, err)
+		}
+		if token.AccessToken != tc.expected {
+			t.Fatalf("%s: expected access token %q, got %q", tc.description, tc.expected, token.AccessToken)
+		}
+	}
+}
+
+func TestAccessTokenSource_fileExpiry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("my-access-token"), 0600); err != nil {
+		t.Fatalf("cannot write token file: %s", err)
+	}
+
+	ts, err := transport_tpg.AccessTokenSource(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	token, err := ts.Token()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if token.Expiry.IsZero() || time.Until(token.Expiry) > transport_tpg.DefaultFileTokenLifetime {
+		t.Fatalf("expected the token to expire within %s, got expiry %s", transport_tpg.DefaultFileTokenLifetime, token.Expiry)
+	}
+}
+
+// Clients of oauth2.NewClient reuse tokens until shortly before they expire,
+// and must pick up rotated tokens once they do.
+func TestFileTokenSource_client(t *testing.T) {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "token")
+	writeToken := func(token string, modTime time.Time) {
+		if err := os.WriteFile(path, []byte(token), 0600); err != nil {
+			t.Fatalf("cannot write token file: %s", err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatalf("cannot set modification time of token file: %s", err)
+		}
+	}
+	get := func(client *http.Client) string {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		resp.Body.Close()
+		return authorization
+	}
+
+	now := time.Now()
+	writeToken("first-token", now)
+
+	// oauth2.ReuseTokenSource refreshes tokens 10 seconds before they
+	// expire, so tokens are reused for a second.
+	ts, err := transport_tpg.NewFileTokenSource(path, 11*time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	client := oauth2.NewClient(context.Background(), ts)
+
+	if got := get(client); got != "Bearer first-token" {
+		t.Fatalf("expected the first token, got %q", got)
+	}
+
+	writeToken("second-token", now.Add(time.Minute))
+	if got := get(client); got != "Bearer first-token" {
+		t.Fatalf("expected the first token to be reused until it expires, got %q", got)
+	}
+
+	time.Sleep(1500 * time.Millisecond)
+	if got := get(client); got != "Bearer second-token" {
+		t.Fatalf("expected the rotated token once the first one expired, got %q", got)
+	}
+}
+
+func TestAccessTokenSource_emptyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, nil, 0600); err != nil {
+		t.Fatalf("cannot write token file: %s", err)
+	}
+
+	if _, err := transport_tpg.AccessTokenSource(path); err == nil {
+		t.Fatalf("expected an error for an empty access token file")
+	}
+}
@@ -0,0 +1,76 @@
+package transport
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/url"
+
+	"github.com/hashicorp/go-cleanhttp"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// BaseClientConfig configures the HTTP client that authenticated clients are
+// built on, see the custom_ca_certificate, client certificate and proxy
+// provider configuration.
+type BaseClientConfig struct {
+	CustomCACertificate string
+	ClientCertificate   string
+	ClientPrivateKey    string
+	HttpProxy           string
+	HttpsProxy          string
+	NoProxy             string
+}
+
+// NewBaseClient returns a clean HTTP client trusting the custom CA
+// certificates, presenting the client certificate and using the proxies of
+// the configuration, or nil if none is configured. Proxies that aren't
+// configured are read from the environment.
+func NewBaseClient(c BaseClientConfig) (*http.Client, error) {
+	if c == (BaseClientConfig{}) {
+		return nil, nil
+	}
+
+	transport := cleanhttp.DefaultPooledTransport()
+	if c.CustomCACertificate != "" || c.ClientCertificate != "" {
+		transport.TLSClientConfig = &tls.Config{
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+	if c.CustomCACertificate != "" {
+		pool, err := CustomCACertPool(c.CustomCACertificate)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig.RootCAs = pool
+	}
+	if c.ClientCertificate != "" {
+		cert, err := ClientCertificate(c.ClientCertificate, c.ClientPrivateKey)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig.Certificates = []tls.Certificate{cert}
+	}
+	if c.HttpProxy != "" || c.HttpsProxy != "" || c.NoProxy != "" {
+		proxyFunc := c.proxyConfig().ProxyFunc()
+		transport.Proxy = func(req *http.Request) (*url.URL, error) {
+			return proxyFunc(req.URL)
+		}
+	}
+	return &http.Client{Transport: transport}, nil
+}
+
+// proxyConfig returns the proxy configuration of the environment, overridden
+// by the configured proxies.
+func (c BaseClientConfig) proxyConfig() *httpproxy.Config {
+	config := httpproxy.FromEnvironment()
+	if c.HttpProxy != "" {
+		config.HTTPProxy = c.HttpProxy
+	}
+	if c.HttpsProxy != "" {
+		config.HTTPSProxy = c.HttpsProxy
+	}
+	if c.NoProxy != "" {
+		config.NoProxy = c.NoProxy
+	}
+	return config
+}
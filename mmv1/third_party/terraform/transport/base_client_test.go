@@ -0,0 +1,155 @@
+package transport_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+)
+
+func TestNewBaseClient_default(t *testing.T) {
+	client, err := transport_tpg.NewBaseClient(transport_tpg.BaseClientConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if client != nil {
+		t.Fatalf("expected no base client without configuration")
+	}
+}
+
+func TestNewBaseClient_customCACertificate(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	caCertificate := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, []byte(caCertificate), 0600); err != nil {
+		t.Fatalf("cannot write CA file: %s", err)
+	}
+
+	cases := map[string]string{
+		"PEM contents": caCertificate,
+		"path":         caFile,
+	}
+	for tn, value := range cases {
+		t.Run(tn, func(t *testing.T) {
+			client, err := transport_tpg.NewBaseClient(transport_tpg.BaseClientConfig{CustomCACertificate: value})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			res, err := client.Get(server.URL)
+			if err != nil {
+				t.Fatalf("expected the server certificate to be trusted, got: %s", err)
+			}
+			res.Body.Close()
+		})
+	}
+
+	if _, err := transport_tpg.NewBaseClient(transport_tpg.BaseClientConfig{CustomCACertificate: "not a certificate"}); err == nil {
+		t.Fatalf("expected an error for contents without certificates")
+	}
+}
+
+func TestNewBaseClient_clientCertificate(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("cannot generate key: %s", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("cannot create certificate: %s", err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("cannot marshal key: %s", err)
+	}
+	certificate := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	privateKey := string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}))
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	caCertificate := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+	client, err := transport_tpg.NewBaseClient(transport_tpg.BaseClientConfig{
+		CustomCACertificate: caCertificate,
+		ClientCertificate:   certificate,
+		ClientPrivateKey:    privateKey,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	res, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("expected the client certificate to be presented, got: %s", err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatalf("cannot read response: %s", err)
+	}
+	if string(body) != "client" {
+		t.Fatalf("expected the client certificate to be presented, got %q", body)
+	}
+
+	if _, err := transport_tpg.NewBaseClient(transport_tpg.BaseClientConfig{ClientCertificate: certificate, ClientPrivateKey: "not a key"}); err == nil {
+		t.Fatalf("expected an error for an invalid private key")
+	}
+}
+
+func TestNewBaseClient_proxy(t *testing.T) {
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("proxied " + r.URL.Host))
+	}))
+	defer proxy.Close()
+
+	client, err := transport_tpg.NewBaseClient(transport_tpg.BaseClientConfig{
+		HttpProxy: proxy.URL,
+		NoProxy:   "direct.example.com",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	res, err := client.Get("http://compute.example.com/")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatalf("cannot read response: %s", err)
+	}
+	if string(body) != "proxied compute.example.com" {
+		t.Fatalf("expected the request to be proxied, got %q", body)
+	}
+}
@@ -0,0 +1,129 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// BatchType declares how a kind of requests is batched, i.e. its batch key and
+// how the bodies of its requests are combined, so that requests are batched
+// without handwriting a BatchRequest and the type assertions of its CombineF
+// and SendF for each kind of requests, e.g. from generated code:
+//
+//	var batchTypeEnableServices = transport_tpg.BatchType[[]string]{
+//		Name:        "Enable Project Services",
+//		KeyTemplate: "project/%s/services:batchEnable",
+//		Combine:     transport_tpg.AppendBatchBodies[string],
+//	}
+type BatchType[T any] struct {
+	// Name describes the requests, e.g. "Enable Project Services", for
+	// debugging.
+	Name string
+
+	// KeyTemplate is the format of the batch key of the requests, given their
+	// resource name, see SendRequestWithTimeout.
+	KeyTemplate string
+
+	// Combine combines the bodies of two requests, see BatcherCombineFunc.
+	Combine func(body T, toAdd T) (T, error)
+}
+
+// BatchKey returns the batch key of the requests of the given resource.
+func (bt BatchType[T]) BatchKey(resourceName string) string {
+	return fmt.Sprintf(bt.KeyTemplate, resourceName)
+}
+
+// Send sends a request of the batch type with the given batcher and waits for
+// its result. send sends the batched requests, with the combined bodies of
+// the requests of the batch.
+func (bt BatchType[T]) Send(batcher *RequestBatcher, resourceName string, body T, send func(resourceName string, body T) (interface{}, error), debugId string, timeout time.Duration) (interface{}, error) {
+	if bt.Combine == nil {
+		return nil, fmt.Errorf("provider error: batch type %q has no Combine function", bt.Name)
+	}
+	if batcher == nil {
+		return nil, fmt.Errorf("provider error: no batcher for batch type %q", bt.Name)
+	}
+
+	req := &BatchRequest{
+		ResourceName: resourceName,
+		Body:         body,
+		CombineF: func(bodyRaw interface{}, toAddRaw interface{}) (interface{}, error) {
+			body, err := bt.assertBody(bodyRaw)
+			if err != nil {
+				return nil, err
+			}
+			toAdd, err := bt.assertBody(toAddRaw)
+			if err != nil {
+				return nil, err
+			}
+			return bt.Combine(body, toAdd)
+		},
+		SendF: func(resourceName string, bodyRaw interface{}) (interface{}, error) {
+			body, err := bt.assertBody(bodyRaw)
+			if err != nil {
+				return nil, err
+			}
+			return send(resourceName, body)
+		},
+		DebugId: debugId,
+	}
+	return batcher.SendRequestWithTimeout(bt.BatchKey(resourceName), req, timeout)
+}
+
+func (bt BatchType[T]) assertBody(bodyRaw interface{}) (T, error) {
+	body, ok := bodyRaw.(T)
+	if !ok {
+		return body, fmt.Errorf("provider error: expected the body of batch type %q to be type %T, got %v with type %T", bt.Name, body, bodyRaw, bodyRaw)
+	}
+	return body, nil
+}
+
+// AppendBatchBodies combines request bodies that are lists of items, e.g.
+// services to enable, by appending them.
+func AppendBatchBodies[E any](body []E, toAdd []E) ([]E, error) {
+	return append(body, toAdd...), nil
+}
+
+// KeepBatchBody combines request bodies that are the same for every request of
+// a batch, e.g. of requests listing the services of a project, by keeping the
+// first one.
+func KeepBatchBody[T any](body T, _ T) (T, error) {
+	return body, nil
+}
+
+// RequestBatchers holds the batchers of the provider by name, created on
+// first use with the provider batching config, for the kinds of requests
+// without a dedicated batcher such as RequestBatcherIam.
+type RequestBatchers struct {
+	mu       sync.Mutex
+	ctx      context.Context
+	config   *BatchingConfig
+	batchers map[string]*RequestBatcher
+}
+
+// NewRequestBatchers returns the batchers of a provider with the given parent
+// context and batching config.
+func NewRequestBatchers(ctx context.Context, config *BatchingConfig) *RequestBatchers {
+	return &RequestBatchers{
+		ctx:      ctx,
+		config:   config,
+		batchers: make(map[string]*RequestBatcher),
+	}
+}
+
+// Get returns the batcher of the given name, e.g. "Tag Bindings", creating it
+// if needed. Requests of different services should use different batchers,
+// see RequestBatcher.
+func (bs *RequestBatchers) Get(name string) *RequestBatcher {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	if batcher, ok := bs.batchers[name]; ok {
+		return batcher
+	}
+	batcher := NewRequestBatcher(name, bs.ctx, bs.config)
+	bs.batchers[name] = batcher
+	return batcher
+}
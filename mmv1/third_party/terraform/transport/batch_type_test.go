@@ -0,0 +1,94 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBatchType_send(t *testing.T) {
+	testBatcher := NewRequestBatcher(
+		"testBatcher",
+		context.Background(),
+		&BatchingConfig{
+			SendAfter:      time.Duration(1) * time.Second,
+			EnableBatching: true,
+		})
+
+	testBatchType := BatchType[[]string]{
+		Name:        "Test Batch Type",
+		KeyTemplate: "test/%s:batch",
+		Combine:     AppendBatchBodies[string],
+	}
+
+	var sent [][]string
+	var sentMu sync.Mutex
+	testSend := func(name string, body []string) (interface{}, error) {
+		sentMu.Lock()
+		defer sentMu.Unlock()
+		sent = append(sent, body)
+		return fmt.Sprintf("%s: %d", name, len(body)), nil
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(3)
+	for i := 0; i < 3; i++ {
+		go func(idx int) {
+			defer wg.Done()
+
+			respV, err := testBatchType.Send(testBatcher, "testBatchType", []string{fmt.Sprint(idx)}, testSend, fmt.Sprintf("Test Batch Type Request #%d", idx), time.Duration(6)*time.Second)
+			if err != nil {
+				t.Errorf("got unexpected error %s", err)
+			}
+			if resp, ok := respV.(string); !ok || resp != "testBatchType: 3" {
+				t.Errorf("expected the response of a batch of 3 requests, got %v", respV)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if len(sent) != 1 || len(sent[0]) != 3 {
+		t.Errorf("expected a single batch of 3 requests to be sent, got %v", sent)
+	}
+}
+
+func TestBatchType_errNoCombine(t *testing.T) {
+	testBatcher := NewRequestBatcher(
+		"testBatcher",
+		context.Background(),
+		&BatchingConfig{
+			SendAfter:      time.Duration(1) * time.Second,
+			EnableBatching: true,
+		})
+
+	testBatchType := BatchType[int]{
+		Name:        "Test Batch Type",
+		KeyTemplate: "test/%s:batch",
+	}
+
+	_, err := testBatchType.Send(testBatcher, "testBatchType", 1, func(string, int) (interface{}, error) { return nil, nil }, "Test Batch Type Request", time.Duration(6)*time.Second)
+	if err == nil || !strings.Contains(err.Error(), "no Combine function") {
+		t.Errorf("expected an error for the missing Combine function, got %v", err)
+	}
+}
+
+func TestRequestBatchers_get(t *testing.T) {
+	batchers := NewRequestBatchers(context.Background(), &BatchingConfig{
+		SendAfter:      time.Duration(1) * time.Second,
+		EnableBatching: true,
+	})
+
+	tagBindings := batchers.Get("Tag Bindings")
+	if tagBindings == nil || tagBindings.debugId != "Tag Bindings" {
+		t.Fatalf("expected a batcher named %q, got %v", "Tag Bindings", tagBindings)
+	}
+	if batchers.Get("Tag Bindings") != tagBindings {
+		t.Errorf("expected the same batcher for the same name")
+	}
+	if batchers.Get("Org Policies") == tagBindings {
+		t.Errorf("expected a different batcher for another name")
+	}
+}
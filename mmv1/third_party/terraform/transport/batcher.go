@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net/http"
 	"sync"
 	"time"
 
 	"github.com/hashicorp/errwrap"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 const DefaultBatchSendIntervalSec = 3
@@ -24,6 +26,26 @@ type RequestBatcher struct {
 	parentCtx context.Context
 	batches   map[string]*startedBatch
 	debugId   string
+	// pausedUntil is the time until which requests aren't sent, see Pause.
+	pausedUntil time.Time
+	// logCtx is the context of the tflog events of the batcher, see
+	// SetLogContext.
+	logCtx context.Context
+	stats  batcherStats
+}
+
+// batcherStats are the totals of the batches sent by a RequestBatcher, logged
+// to help tuning send_after and max_batch_size.
+type batcherStats struct {
+	batches       int
+	requests      int
+	maxBatchSize  int
+	maxQueueDepth int
+	earlyFlushes  int
+	splitBatches  int
+	totalQueued   time.Duration
+	maxQueued     time.Duration
+	totalSend     time.Duration
 }
 
 // These types are meant to be the public interface to batchers. They define
@@ -85,6 +107,10 @@ type startedBatch struct {
 	subscribers []batchSubscriber
 
 	timer *time.Timer
+
+	// startedAt is the time the batch was started, to log how long requests
+	// wait to be sent.
+	startedAt time.Time
 }
 
 // batchSubscriber contains information required for a single request for a startedBatch.
@@ -100,6 +126,9 @@ type batchSubscriber struct {
 type BatchingConfig struct {
 	SendAfter      time.Duration
 	EnableBatching bool
+	// MaxBatchSize is the number of requests after which a batch is sent
+	// without waiting for SendAfter, 0 for no limit.
+	MaxBatchSize int
 }
 
 // Initializes a new batcher.
@@ -109,6 +138,7 @@ func NewRequestBatcher(debugId string, ctx context.Context, config *BatchingConf
 		parentCtx:      ctx,
 		BatchingConfig: config,
 		batches:        make(map[string]*startedBatch),
+		logCtx:         ctx,
 	}
 
 	// Start goroutine to managing stopping the batcher if the provider-level parent context is closed.
@@ -123,11 +153,22 @@ func NewRequestBatcher(debugId string, ctx context.Context, config *BatchingConf
 	return batcher
 }
 
+// SetLogContext sets the context of the tflog events of the batcher, which
+// defaults to its parent context, for parent contexts without a provider
+// logger.
+func (b *RequestBatcher) SetLogContext(ctx context.Context) {
+	b.Lock()
+	defer b.Unlock()
+
+	b.logCtx = ctx
+}
+
 func (b *RequestBatcher) stop() {
 	b.Lock()
 	defer b.Unlock()
 
 	log.Printf("[DEBUG] Stopping batcher %q", b.debugId)
+	b.logSummary()
 	for batchKey, batch := range b.batches {
 		log.Printf("[DEBUG] Cancelling started batch for batchKey %q", batchKey)
 		batch.timer.Stop()
@@ -163,6 +204,7 @@ func (b *RequestBatcher) SendRequestWithTimeout(batchKey string, request *BatchR
 	}
 	if !b.EnableBatching {
 		log.Printf("[DEBUG] Batching is disabled, sending single request for %q", request.DebugId)
+		b.waitIfPaused()
 		return request.SendF(request.ResourceName, request.Body)
 	}
 
@@ -215,7 +257,13 @@ func (b *RequestBatcher) registerBatchRequest(batchKey string, newRequest *Batch
 
 	// If batch already exists, combine this request into existing request.
 	if batch, ok := b.batches[batchKey]; ok {
-		return batch.addRequest(newRequest)
+		respCh, err := batch.addRequest(newRequest)
+		if err != nil {
+			return nil, err
+		}
+		b.logQueuedRequest(batchKey, newRequest, batch)
+		b.sendBatchIfFull(batchKey, batch)
+		return respCh, nil
 	}
 
 	// Batch doesn't exist for given batch key - create a new batch.
@@ -240,6 +288,7 @@ func (b *RequestBatcher) registerBatchRequest(batchKey string, newRequest *Batch
 		},
 		batchKey:    batchKey,
 		subscribers: []batchSubscriber{sub},
+		startedAt:   time.Now(),
 	}
 
 	// Start a timer to send the request
@@ -253,16 +302,94 @@ func (b *RequestBatcher) registerBatchRequest(batchKey string, newRequest *Batch
 			b.sendBatchWithSingleRetry(batchKey, batch)
 		}
 	})
+	b.logQueuedRequest(batchKey, newRequest, b.batches[batchKey])
+	b.sendBatchIfFull(batchKey, b.batches[batchKey])
 
 	return respCh, nil
 }
 
+// sendBatchIfFull sends a batch without waiting for its timer once it reaches
+// the maximum batch size. It must be called with the RequestBatcher locked.
+func (b *RequestBatcher) sendBatchIfFull(batchKey string, batch *startedBatch) {
+	if b.MaxBatchSize <= 0 || len(batch.subscribers) < b.MaxBatchSize {
+		return
+	}
+
+	// If the timer already fired, the batch is about to be sent anyway.
+	if !batch.timer.Stop() {
+		return
+	}
+
+	log.Printf("[DEBUG] Batch %q reached the maximum batch size of %d, sending it early", batchKey, b.MaxBatchSize)
+	b.stats.earlyFlushes++
+	delete(b.batches, batchKey)
+	go b.sendBatchWithSingleRetry(batchKey, batch)
+}
+
+// Pause delays sending requests until the given delay elapsed, e.g. when the
+// service asked to retry requests after it. Requests being sent aren't
+// affected.
+func (b *RequestBatcher) Pause(delay time.Duration) {
+	b.Lock()
+	defer b.Unlock()
+
+	if until := time.Now().Add(delay); until.After(b.pausedUntil) {
+		log.Printf("[DEBUG] Pausing batcher %q for %s", b.debugId, delay)
+		b.pausedUntil = until
+	}
+}
+
+// PauseBatchersOnServerDelay returns a RetryConfig.OnServerDelay function
+// pausing the batcher of the service retried requests are sent to, out of the
+// batchers by base path returned by batchers, see matchBasePath. Batchers are
+// looked up when requests are retried, as they may be created after the HTTP
+// client.
+func PauseBatchersOnServerDelay(batchers func() map[string]*RequestBatcher) func(*http.Request, time.Duration) {
+	return func(req *http.Request, delay time.Duration) {
+		byBasePath := batchers()
+		basePaths := make([]string, 0, len(byBasePath))
+		for basePath, batcher := range byBasePath {
+			if basePath != "" && batcher != nil {
+				basePaths = append(basePaths, basePath)
+			}
+		}
+		if basePath, ok := matchBasePath(req.URL, sortBasePaths(basePaths)); ok {
+			byBasePath[basePath].Pause(delay)
+		}
+	}
+}
+
+// waitIfPaused blocks until the batcher isn't paused, or its parent context is
+// done.
+func (b *RequestBatcher) waitIfPaused() {
+	b.Lock()
+	wait := time.Until(b.pausedUntil)
+	b.Unlock()
+
+	if wait <= 0 {
+		return
+	}
+	log.Printf("[DEBUG] Batcher %q is paused, waiting %s before sending requests", b.debugId, wait)
+	select {
+	case <-time.After(wait):
+	case <-b.parentCtx.Done():
+	}
+}
+
 func (b *RequestBatcher) sendBatchWithSingleRetry(batchKey string, batch *startedBatch) {
+	b.waitIfPaused()
+
 	log.Printf("[DEBUG] Sending batch %q combining %d requests)", batchKey, len(batch.subscribers))
+	queued := time.Since(batch.startedAt)
+	sendStart := time.Now()
 	resp := batch.send()
 
 	// If the batch failed and combines more than one request, retry each single request.
-	if resp.IsError() && len(batch.subscribers) > 1 {
+	split := resp.IsError() && len(batch.subscribers) > 1
+	defer func() {
+		b.logSentBatch(batchKey, batch, queued, time.Since(sendStart), split)
+	}()
+	if split {
 		log.Printf("[DEBUG] Batch failed with error: %v", resp.err)
 		log.Printf("[DEBUG] Sending each request in batch separately")
 		for _, sub := range batch.subscribers {
@@ -303,6 +430,90 @@ func (b *RequestBatcher) popBatch(batchKey string) *startedBatch {
 	return batch
 }
 
+// logQueuedRequest logs a request added to the given batch, and the number of
+// requests waiting in all batches. It must be called with the RequestBatcher
+// locked.
+func (b *RequestBatcher) logQueuedRequest(batchKey string, request *BatchRequest, batch *startedBatch) {
+	queueDepth := 0
+	for _, batch := range b.batches {
+		queueDepth += len(batch.subscribers)
+	}
+	if queueDepth > b.stats.maxQueueDepth {
+		b.stats.maxQueueDepth = queueDepth
+	}
+
+	tflog.Debug(b.logCtx, "Queued batch request", map[string]interface{}{
+		"batcher":         b.debugId,
+		"batch_key":       batchKey,
+		"request":         request.DebugId,
+		"batch_size":      len(batch.subscribers),
+		"pending_batches": len(b.batches),
+		"queue_depth":     queueDepth,
+		"send_after":      b.SendAfter.String(),
+	})
+}
+
+// logSentBatch records and logs a sent batch, with how long its first request
+// waited to be sent and how long sending it took, and logs a summary once no
+// batches are left to send.
+func (b *RequestBatcher) logSentBatch(batchKey string, batch *startedBatch, queued, sent time.Duration, split bool) {
+	b.Lock()
+	defer b.Unlock()
+
+	size := len(batch.subscribers)
+	b.stats.batches++
+	b.stats.requests += size
+	if size > b.stats.maxBatchSize {
+		b.stats.maxBatchSize = size
+	}
+	if split {
+		b.stats.splitBatches++
+	}
+	b.stats.totalQueued += queued
+	if queued > b.stats.maxQueued {
+		b.stats.maxQueued = queued
+	}
+	b.stats.totalSend += sent
+
+	tflog.Debug(b.logCtx, "Sent batch", map[string]interface{}{
+		"batcher":          b.debugId,
+		"batch_key":        batchKey,
+		"batch_size":       size,
+		"queued_ms":        queued.Milliseconds(),
+		"send_ms":          sent.Milliseconds(),
+		"flush_latency_ms": (queued + sent).Milliseconds(),
+		"split":            split,
+	})
+
+	if len(b.batches) == 0 {
+		b.logSummary()
+	}
+}
+
+// logSummary logs the totals of the batches sent so far. It must be called
+// with the RequestBatcher locked.
+func (b *RequestBatcher) logSummary() {
+	if b.stats.batches == 0 {
+		return
+	}
+
+	tflog.Debug(b.logCtx, "Batcher summary", map[string]interface{}{
+		"batcher":         b.debugId,
+		"send_after":      b.SendAfter.String(),
+		"max_batch_size":  b.MaxBatchSize,
+		"batches":         b.stats.batches,
+		"requests":        b.stats.requests,
+		"avg_batch_size":  float64(b.stats.requests) / float64(b.stats.batches),
+		"largest_batch":   b.stats.maxBatchSize,
+		"max_queue_depth": b.stats.maxQueueDepth,
+		"early_flushes":   b.stats.earlyFlushes,
+		"split_batches":   b.stats.splitBatches,
+		"avg_queued_ms":   (b.stats.totalQueued / time.Duration(b.stats.batches)).Milliseconds(),
+		"max_queued_ms":   b.stats.maxQueued.Milliseconds(),
+		"avg_send_ms":     (b.stats.totalSend / time.Duration(b.stats.batches)).Milliseconds(),
+	})
+}
+
 func (batch *startedBatch) addRequest(newRequest *BatchRequest) (<-chan batchResponse, error) {
 	log.Printf("[DEBUG] Adding batch request %q to existing batch %q", newRequest.DebugId, batch.batchKey)
 	if batch.CombineF == nil {
@@ -1,6 +1,7 @@
 package transport
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -9,6 +10,8 @@ import (
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
 )
 
 func TestRequestBatcher_batchSingle(t *testing.T) {
@@ -245,6 +248,156 @@ func TestRequestBatcher_errTimeout(t *testing.T) {
 	wg.Wait()
 }
 
+func TestRequestBatcher_maxBatchSize(t *testing.T) {
+	testBatcher := NewRequestBatcher(
+		"testBatcher",
+		context.Background(),
+		&BatchingConfig{
+			SendAfter:      time.Duration(1) * time.Minute,
+			EnableBatching: true,
+			MaxBatchSize:   2,
+		})
+
+	testCombine := func(currV interface{}, toAddV interface{}) (interface{}, error) {
+		return currV.(int) + toAddV.(int), nil
+	}
+
+	testSendBatch := func(name string, body interface{}) (interface{}, error) {
+		return fmt.Sprintf("%s: %d", name, body), nil
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(4)
+
+	for i := 0; i < 4; i++ {
+		go func(idx int) {
+			defer wg.Done()
+
+			req := &BatchRequest{
+				DebugId:      fmt.Sprintf("Test Max Batch Size Request #%d", idx),
+				ResourceName: "testMaxBatchSize",
+				Body:         1,
+				CombineF:     testCombine,
+				SendF:        testSendBatch,
+			}
+
+			// Full batches are sent well before SendAfter and the timeout.
+			respV, err := testBatcher.SendRequestWithTimeout("testMaxBatchSize", req, time.Duration(5)*time.Second)
+			if err != nil {
+				t.Errorf("got unexpected error %s", err)
+			}
+			resp, ok := respV.(string)
+			if !ok {
+				t.Errorf("test returned an non-string response: %v", resp)
+			}
+			if resp != "testMaxBatchSize: 2" {
+				t.Errorf("expected response for a batch of 2 requests, got %s", resp)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+}
+
+func TestRequestBatcher_pause(t *testing.T) {
+	testBatcher := NewRequestBatcher(
+		"testBatcher",
+		context.Background(),
+		&BatchingConfig{
+			SendAfter:      time.Duration(10) * time.Millisecond,
+			EnableBatching: true,
+		})
+
+	req := &BatchRequest{
+		DebugId:      "Test Pause Request",
+		ResourceName: "testPause",
+		Body:         1,
+		CombineF: func(currV interface{}, toAddV interface{}) (interface{}, error) {
+			return currV.(int) + toAddV.(int), nil
+		},
+		SendF: func(name string, body interface{}) (interface{}, error) {
+			return fmt.Sprintf("%s: %d", name, body), nil
+		},
+	}
+
+	testBatcher.Pause(500 * time.Millisecond)
+	start := time.Now()
+	if _, err := testBatcher.SendRequestWithTimeout("testPause", req, time.Duration(5)*time.Second); err != nil {
+		t.Fatalf("got unexpected error %s", err)
+	}
+	if elapsed := time.Since(start); elapsed < 500*time.Millisecond {
+		t.Errorf("expected the paused batcher to wait before sending the batch, sent after %s", elapsed)
+	}
+}
+
+func TestRequestBatcher_logging(t *testing.T) {
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+	testBatcher := NewRequestBatcher(
+		"testBatcher",
+		context.Background(),
+		&BatchingConfig{
+			SendAfter:      time.Duration(10) * time.Millisecond,
+			EnableBatching: true,
+		})
+	testBatcher.SetLogContext(ctx)
+
+	wg := sync.WaitGroup{}
+	wg.Add(3)
+	for i := 0; i < 3; i++ {
+		go func(idx int) {
+			defer wg.Done()
+
+			req := &BatchRequest{
+				DebugId:      fmt.Sprintf("Test Logging Request #%d", idx),
+				ResourceName: "testLogging",
+				Body:         1,
+				CombineF: func(currV interface{}, toAddV interface{}) (interface{}, error) {
+					return currV.(int) + toAddV.(int), nil
+				},
+				SendF: func(name string, body interface{}) (interface{}, error) {
+					return fmt.Sprintf("%s: %d", name, body), nil
+				},
+			}
+			if _, err := testBatcher.SendRequestWithTimeout("testLogging", req, time.Duration(5)*time.Second); err != nil {
+				t.Errorf("got unexpected error %s", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// The batch is logged after its requests got their responses.
+	var summary map[string]interface{}
+	for start := time.Now(); summary == nil && time.Since(start) < 5*time.Second; time.Sleep(10 * time.Millisecond) {
+		testBatcher.Lock()
+		entries, err := tflogtest.MultilineJSONDecode(bytes.NewReader(output.Bytes()))
+		testBatcher.Unlock()
+		if err != nil {
+			t.Fatalf("error decoding the log entries: %s", err)
+		}
+
+		queued := 0
+		for _, entry := range entries {
+			switch entry["@message"] {
+			case "Queued batch request":
+				queued++
+			case "Batcher summary":
+				summary = entry
+			}
+		}
+		if summary != nil && queued != 3 {
+			t.Errorf("expected 3 queued requests to be logged, got %d", queued)
+		}
+	}
+	if summary == nil {
+		t.Fatalf("expected a summary to be logged")
+	}
+	// JSON numbers are decoded as float64.
+	if summary["batches"] != float64(1) || summary["requests"] != float64(3) || summary["max_queue_depth"] != float64(3) {
+		t.Errorf("expected a summary of 1 batch of 3 requests, got %v", summary)
+	}
+}
+
 func testBasicCountBatches(t *testing.T, testName string, numBatches int) {
 	testBatcher := NewRequestBatcher(
 		"testBatcher",
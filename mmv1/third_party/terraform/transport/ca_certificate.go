@@ -0,0 +1,27 @@
+package transport
+
+import (
+	"crypto/x509"
+	"fmt"
+
+	"github.com/hashicorp/terraform-provider-google/google/verify"
+)
+
+// CustomCACertPool returns the system certificate pool extended with the
+// certificates of the custom_ca_certificate provider configuration, which is
+// either PEM encoded certificates or the path of a file containing them.
+func CustomCACertPool(caCertificate string) (*x509.CertPool, error) {
+	contents, _, err := verify.PathOrContents(caCertificate)
+	if err != nil {
+		return nil, fmt.Errorf("error loading custom CA certificate: %s", err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM([]byte(contents)) {
+		return nil, fmt.Errorf("custom CA certificate doesn't contain any PEM encoded certificate")
+	}
+	return pool, nil
+}
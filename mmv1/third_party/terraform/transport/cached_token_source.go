@@ -0,0 +1,69 @@
+package transport
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// DefaultTokenRefreshAhead is how long before they expire cached tokens are
+// refreshed.
+const DefaultTokenRefreshAhead = 5 * time.Minute
+
+// CachedTokenSource caches the tokens of a token source and refreshes them
+// ahead of their expiry. Refreshes are serialized, so that many requests
+// sent in parallel when tokens expire result in a single call to the token
+// endpoint.
+type CachedTokenSource struct {
+	source       oauth2.TokenSource
+	refreshAhead time.Duration
+
+	mu           sync.Mutex
+	token        *oauth2.Token
+	refreshAfter time.Time
+}
+
+// NewCachedTokenSource returns a token source caching the tokens of the given
+// one, and refreshing them refreshAhead before they expire. Tokens without an
+// expiry aren't cached.
+func NewCachedTokenSource(source oauth2.TokenSource, refreshAhead time.Duration) *CachedTokenSource {
+	return &CachedTokenSource{
+		source:       source,
+		refreshAhead: refreshAhead,
+	}
+}
+
+func (s *CachedTokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != nil && time.Now().Before(s.refreshAfter) {
+		return s.token, nil
+	}
+
+	token, err := s.source.Token()
+	if err != nil {
+		// The cached token is still usable until it expires.
+		if s.token != nil && time.Now().Before(s.token.Expiry) {
+			log.Printf("[WARN] Error refreshing access token ahead of its expiry, using the current one: %s", err)
+			return s.token, nil
+		}
+		return nil, err
+	}
+
+	if token.Expiry.IsZero() {
+		s.token = nil
+		return token, nil
+	}
+
+	s.token = token
+	s.refreshAfter = token.Expiry.Add(-s.refreshAhead)
+	if remaining := time.Until(token.Expiry); remaining < s.refreshAhead {
+		// The source may cache tokens itself until shortly before they
+		// expire, so try again halfway to the expiry rather than every time.
+		s.refreshAfter = time.Now().Add(remaining / 2)
+	}
+	return token, nil
+}
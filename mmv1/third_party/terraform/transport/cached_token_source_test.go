@@ -0,0 +1,108 @@
+package transport_test
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+)
+
+type countingTokenSource struct {
+	calls  int32
+	expiry time.Duration
+	err    error
+}
+
+func (s *countingTokenSource) Token() (*oauth2.Token, error) {
+	atomic.AddInt32(&s.calls, 1)
+	if s.err != nil {
+		return nil, s.err
+	}
+	token := &oauth2.Token{AccessToken: "token"}
+	if s.expiry != 0 {
+		token.Expiry = time.Now().Add(s.expiry)
+	}
+	return token, nil
+}
+
+func TestCachedTokenSource_cachesTokens(t *testing.T) {
+	source := &countingTokenSource{expiry: time.Hour}
+	ts := transport_tpg.NewCachedTokenSource(source, 5*time.Minute)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := ts.Token(); err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if source.calls != 1 {
+		t.Fatalf("expected a single call to the token source, got %d", source.calls)
+	}
+}
+
+func TestCachedTokenSource_refreshesAhead(t *testing.T) {
+	source := &countingTokenSource{expiry: time.Hour}
+	ts := transport_tpg.NewCachedTokenSource(source, 2*time.Hour)
+
+	for i := 0; i < 3; i++ {
+		if _, err := ts.Token(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	// Tokens expiring within the refresh window are refreshed, but not on
+	// every call since the source may return the same token until it expires.
+	if source.calls != 1 {
+		t.Fatalf("expected a single call to the token source, got %d", source.calls)
+	}
+
+	source = &countingTokenSource{expiry: 10 * time.Millisecond}
+	ts = transport_tpg.NewCachedTokenSource(source, 5*time.Minute)
+	ts.Token()
+	time.Sleep(10 * time.Millisecond)
+	ts.Token()
+	if source.calls != 2 {
+		t.Fatalf("expected the token to be refreshed, got %d calls to the token source", source.calls)
+	}
+}
+
+func TestCachedTokenSource_refreshErrors(t *testing.T) {
+	source := &countingTokenSource{expiry: 200 * time.Millisecond}
+	ts := transport_tpg.NewCachedTokenSource(source, 5*time.Minute)
+	if _, err := ts.Token(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// The current token is used while it's valid.
+	source.err = errors.New("token endpoint unavailable")
+	time.Sleep(120 * time.Millisecond)
+	if _, err := ts.Token(); err != nil {
+		t.Fatalf("expected the current token, got: %s", err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if _, err := ts.Token(); err == nil {
+		t.Fatalf("expected an error once the current token expired")
+	}
+}
+
+func TestCachedTokenSource_tokensWithoutExpiry(t *testing.T) {
+	source := &countingTokenSource{}
+	ts := transport_tpg.NewCachedTokenSource(source, 5*time.Minute)
+	ts.Token()
+	ts.Token()
+	if source.calls != 2 {
+		t.Fatalf("expected tokens without expiry not to be cached, got %d calls to the token source", source.calls)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 	"time"
 
@@ -407,6 +409,64 @@ func TestHandleSDKDefaults_UserProjectOverride(t *testing.T) {
 	}
 }
 
+func TestMultiEnvSearch_fileVariants(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials")
+	if err := os.WriteFile(path, []byte("credentials-from-file\n"), 0600); err != nil {
+		t.Fatalf("cannot write file: %s", err)
+	}
+
+	cases := map[string]struct {
+		EnvVariables  map[string]string
+		ExpectedValue string
+	}{
+		"the _FILE variant is read when the variable is not set": {
+			EnvVariables: map[string]string{
+				"GOOGLE_CREDENTIALS_FILE": path,
+			},
+			ExpectedValue: "credentials-from-file",
+		},
+		"the variable takes precedence over its _FILE variant": {
+			EnvVariables: map[string]string{
+				"GOOGLE_CREDENTIALS":      "credentials-from-env",
+				"GOOGLE_CREDENTIALS_FILE": path,
+			},
+			ExpectedValue: "credentials-from-env",
+		},
+		"earlier variables take precedence over the _FILE variants of later ones": {
+			EnvVariables: map[string]string{
+				"GOOGLE_CREDENTIALS":             "credentials-from-env",
+				"GOOGLE_CLOUD_KEYFILE_JSON_FILE": path,
+			},
+			ExpectedValue: "credentials-from-env",
+		},
+		"unreadable _FILE variants are ignored": {
+			EnvVariables: map[string]string{
+				"GOOGLE_CREDENTIALS_FILE": filepath.Join(t.TempDir(), "missing"),
+			},
+			ExpectedValue: "",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			for _, k := range []string{"GOOGLE_CREDENTIALS", "GOOGLE_CREDENTIALS_FILE", "GOOGLE_CLOUD_KEYFILE_JSON", "GOOGLE_CLOUD_KEYFILE_JSON_FILE"} {
+				t.Setenv(k, "")
+			}
+			for k, v := range tc.EnvVariables {
+				t.Setenv(k, v)
+			}
+
+			ks := []string{"GOOGLE_CREDENTIALS", "GOOGLE_CLOUD_KEYFILE_JSON"}
+			if v := transport_tpg.MultiEnvSearch(ks); v != tc.ExpectedValue {
+				t.Fatalf("expected MultiEnvSearch to return %q, got %q", tc.ExpectedValue, v)
+			}
+			if v := transport_tpg.MultiEnvDefault(ks, ""); v != tc.ExpectedValue {
+				t.Fatalf("expected MultiEnvDefault to return %q, got %q", tc.ExpectedValue, v)
+			}
+		})
+	}
+}
+
 func TestConfigLoadAndValidate_accountFilePath(t *testing.T) {
 	config := &transport_tpg.Config{
 		Credentials: transport_tpg.TestFakeCredentialsPath,
@@ -614,6 +674,27 @@ func TestConfigLoadAndValidate_customScopes(t *testing.T) {
 	}
 }
 
+func TestConfigLoadAndValidate_additionalScopes(t *testing.T) {
+	config := &transport_tpg.Config{
+		Credentials:      transport_tpg.TestFakeCredentialsPath,
+		Project:          "my-gce-project",
+		Region:           "us-central1",
+		AdditionalScopes: []string{"https://www.googleapis.com/auth/drive", "https://www.googleapis.com/auth/cloud-platform"},
+	}
+
+	transport_tpg.ConfigureBasePaths(config)
+
+	err := config.LoadAndValidate(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := append(append([]string{}, transport_tpg.DefaultClientScopes...), "https://www.googleapis.com/auth/drive")
+	if !reflect.DeepEqual(config.Scopes, expected) {
+		t.Fatalf("expected scopes %v, got %v", expected, config.Scopes)
+	}
+}
+
 func TestConfigLoadAndValidate_defaultBatchingConfig(t *testing.T) {
 	// Use default batching config
 	batchCfg, err := transport_tpg.ExpandProviderBatchingConfig(nil)
@@ -731,3 +812,55 @@ func TestGetRegionFromRegionSelfLink(t *testing.T) {
 		})
 	}
 }
+
+func TestProviderMetaUserAgentProduct(t *testing.T) {
+	cases := map[string]struct {
+		Meta           transport_tpg.ProviderMeta
+		ExpectedOutput string
+	}{
+		"No module": {
+			Meta:           transport_tpg.ProviderMeta{},
+			ExpectedOutput: "",
+		},
+		"A module name without a version": {
+			Meta:           transport_tpg.ProviderMeta{ModuleName: "blueprints/terraform/terraform-google-vm"},
+			ExpectedOutput: "blueprints/terraform/terraform-google-vm",
+		},
+		"A module name with a version": {
+			Meta:           transport_tpg.ProviderMeta{ModuleName: "blueprints/terraform/terraform-google-vm", ModuleVersion: "v11.0.0"},
+			ExpectedOutput: "blueprints/terraform/terraform-google-vm/v11.0.0",
+		},
+		"A version without a module name is ignored": {
+			Meta:           transport_tpg.ProviderMeta{ModuleVersion: "v11.0.0"},
+			ExpectedOutput: "",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			if product := tc.Meta.UserAgentProduct(); product != tc.ExpectedOutput {
+				t.Fatalf("want %q, got %q", tc.ExpectedOutput, product)
+			}
+		})
+	}
+}
+
+func TestNewTestConfig(t *testing.T) {
+	url := "http://127.0.0.1:8080/"
+	config := transport_tpg.NewTestConfig("http://127.0.0.1:8080", nil)
+
+	for service, basePath := range config.ServiceBasePaths() {
+		if basePath != url {
+			t.Errorf("want base path of %s %q, got %q", service, url, basePath)
+		}
+	}
+	if config.ContainerBasePath != url {
+		t.Errorf("want ContainerBasePath %q, got %q", url, config.ContainerBasePath)
+	}
+	dcl := reflect.ValueOf(config.DCLConfig)
+	for i := 0; i < dcl.NumField(); i++ {
+		if basePath := dcl.Field(i).String(); basePath != url {
+			t.Errorf("want %s %q, got %q", dcl.Type().Field(i).Name, url, basePath)
+		}
+	}
+}
@@ -1,3 +1,42 @@
 package transport
 
+import (
+	"net/http"
+	"reflect"
+	"strings"
+)
+
 const TestFakeCredentialsPath = "../test-fixtures/fake_account.json"
+
+// NewTestConfig returns a config sending the requests of every product to the
+// given URL with the given client, e.g. those of an httptest.Server.
+func NewTestConfig(url string, client *http.Client) *Config {
+	c := &Config{}
+	c.Client = client
+	ConfigureTestBasePaths(c, url)
+	return c
+}
+
+// ConfigureTestBasePaths sets every *BasePath field of the given config to the
+// given URL, including those of the DCL products, so that newly generated
+// products are covered without listing them.
+func ConfigureTestBasePaths(c *Config, url string) {
+	if !strings.HasSuffix(url, "/") {
+		url = url + "/"
+	}
+	setBasePaths(reflect.ValueOf(c).Elem(), url)
+}
+
+func setBasePaths(val reflect.Value, url string) {
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			setBasePaths(val.Field(i), url)
+			continue
+		}
+		if strings.HasSuffix(field.Name, "BasePath") && field.Type.Kind() == reflect.String {
+			val.Field(i).SetString(url)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// credentialsCommandTimeout is how long credentials commands may run.
+const credentialsCommandTimeout = time.Minute
+
+// CredentialsCommand is the credentials_command provider configuration, an
+// external program printing access tokens, e.g. the client of a secret broker.
+type CredentialsCommand struct {
+	Command string
+	Args    []string
+	// Env holds environment variables set for the command in addition to the
+	// ones of the provider.
+	Env map[string]string
+}
+
+func ExpandCredentialsCommand(v interface{}) *CredentialsCommand {
+	if v == nil {
+		return nil
+	}
+	ls := v.([]interface{})
+	if len(ls) == 0 || ls[0] == nil {
+		return nil
+	}
+
+	cfgV := ls[0].(map[string]interface{})
+	cc := &CredentialsCommand{
+		Command: cfgV["command"].(string),
+		Env:     make(map[string]string),
+	}
+	if args, ok := cfgV["args"]; ok {
+		for _, arg := range args.([]interface{}) {
+			cc.Args = append(cc.Args, arg.(string))
+		}
+	}
+	if env, ok := cfgV["env"]; ok {
+		for name, value := range env.(map[string]interface{}) {
+			cc.Env[name] = value.(string)
+		}
+	}
+	return cc
+}
+
+// commandOutput is the output of credentials commands printing JSON, either
+// an OAuth 2.0 token response, or an ExecCredential of kubectl credential
+// plugins.
+type commandOutput struct {
+	AccessToken string    `json:"access_token"`
+	ExpiresIn   int64     `json:"expires_in"`
+	Expiry      time.Time `json:"expiry"`
+	Status      *struct {
+		Token               string    `json:"token"`
+		ExpirationTimestamp time.Time `json:"expirationTimestamp"`
+	} `json:"status"`
+}
+
+// TokenSource returns a token source running the command for access tokens.
+// The command prints either an access token, or JSON with an access_token and
+// its expires_in or expiry, or a kubectl ExecCredential with a status.token
+// and its status.expirationTimestamp. Tokens without an expiry are reused
+// until the provider exits, others are meant to be cached by a
+// CachedTokenSource.
+func (c *CredentialsCommand) TokenSource() oauth2.TokenSource {
+	return &commandTokenSource{command: c}
+}
+
+type commandTokenSource struct {
+	command *CredentialsCommand
+
+	mu    sync.Mutex
+	token *oauth2.Token
+}
+
+func (s *commandTokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != nil {
+		return s.token, nil
+	}
+
+	out, err := s.command.run()
+	if err != nil {
+		return nil, err
+	}
+	token, err := parseCommandOutput(out)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing the output of credentials_command %q: %s", s.command.Command, err)
+	}
+	if token.Expiry.IsZero() {
+		s.token = token
+	}
+	return token, nil
+}
+
+func (c *CredentialsCommand) run() ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), credentialsCommandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, c.Command, c.Args...)
+	cmd.Env = os.Environ()
+	names := make([]string, 0, len(c.Env))
+	for name := range c.Env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		cmd.Env = append(cmd.Env, name+"="+c.Env[name])
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("error running credentials_command %q: %s: %s", c.Command, err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+func parseCommandOutput(out []byte) (*oauth2.Token, error) {
+	out = bytes.TrimSpace(out)
+	if len(out) == 0 {
+		return nil, fmt.Errorf("the command printed no access token")
+	}
+	if out[0] != '{' {
+		return &oauth2.Token{AccessToken: string(out)}, nil
+	}
+
+	var o commandOutput
+	if err := json.Unmarshal(out, &o); err != nil {
+		return nil, err
+	}
+	token := &oauth2.Token{AccessToken: o.AccessToken, Expiry: o.Expiry}
+	if o.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(o.ExpiresIn) * time.Second)
+	}
+	if o.Status != nil {
+		token.AccessToken = o.Status.Token
+		token.Expiry = o.Status.ExpirationTimestamp
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("no access_token or status.token in the JSON output")
+	}
+	return token, nil
+}
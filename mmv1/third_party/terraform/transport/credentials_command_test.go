@@ -0,0 +1,86 @@
+package transport_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+)
+
+func TestExpandCredentialsCommand(t *testing.T) {
+	if cc := transport_tpg.ExpandCredentialsCommand([]interface{}{}); cc != nil {
+		t.Fatalf("expected no command for an empty block, got %#v", cc)
+	}
+
+	cc := transport_tpg.ExpandCredentialsCommand([]interface{}{
+		map[string]interface{}{
+			"command": "broker",
+			"args":    []interface{}{"token", "--json"},
+			"env":     map[string]interface{}{"BROKER_ROLE": "terraform"},
+		},
+	})
+	if cc.Command != "broker" || len(cc.Args) != 2 || cc.Env["BROKER_ROLE"] != "terraform" {
+		t.Fatalf("unexpected command %#v", cc)
+	}
+}
+
+func TestCredentialsCommandTokenSource(t *testing.T) {
+	expiry := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	cases := map[string]struct {
+		script         string
+		env            map[string]string
+		expectedToken  string
+		expectedExpiry bool
+		expectedError  string
+	}{
+		"raw token": {
+			script:        `echo "$TOKEN"`,
+			env:           map[string]string{"TOKEN": "ya29.raw"},
+			expectedToken: "ya29.raw",
+		},
+		"token response": {
+			script:         `echo '{"access_token": "ya29.response", "expires_in": 3600}'`,
+			expectedToken:  "ya29.response",
+			expectedExpiry: true,
+		},
+		"exec credential": {
+			script:         `echo '{"kind": "ExecCredential", "status": {"token": "ya29.exec", "expirationTimestamp": "` + expiry.Format(time.RFC3339) + `"}}'`,
+			expectedToken:  "ya29.exec",
+			expectedExpiry: true,
+		},
+		"no token": {
+			script:        `echo '{"expires_in": 3600}'`,
+			expectedError: "no access_token",
+		},
+		"failure": {
+			script:        `echo "not logged in" >&2; exit 1`,
+			expectedError: "not logged in",
+		},
+	}
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			cc := &transport_tpg.CredentialsCommand{
+				Command: "sh",
+				Args:    []string{"-c", tc.script},
+				Env:     tc.env,
+			}
+			token, err := cc.TokenSource().Token()
+			if tc.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+					t.Fatalf("expected an error containing %q, got %v", tc.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if token.AccessToken != tc.expectedToken {
+				t.Errorf("expected token %q, got %q", tc.expectedToken, token.AccessToken)
+			}
+			if token.Expiry.IsZero() == tc.expectedExpiry {
+				t.Errorf("unexpected expiry %s", token.Expiry)
+			}
+		})
+	}
+}
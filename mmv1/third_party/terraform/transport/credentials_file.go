@@ -0,0 +1,88 @@
+package transport
+
+import (
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+	"golang.org/x/oauth2"
+)
+
+// credentialsFileCheckInterval is how often credentials files are checked for
+// changes.
+var credentialsFileCheckInterval = 10 * time.Second
+
+// ReloadingTokenSource returns a token source using the given one until the
+// credentials file at path changes, e.g. when its key is rotated while
+// Terraform runs, and then the one returned by newTokenSource for the new
+// contents of the file. Changes are detected by the modification time and
+// the size of the file, checked at most every 10 seconds. If the new contents
+// can't be used, e.g. while the file is being written, the previous token
+// source is kept until the next change.
+func ReloadingTokenSource(path string, tokenSource oauth2.TokenSource, newTokenSource func(contents string) (oauth2.TokenSource, error)) oauth2.TokenSource {
+	if expanded, err := homedir.Expand(path); err == nil {
+		path = expanded
+	}
+	s := &reloadingTokenSource{
+		path:           path,
+		tokenSource:    tokenSource,
+		newTokenSource: newTokenSource,
+		checkedAt:      time.Now(),
+	}
+	if info, err := os.Stat(path); err == nil {
+		s.modTime, s.size = info.ModTime(), info.Size()
+	}
+	return s
+}
+
+type reloadingTokenSource struct {
+	path           string
+	newTokenSource func(contents string) (oauth2.TokenSource, error)
+
+	mu          sync.Mutex
+	tokenSource oauth2.TokenSource
+	checkedAt   time.Time
+	modTime     time.Time
+	size        int64
+}
+
+func (s *reloadingTokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	if time.Since(s.checkedAt) >= credentialsFileCheckInterval {
+		s.checkedAt = time.Now()
+		s.reloadIfChanged()
+	}
+	tokenSource := s.tokenSource
+	s.mu.Unlock()
+
+	return tokenSource.Token()
+}
+
+// reloadIfChanged replaces the token source if the file changed since it was
+// last read. It must be called with the token source locked.
+func (s *reloadingTokenSource) reloadIfChanged() {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		log.Printf("[WARN] Unable to check credentials file %s for changes: %s", s.path, err)
+		return
+	}
+	if info.ModTime().Equal(s.modTime) && info.Size() == s.size {
+		return
+	}
+	s.modTime, s.size = info.ModTime(), info.Size()
+
+	log.Printf("[INFO] Credentials file %s changed, reloading credentials", s.path)
+	contents, err := os.ReadFile(s.path)
+	if err != nil {
+		log.Printf("[WARN] Unable to read credentials file %s, keeping the previous credentials: %s", s.path, err)
+		return
+	}
+	tokenSource, err := s.newTokenSource(string(contents))
+	if err != nil {
+		log.Printf("[WARN] Unable to load credentials file %s, keeping the previous credentials: %s", s.path, err)
+		return
+	}
+	s.tokenSource = tokenSource
+}
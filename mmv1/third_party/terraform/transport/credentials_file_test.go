@@ -0,0 +1,55 @@
+package transport
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+func TestReloadingTokenSource(t *testing.T) {
+	defer func(interval time.Duration) {
+		credentialsFileCheckInterval = interval
+	}(credentialsFileCheckInterval)
+	credentialsFileCheckInterval = 0
+
+	path := filepath.Join(t.TempDir(), "credentials.json")
+	writeFile := func(contents string) {
+		if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+			t.Fatalf("cannot write file: %s", err)
+		}
+	}
+	newTokenSource := func(contents string) (oauth2.TokenSource, error) {
+		if contents == "invalid" {
+			return nil, fmt.Errorf("invalid credentials")
+		}
+		return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: contents}), nil
+	}
+	var ts oauth2.TokenSource
+	checkToken := func(expected string) {
+		t.Helper()
+		token, err := ts.Token()
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if token.AccessToken != expected {
+			t.Errorf("expected token %q, got %q", expected, token.AccessToken)
+		}
+	}
+
+	writeFile("old")
+	initial, _ := newTokenSource("old")
+	ts = ReloadingTokenSource(path, initial, newTokenSource)
+	checkToken("old")
+
+	// Sizes differ so that changes are detected despite coarse modification
+	// times.
+	writeFile("rotated")
+	checkToken("rotated")
+
+	writeFile("invalid")
+	checkToken("rotated")
+}
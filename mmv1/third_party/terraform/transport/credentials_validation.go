@@ -0,0 +1,124 @@
+package transport
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-provider-google/google/verify"
+)
+
+// Modes of the credentials_validation provider attribute. Basic validation
+// only checks that credentials parse, strict validation also checks them
+// against the other authentication options, see ValidateCredentialsStrictly.
+const (
+	CredentialsValidationBasic  = "basic"
+	CredentialsValidationStrict = "strict"
+)
+
+// credentialsFile holds the fields of credential files checked by strict
+// credentials validation.
+type credentialsFile struct {
+	Type                           string `json:"type"`
+	ClientEmail                    string `json:"client_email"`
+	PrivateKey                     string `json:"private_key"`
+	RefreshToken                   string `json:"refresh_token"`
+	Audience                       string `json:"audience"`
+	ServiceAccountImpersonationUrl string `json:"service_account_impersonation_url"`
+	CredentialSource               struct {
+		File string `json:"file"`
+	} `json:"credential_source"`
+}
+
+// ValidateCredentialsStrictly checks, without sending any request, that the
+// credentials of the provider suit its other authentication options, e.g.
+// that credentials impersonating a service account already aren't used with
+// impersonate_service_account. credentials is the path or contents of a
+// credential file, and application default credentials are checked if neither
+// it nor accessToken is set. Tokens that expired already are returned as
+// warnings, as they may be refreshed before they're used.
+func ValidateCredentialsStrictly(credentials, accessToken, impersonateServiceAccount string) (warnings []string, err error) {
+	if accessToken != "" {
+		if exp, ok := jwtExpiry(accessToken); ok && exp.Before(time.Now()) {
+			warnings = append(warnings, fmt.Sprintf("access_token expired at %s", exp.Format(time.RFC3339)))
+		}
+		return warnings, nil
+	}
+
+	source := "credentials"
+	if credentials == "" {
+		credentials = os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
+		source = "GOOGLE_APPLICATION_CREDENTIALS"
+		if credentials == "" {
+			// Credentials are found by the client libraries, e.g. from the
+			// metadata server, and can't be checked beforehand.
+			return nil, nil
+		}
+	}
+
+	contents, _, err := verify.PathOrContents(credentials)
+	if err != nil {
+		return nil, fmt.Errorf("error loading %s: %s", source, err)
+	}
+	var f credentialsFile
+	if err := json.Unmarshal([]byte(contents), &f); err != nil {
+		return nil, fmt.Errorf("%s are not valid JSON: %s", source, err)
+	}
+
+	switch f.Type {
+	case "service_account":
+		if f.ClientEmail == "" || f.PrivateKey == "" {
+			return nil, fmt.Errorf("%s of type %q lack client_email or private_key", source, f.Type)
+		}
+	case "authorized_user":
+		if f.RefreshToken == "" {
+			return nil, fmt.Errorf("%s of type %q lack refresh_token", source, f.Type)
+		}
+	case "external_account":
+		if f.Audience == "" {
+			return nil, fmt.Errorf("%s of type %q lack audience", source, f.Type)
+		}
+		if f.ServiceAccountImpersonationUrl != "" && impersonateServiceAccount != "" {
+			return nil, fmt.Errorf("%s of type %q impersonate a service account already through service_account_impersonation_url, remove it or impersonate_service_account", source, f.Type)
+		}
+		if file := f.CredentialSource.File; file != "" {
+			token, err := os.ReadFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("error reading the subject token of %s: %s", source, err)
+			}
+			if exp, ok := jwtExpiry(strings.TrimSpace(string(token))); ok && exp.Before(time.Now()) {
+				warnings = append(warnings, fmt.Sprintf("subject token in %s expired at %s", file, exp.Format(time.RFC3339)))
+			}
+		}
+	case "impersonated_service_account":
+		if impersonateServiceAccount != "" {
+			return nil, fmt.Errorf("%s of type %q impersonate a service account already, remove impersonate_service_account", source, f.Type)
+		}
+	case "external_account_authorized_user", "gdch_service_account":
+	default:
+		return nil, fmt.Errorf("%s have an unsupported type %q", source, f.Type)
+	}
+	return warnings, nil
+}
+
+// jwtExpiry returns the expiry of a JWT, if token is one with an expiry.
+func jwtExpiry(token string) (time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, false
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(claims.Exp, 0), true
+}
@@ -0,0 +1,103 @@
+package transport_test
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+)
+
+func testJwt(exp time.Time) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"exp":%d}`, exp.Unix())))
+	return "eyJhbGciOiJSUzI1NiJ9." + payload + ".c2lnbmF0dXJl"
+}
+
+func TestValidateCredentialsStrictly(t *testing.T) {
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", "")
+
+	dir := t.TempDir()
+	expiredTokenFile := filepath.Join(dir, "expired-token")
+	if err := os.WriteFile(expiredTokenFile, []byte(testJwt(time.Now().Add(-time.Hour))+"\n"), 0600); err != nil {
+		t.Fatalf("cannot write token file: %s", err)
+	}
+	validTokenFile := filepath.Join(dir, "token")
+	if err := os.WriteFile(validTokenFile, []byte(testJwt(time.Now().Add(time.Hour))), 0600); err != nil {
+		t.Fatalf("cannot write token file: %s", err)
+	}
+
+	cases := map[string]struct {
+		credentials               string
+		accessToken               string
+		impersonateServiceAccount string
+		expectedWarning           string
+		expectedError             string
+	}{
+		"no credentials": {},
+		"service account": {
+			credentials:               `{"type": "service_account", "client_email": "sa@my-project.iam.gserviceaccount.com", "private_key": "key"}`,
+			impersonateServiceAccount: "other@my-project.iam.gserviceaccount.com",
+		},
+		"service account without key": {
+			credentials:   `{"type": "service_account", "client_email": "sa@my-project.iam.gserviceaccount.com"}`,
+			expectedError: "lack client_email or private_key",
+		},
+		"external account": {
+			credentials:               fmt.Sprintf(`{"type": "external_account", "audience": "aud", "credential_source": {"file": %q}}`, validTokenFile),
+			impersonateServiceAccount: "sa@my-project.iam.gserviceaccount.com",
+		},
+		"external account with impersonation": {
+			credentials:               `{"type": "external_account", "audience": "aud", "service_account_impersonation_url": "https://iamcredentials.googleapis.com/v1/projects/-/serviceAccounts/sa@my-project.iam.gserviceaccount.com:generateAccessToken"}`,
+			impersonateServiceAccount: "other@my-project.iam.gserviceaccount.com",
+			expectedError:             "impersonate a service account already",
+		},
+		"external account with an expired token": {
+			credentials:     fmt.Sprintf(`{"type": "external_account", "audience": "aud", "credential_source": {"file": %q}}`, expiredTokenFile),
+			expectedWarning: "subject token in " + expiredTokenFile + " expired",
+		},
+		"external account with a missing token": {
+			credentials:   fmt.Sprintf(`{"type": "external_account", "audience": "aud", "credential_source": {"file": %q}}`, filepath.Join(dir, "missing")),
+			expectedError: "error reading the subject token",
+		},
+		"impersonated service account with impersonation": {
+			credentials:               `{"type": "impersonated_service_account"}`,
+			impersonateServiceAccount: "sa@my-project.iam.gserviceaccount.com",
+			expectedError:             "impersonate a service account already",
+		},
+		"unsupported type": {
+			credentials:   `{"type": "api_key"}`,
+			expectedError: `unsupported type "api_key"`,
+		},
+		"expired access token": {
+			accessToken:     testJwt(time.Now().Add(-time.Hour)),
+			expectedWarning: "access_token expired",
+		},
+		"opaque access token": {
+			accessToken: "ya29.token",
+		},
+	}
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			warnings, err := transport_tpg.ValidateCredentialsStrictly(tc.credentials, tc.accessToken, tc.impersonateServiceAccount)
+			if tc.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+					t.Fatalf("expected an error containing %q, got %v", tc.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tc.expectedWarning == "" && len(warnings) != 0 {
+				t.Errorf("expected no warnings, got %v", warnings)
+			}
+			if tc.expectedWarning != "" && (len(warnings) != 1 || !strings.Contains(warnings[0], tc.expectedWarning)) {
+				t.Errorf("expected a warning containing %q, got %v", tc.expectedWarning, warnings)
+			}
+		})
+	}
+}
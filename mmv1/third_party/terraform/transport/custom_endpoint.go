@@ -0,0 +1,187 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+// customEndpointPlaceholder matches the variables of endpoints, e.g. the
+// {{region}} of regional endpoints, which aren't valid in URL hosts.
+var customEndpointPlaceholder = regexp.MustCompile(`{{[a-zA-Z_]+}}`)
+
+// customEndpointProbeTimeout is how long discovery documents are requested
+// for with probe_custom_endpoints.
+const customEndpointProbeTimeout = 10 * time.Second
+
+// ValidateCustomEndpoint validates the *_custom_endpoint provider
+// configuration: an http or https URL ending with a version path segment and a
+// slash, e.g. https://compute.googleapis.com/compute/v1/. Endpoints on Google
+// APIs hosts that seem to serve another API than the one of the key, e.g.
+// storage.googleapis.com for compute_custom_endpoint, are warned about.
+func ValidateCustomEndpoint(v interface{}, k string) (ws []string, errors []error) {
+	endpoint := v.(string)
+	u, err := url.Parse(customEndpointPlaceholder.ReplaceAllString(endpoint, "placeholder"))
+	if err != nil {
+		errors = append(errors, fmt.Errorf("%q (%q) is not a valid URL: %s", k, endpoint, err))
+		return
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		errors = append(errors, fmt.Errorf("%q (%q) must be an https or http URL", k, endpoint))
+		return
+	}
+	if u.Host == "" {
+		errors = append(errors, fmt.Errorf("%q (%q) has no host", k, endpoint))
+		return
+	}
+	if !strings.HasSuffix(u.Path, "/") {
+		errors = append(errors, fmt.Errorf("%q (%q) must end with a slash, e.g. %q", k, endpoint, endpoint+"/"))
+		return
+	}
+	if strings.Trim(u.Path, "/") == "" {
+		errors = append(errors, fmt.Errorf("%q (%q) must include the path of the API version, e.g. https://compute.googleapis.com/compute/v1/", k, endpoint))
+		return
+	}
+
+	service := strings.TrimSuffix(k, "_custom_endpoint")
+	if api := basePathApi(DefaultBasePaths[ServiceBasePathKeys[service]]); api != "" {
+		if hostApi := basePathApi(endpoint); hostApi != "" && !apiHostMatches(hostApi, api) {
+			ws = append(ws, fmt.Sprintf("%q (%q) seems to point to the %s API instead of the %s API, requests to it may fail with 404 errors", k, endpoint, hostApi, api))
+		}
+	}
+	return
+}
+
+// basePathApi returns the API of the given base path on a Google APIs host,
+// i.e. the first label of the host without the {{region}} of regional base
+// paths, e.g. "compute" for https://compute.googleapis.com/compute/v1/. An
+// empty string is returned for other hosts, and for shared hosts like
+// www.googleapis.com.
+func basePathApi(basePath string) string {
+	_, rest, ok := strings.Cut(basePath, "://")
+	if !ok {
+		return ""
+	}
+	host, _, _ := strings.Cut(rest, "/")
+	if !strings.HasSuffix(host, "."+DefaultUniverseDomain) {
+		return ""
+	}
+	label, _, _ := strings.Cut(host, ".")
+	label = strings.TrimPrefix(label, "{{region}}-")
+	if label == "www" {
+		return ""
+	}
+	return label
+}
+
+// apiHostMatches returns whether the first label of a host is the one of the
+// given API, possibly prefixed by a region or an environment, e.g.
+// us-central1-aiplatform, or suffixed by a Private Service Connect endpoint,
+// e.g. compute-myendpoint.
+func apiHostMatches(hostApi, api string) bool {
+	return hostApi == api || strings.HasSuffix(hostApi, "-"+api) || strings.HasPrefix(hostApi, api+"-")
+}
+
+// CustomEndpointValidator is ValidateCustomEndpoint for the framework
+// provider.
+func CustomEndpointValidator() validator.String {
+	return customEndpointValidator{}
+}
+
+type customEndpointValidator struct {
+}
+
+// Description describes the validation in plain text formatting.
+func (v customEndpointValidator) Description(_ context.Context) string {
+	return "value expected to be an http or https URL ending with a version path segment and a slash"
+}
+
+// MarkdownDescription describes the validation in Markdown formatting.
+func (v customEndpointValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// ValidateString performs the validation.
+func (v customEndpointValidator) ValidateString(ctx context.Context, request validator.StringRequest, response *validator.StringResponse) {
+	if request.ConfigValue.IsNull() || request.ConfigValue.IsUnknown() {
+		return
+	}
+
+	ws, errs := ValidateCustomEndpoint(request.ConfigValue.ValueString(), request.Path.String())
+	for _, w := range ws {
+		response.Diagnostics.AddAttributeWarning(request.Path, "unexpected custom endpoint", w)
+	}
+	for _, err := range errs {
+		response.Diagnostics.AddAttributeError(request.Path, "invalid custom endpoint", err.Error())
+	}
+}
+
+// ProbeCustomEndpoints requests the discovery documents of the given base paths
+// by service that differ from the given default base paths of the provider,
+// see NewDefaultBasePaths, i.e. the ones set through the *_custom_endpoint
+// provider configuration, see probe_custom_endpoints. It
+// returns warnings about the endpoints that don't serve a discovery document,
+// or serve the one of another API, as these requests are sent without
+// credentials and some endpoints may not serve discovery documents at all.
+func ProbeCustomEndpoints(ctx context.Context, basePaths, defaultBasePaths map[string]string) []string {
+	services := make([]string, 0, len(basePaths))
+	for service, basePath := range basePaths {
+		if basePath != "" && basePath != defaultBasePaths[ServiceBasePathKeys[service]] && !customEndpointPlaceholder.MatchString(basePath) {
+			services = append(services, service)
+		}
+	}
+	sort.Strings(services)
+
+	client := &http.Client{Timeout: customEndpointProbeTimeout}
+	var warnings []string
+	for _, service := range services {
+		if err := probeDiscoveryDocument(ctx, client, basePaths[service], basePathApi(DefaultBasePaths[ServiceBasePathKeys[service]])); err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s_custom_endpoint (%q): %s", service, basePaths[service], err))
+		}
+	}
+	return warnings
+}
+
+// probeDiscoveryDocument requests the discovery document of the version of
+// the given base path, served at the root of its host, and checks that it
+// describes the given API, if any.
+func probeDiscoveryDocument(ctx context.Context, client *http.Client, basePath, api string) error {
+	u, err := url.Parse(basePath)
+	if err != nil {
+		return err
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	discoveryUrl := fmt.Sprintf("%s://%s/$discovery/rest?version=%s", u.Scheme, u.Host, url.QueryEscape(segments[len(segments)-1]))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", discoveryUrl, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to request the discovery document: %s", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("the discovery document %s returned HTTP %d, check the host and the version of the endpoint", discoveryUrl, resp.StatusCode)
+	}
+
+	var doc struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return fmt.Errorf("the discovery document %s isn't valid JSON: %s", discoveryUrl, err)
+	}
+	if api != "" && doc.Name != "" && doc.Name != api {
+		return fmt.Errorf("the endpoint serves the %s API instead of the %s API", doc.Name, api)
+	}
+	return nil
+}
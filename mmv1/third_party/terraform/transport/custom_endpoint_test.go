@@ -0,0 +1,107 @@
+package transport_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+)
+
+func TestValidateCustomEndpoint(t *testing.T) {
+	cases := map[string]struct {
+		Endpoint         string
+		ExpectedError    string
+		ExpectedWarnings int
+	}{
+		"default endpoint": {
+			Endpoint: "https://compute.googleapis.com/compute/v1/",
+		},
+		"local endpoint": {
+			Endpoint: "http://localhost:8080/compute/v1/",
+		},
+		"regional endpoint": {
+			Endpoint: "https://{{region}}-compute.googleapis.com/compute/v1/",
+		},
+		"Private Service Connect endpoint": {
+			Endpoint: "https://compute-myendpoint.p.googleapis.com/compute/v1/",
+		},
+		"missing trailing slash": {
+			Endpoint:      "https://compute.googleapis.com/compute/v1",
+			ExpectedError: "must end with a slash",
+		},
+		"missing version": {
+			Endpoint:      "https://compute.googleapis.com/",
+			ExpectedError: "must include the path of the API version",
+		},
+		"unsupported scheme": {
+			Endpoint:      "ftp://compute.googleapis.com/compute/v1/",
+			ExpectedError: "must be an https or http URL",
+		},
+		"missing scheme": {
+			Endpoint:      "compute.googleapis.com/compute/v1/",
+			ExpectedError: "must be an https or http URL",
+		},
+		"endpoint of another API": {
+			Endpoint:         "https://storage.googleapis.com/storage/v1/",
+			ExpectedWarnings: 1,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			ws, errs := transport_tpg.ValidateCustomEndpoint(tc.Endpoint, "compute_custom_endpoint")
+			if tc.ExpectedError != "" {
+				if len(errs) == 0 || !strings.Contains(errs[0].Error(), tc.ExpectedError) {
+					t.Fatalf("expected an error containing %q, got %v", tc.ExpectedError, errs)
+				}
+				return
+			}
+			if len(errs) > 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			if len(ws) != tc.ExpectedWarnings {
+				t.Fatalf("expected %d warnings, got %v", tc.ExpectedWarnings, ws)
+			}
+		})
+	}
+}
+
+func TestProbeCustomEndpoints(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/$discovery/rest" {
+			http.NotFound(w, r)
+			return
+		}
+		switch r.URL.Query().Get("version") {
+		case "v1":
+			w.Write([]byte(`{"name": "storage", "version": "v1"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	warnings := transport_tpg.ProbeCustomEndpoints(context.Background(), map[string]string{
+		"compute": server.URL + "/compute/v1/",
+	}, transport_tpg.DefaultBasePaths)
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "serves the storage API instead of the compute API") {
+		t.Errorf("expected a warning about the API of the endpoint, got %v", warnings)
+	}
+
+	warnings = transport_tpg.ProbeCustomEndpoints(context.Background(), map[string]string{
+		"compute": server.URL + "/compute/beta2/",
+	}, transport_tpg.DefaultBasePaths)
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "returned HTTP 404") {
+		t.Errorf("expected a warning about the missing discovery document, got %v", warnings)
+	}
+
+	warnings = transport_tpg.ProbeCustomEndpoints(context.Background(), map[string]string{
+		"compute": transport_tpg.DefaultBasePaths[transport_tpg.ComputeBasePathKey],
+	}, transport_tpg.DefaultBasePaths)
+	if len(warnings) != 0 {
+		t.Errorf("expected default endpoints not to be probed, got %v", warnings)
+	}
+}
@@ -0,0 +1,56 @@
+package transport
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ReadCustomEndpointsFile returns the endpoints by service of the
+// custom_endpoints_file provider configuration, a JSON or YAML file mapping
+// services, named like their custom endpoints, e.g. "compute" for
+// compute_custom_endpoint, to endpoints.
+func ReadCustomEndpointsFile(path string) (map[string]string, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading custom_endpoints_file: %s", err)
+	}
+
+	// JSON is valid YAML, so both are read the same way.
+	var endpoints map[string]string
+	if err := yaml.Unmarshal(contents, &endpoints); err != nil {
+		return nil, fmt.Errorf("error parsing custom_endpoints_file %s: %s", path, err)
+	}
+	for service, endpoint := range endpoints {
+		ws, errs := ValidateCustomEndpoint(endpoint, service)
+		if len(errs) > 0 {
+			return nil, fmt.Errorf("invalid endpoint for %q in custom_endpoints_file: %s", service, errs[0])
+		}
+		for _, w := range ws {
+			log.Printf("[WARN] custom_endpoints_file: %s", w)
+		}
+	}
+	return endpoints, nil
+}
+
+// SetCustomEndpointsFileBasePaths rewrites the given base paths, see
+// NewDefaultBasePaths, to the endpoints of the custom_endpoints_file provider
+// configuration. The endpoints replace the defaults of the *_custom_endpoint
+// provider configuration, so endpoints configured directly or through
+// environment variables take precedence.
+func SetCustomEndpointsFileBasePaths(basePaths map[string]string, path string) error {
+	endpoints, err := ReadCustomEndpointsFile(path)
+	if err != nil {
+		return err
+	}
+	for service, endpoint := range endpoints {
+		key, ok := ServiceBasePathKeys[service]
+		if !ok {
+			return fmt.Errorf("unknown service %q in custom_endpoints_file", service)
+		}
+		basePaths[key] = endpoint
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package transport_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+)
+
+func TestReadCustomEndpointsFile(t *testing.T) {
+	cases := map[string]string{
+		"endpoints.json": `{"compute": "http://localhost:8080/compute/v1/"}`,
+		"endpoints.yaml": "compute: http://localhost:8080/compute/v1/\n",
+	}
+	for name, contents := range cases {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+				t.Fatalf("cannot write file: %s", err)
+			}
+
+			endpoints, err := transport_tpg.ReadCustomEndpointsFile(path)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if len(endpoints) != 1 || endpoints["compute"] != "http://localhost:8080/compute/v1/" {
+				t.Fatalf("unexpected endpoints %v", endpoints)
+			}
+		})
+	}
+
+	path := filepath.Join(t.TempDir(), "endpoints.json")
+	if err := os.WriteFile(path, []byte(`{"compute": "http://localhost:8080"}`), 0600); err != nil {
+		t.Fatalf("cannot write file: %s", err)
+	}
+	if _, err := transport_tpg.ReadCustomEndpointsFile(path); err == nil || !strings.Contains(err.Error(), `invalid endpoint for "compute"`) {
+		t.Fatalf("expected an invalid endpoint error, got %v", err)
+	}
+}
+
+func TestSetCustomEndpointsFileBasePaths(t *testing.T) {
+	basePaths := transport_tpg.NewDefaultBasePaths()
+
+	path := filepath.Join(t.TempDir(), "endpoints.yaml")
+	if err := os.WriteFile(path, []byte("compute: http://localhost:8080/compute/v1/\n"), 0600); err != nil {
+		t.Fatalf("cannot write file: %s", err)
+	}
+	if err := transport_tpg.SetCustomEndpointsFileBasePaths(basePaths, path); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if basePath := basePaths[transport_tpg.ComputeBasePathKey]; basePath != "http://localhost:8080/compute/v1/" {
+		t.Fatalf("expected the compute base path to be replaced, got %s", basePath)
+	}
+
+	if err := os.WriteFile(path, []byte("unknown: http://localhost:8080/unknown/v1/\n"), 0600); err != nil {
+		t.Fatalf("cannot write file: %s", err)
+	}
+	if err := transport_tpg.SetCustomEndpointsFileBasePaths(basePaths, path); err == nil || !strings.Contains(err.Error(), `unknown service "unknown"`) {
+		t.Fatalf("expected an unknown service error, got %v", err)
+	}
+}
@@ -443,6 +443,44 @@ func isCommonRetryableErrorCode(err error) (bool, string) {
 	return false, ""
 }
 
+// IsOneOfErrorCodes returns a predicate retrying errors with any of the given
+// HTTP error codes, see the additional_error_codes field of the retry
+// provider block.
+func IsOneOfErrorCodes(codes []int) RetryErrorPredicateFunc {
+	return func(err error) (bool, string) {
+		gerr, ok := err.(*googleapi.Error)
+		if !ok {
+			return false, ""
+		}
+
+		for _, code := range codes {
+			if gerr.Code == code {
+				return true, fmt.Sprintf("Retryable error code %d", gerr.Code)
+			}
+		}
+		return false, ""
+	}
+}
+
+// IsOneOfErrorReasons returns a predicate retrying errors whose response
+// contains any of the given strings, e.g. error reasons, see the
+// additional_error_reasons field of the retry provider block.
+func IsOneOfErrorReasons(reasons []string) RetryErrorPredicateFunc {
+	return func(err error) (bool, string) {
+		gerr, ok := err.(*googleapi.Error)
+		if !ok {
+			return false, ""
+		}
+
+		for _, reason := range reasons {
+			if strings.Contains(gerr.Body, reason) || strings.Contains(gerr.Message, reason) {
+				return true, fmt.Sprintf("Retryable error reason %q", reason)
+			}
+		}
+		return false, ""
+	}
+}
+
 // Do not retry if operation returns a 429
 func Is429QuotaError(err error) (bool, string) {
 	if gerr, ok := err.(*googleapi.Error); ok {
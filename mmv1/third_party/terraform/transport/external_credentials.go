@@ -0,0 +1,222 @@
+package transport
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultSubjectTokenType is the type of OIDC ID tokens, which most CI
+// systems issue.
+const DefaultSubjectTokenType = "urn:ietf:params:oauth:token-type:jwt"
+
+// AwsSubjectTokenType is the type of the signed AWS GetCallerIdentity
+// requests used as subject tokens on AWS.
+const AwsSubjectTokenType = "urn:ietf:params:aws:token-type:aws4_request"
+
+// Environments whose metadata servers or CI systems provide subject tokens,
+// see the environment field of the external_credentials provider block.
+const (
+	ExternalCredentialsEnvironmentAws    = "aws"
+	ExternalCredentialsEnvironmentAzure  = "azure"
+	ExternalCredentialsEnvironmentGithub = "github"
+	ExternalCredentialsEnvironmentGitlab = "gitlab"
+)
+
+// GitlabOidcTokenEnvVar is the variable GitLab CI jobs are expected to
+// receive ID tokens in with the gitlab environment, declared in the id_tokens
+// of the job.
+const GitlabOidcTokenEnvVar = "GITLAB_OIDC_TOKEN"
+
+// ExternalCredentials configures workload identity federation with a subject
+// token read from a file or a URL, or provided by the metadata server of the
+// environment, see the external_credentials provider block.
+type ExternalCredentials struct {
+	Audience         string
+	SubjectTokenType string
+	TokenFile        string
+	TokenUrl         string
+	Environment      string
+	// AzureAppIdUri is the application ID URI of the Azure AD application
+	// that managed identity tokens are requested for.
+	AzureAppIdUri string
+}
+
+// ExpandExternalCredentials expands the external_credentials provider block,
+// returning nil if it isn't set.
+func ExpandExternalCredentials(v interface{}) *ExternalCredentials {
+	if v == nil {
+		return nil
+	}
+	ls := v.([]interface{})
+	if len(ls) == 0 || ls[0] == nil {
+		return nil
+	}
+
+	cfgV := ls[0].(map[string]interface{})
+	c := &ExternalCredentials{}
+	c.Audience, _ = cfgV["audience"].(string)
+	c.SubjectTokenType, _ = cfgV["subject_token_type"].(string)
+	c.TokenFile, _ = cfgV["token_file"].(string)
+	c.TokenUrl, _ = cfgV["token_url"].(string)
+	c.Environment, _ = cfgV["environment"].(string)
+	c.AzureAppIdUri, _ = cfgV["azure_app_id_uri"].(string)
+	return c
+}
+
+// CredentialsJSON returns an external account credential configuration, as
+// written by `gcloud iam workload-identity-pools create-cred-config`, so that
+// it can be used like the contents of a credential file.
+func (c *ExternalCredentials) CredentialsJSON() (string, error) {
+	if c.Audience == "" {
+		return "", fmt.Errorf("external_credentials: audience is required")
+	}
+	// The audience may be given as the name of the workload identity pool
+	// provider.
+	audience := c.Audience
+	if strings.HasPrefix(audience, "projects/") {
+		audience = fmt.Sprintf("//iam.%s/%s", DefaultUniverseDomain, audience)
+	}
+
+	sources := 0
+	for _, v := range []string{c.TokenFile, c.TokenUrl, c.Environment} {
+		if v != "" {
+			sources++
+		}
+	}
+	switch {
+	case sources > 1:
+		return "", fmt.Errorf("external_credentials: only one of token_file, token_url and environment can be set")
+	case sources == 0:
+		return "", fmt.Errorf("external_credentials: one of token_file, token_url and environment is required")
+	}
+
+	subjectTokenType := c.SubjectTokenType
+	var source map[string]interface{}
+	switch {
+	case c.TokenFile != "":
+		source = map[string]interface{}{"file": c.TokenFile}
+	case c.TokenUrl != "":
+		source = map[string]interface{}{"url": c.TokenUrl}
+	case c.Environment == ExternalCredentialsEnvironmentAws:
+		// AWS credentials are read from the environment variables of the AWS
+		// SDKs if set, or else from the instance metadata service.
+		source = map[string]interface{}{
+			"environment_id":                 "aws1",
+			"region_url":                     "http://169.254.169.254/latest/meta-data/placement/availability-zone",
+			"url":                            "http://169.254.169.254/latest/meta-data/iam/security-credentials",
+			"regional_cred_verification_url": "https://sts.{region}.amazonaws.com?Action=GetCallerIdentity&Version=2011-06-15",
+			"imdsv2_session_token_url":       "http://169.254.169.254/latest/api/token",
+		}
+		if subjectTokenType == "" {
+			subjectTokenType = AwsSubjectTokenType
+		}
+	case c.Environment == ExternalCredentialsEnvironmentAzure:
+		if c.AzureAppIdUri == "" {
+			return "", fmt.Errorf("external_credentials: azure_app_id_uri is required with the azure environment")
+		}
+		source = map[string]interface{}{
+			"url": "http://169.254.169.254/metadata/identity/oauth2/token?api-version=2018-02-01&resource=" + url.QueryEscape(c.AzureAppIdUri),
+			"headers": map[string]interface{}{
+				"Metadata": "True",
+			},
+			"format": map[string]interface{}{
+				"type":                     "json",
+				"subject_token_field_name": "access_token",
+			},
+		}
+	case c.Environment == ExternalCredentialsEnvironmentGithub:
+		// GitHub Actions issues ID tokens for the requested audience to jobs
+		// with the id-token: write permission. Workload identity pool providers
+		// accept their own URL as audience by default.
+		requestUrl, requestToken := os.Getenv("ACTIONS_ID_TOKEN_REQUEST_URL"), os.Getenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN")
+		if requestUrl == "" || requestToken == "" {
+			return "", fmt.Errorf("external_credentials: ACTIONS_ID_TOKEN_REQUEST_URL and ACTIONS_ID_TOKEN_REQUEST_TOKEN are required with the github environment, grant the id-token: write permission to the workflow")
+		}
+		u, err := url.Parse(requestUrl)
+		if err != nil {
+			return "", fmt.Errorf("external_credentials: invalid ACTIONS_ID_TOKEN_REQUEST_URL: %s", err)
+		}
+		q := u.Query()
+		q.Set("audience", "https:"+audience)
+		u.RawQuery = q.Encode()
+		source = map[string]interface{}{
+			"url": u.String(),
+			"headers": map[string]interface{}{
+				"Authorization": "Bearer " + requestToken,
+			},
+			"format": map[string]interface{}{
+				"type":                     "json",
+				"subject_token_field_name": "value",
+			},
+		}
+	case c.Environment == ExternalCredentialsEnvironmentGitlab:
+		// GitLab CI passes ID tokens in variables, which external account
+		// credentials can't read, so the token is written to a file like in
+		// the GitLab documentation.
+		token := os.Getenv(GitlabOidcTokenEnvVar)
+		if token == "" {
+			return "", fmt.Errorf("external_credentials: %s is required with the gitlab environment, declare it in the id_tokens of the job", GitlabOidcTokenEnvVar)
+		}
+		tokenFile, err := writeGitlabTokenFile(token)
+		if err != nil {
+			return "", fmt.Errorf("external_credentials: error writing the GitLab ID token: %s", err)
+		}
+		source = map[string]interface{}{"file": tokenFile}
+	default:
+		return "", fmt.Errorf("external_credentials: unsupported environment %q", c.Environment)
+	}
+
+	if subjectTokenType == "" {
+		subjectTokenType = DefaultSubjectTokenType
+	}
+
+	contents, err := json.Marshal(map[string]interface{}{
+		"type":               "external_account",
+		"audience":           audience,
+		"subject_token_type": subjectTokenType,
+		"token_url":          fmt.Sprintf("https://sts.%s/v1/token", DefaultUniverseDomain),
+		"credential_source":  source,
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(contents), nil
+}
+
+// GitlabTokenFile returns the file the given GitLab ID token is written to.
+// The file is named after the token rather than randomly, so that every
+// configuration of the provider with the same token, such as those of the SDK
+// and the framework provider, overwrites the same file and gets the same
+// credentials, see SharedCredentialsKey.
+func GitlabTokenFile(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return filepath.Join(os.TempDir(), "terraform-provider-google-gitlab-token-"+hex.EncodeToString(sum[:8]))
+}
+
+// writeGitlabTokenFile writes the given token to its file, replacing it
+// atomically so that it's never read partially written.
+func writeGitlabTokenFile(token string) (string, error) {
+	path := GitlabTokenFile(token)
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(token); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
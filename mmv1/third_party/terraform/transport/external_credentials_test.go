@@ -0,0 +1,213 @@
+package transport_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+)
+
+func TestExternalCredentialsCredentialsJSON(t *testing.T) {
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_URL", "https://token.actions.githubusercontent.com/request?api-version=2.0")
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN", "request-token")
+
+	cases := map[string]struct {
+		Config           transport_tpg.ExternalCredentials
+		ExpectedAudience string
+		ExpectedSource   map[string]interface{}
+		ExpectedType     string
+		ExpectedErrors   string
+	}{
+		"subject token read from a file with the default token type": {
+			Config: transport_tpg.ExternalCredentials{
+				Audience:  "//iam.googleapis.com/projects/123/locations/global/workloadIdentityPools/ci/providers/ci",
+				TokenFile: "/tmp/token",
+			},
+			ExpectedSource: map[string]interface{}{"file": "/tmp/token"},
+			ExpectedType:   transport_tpg.DefaultSubjectTokenType,
+		},
+		"subject token fetched from a URL": {
+			Config: transport_tpg.ExternalCredentials{
+				Audience:         "//iam.googleapis.com/projects/123/locations/global/workloadIdentityPools/ci/providers/ci",
+				SubjectTokenType: "urn:ietf:params:oauth:token-type:id_token",
+				TokenUrl:         "http://localhost/token",
+			},
+			ExpectedSource: map[string]interface{}{"url": "http://localhost/token"},
+			ExpectedType:   "urn:ietf:params:oauth:token-type:id_token",
+		},
+		"AWS environment": {
+			Config: transport_tpg.ExternalCredentials{
+				Audience:    "//iam.googleapis.com/projects/123/locations/global/workloadIdentityPools/aws/providers/aws",
+				Environment: transport_tpg.ExternalCredentialsEnvironmentAws,
+			},
+			ExpectedSource: map[string]interface{}{
+				"environment_id":                 "aws1",
+				"region_url":                     "http://169.254.169.254/latest/meta-data/placement/availability-zone",
+				"url":                            "http://169.254.169.254/latest/meta-data/iam/security-credentials",
+				"regional_cred_verification_url": "https://sts.{region}.amazonaws.com?Action=GetCallerIdentity&Version=2011-06-15",
+				"imdsv2_session_token_url":       "http://169.254.169.254/latest/api/token",
+			},
+			ExpectedType: transport_tpg.AwsSubjectTokenType,
+		},
+		"Azure environment": {
+			Config: transport_tpg.ExternalCredentials{
+				Audience:      "//iam.googleapis.com/projects/123/locations/global/workloadIdentityPools/azure/providers/azure",
+				Environment:   transport_tpg.ExternalCredentialsEnvironmentAzure,
+				AzureAppIdUri: "api://my-app",
+			},
+			ExpectedSource: map[string]interface{}{
+				"url":     "http://169.254.169.254/metadata/identity/oauth2/token?api-version=2018-02-01&resource=api%3A%2F%2Fmy-app",
+				"headers": map[string]interface{}{"Metadata": "True"},
+				"format":  map[string]interface{}{"type": "json", "subject_token_field_name": "access_token"},
+			},
+			ExpectedType: transport_tpg.DefaultSubjectTokenType,
+		},
+		"GitHub environment with a pool provider name": {
+			Config: transport_tpg.ExternalCredentials{
+				Audience:    "projects/123/locations/global/workloadIdentityPools/github/providers/github",
+				Environment: transport_tpg.ExternalCredentialsEnvironmentGithub,
+			},
+			ExpectedAudience: "//iam.googleapis.com/projects/123/locations/global/workloadIdentityPools/github/providers/github",
+			ExpectedSource: map[string]interface{}{
+				"url":     "https://token.actions.githubusercontent.com/request?api-version=2.0&audience=https%3A%2F%2Fiam.googleapis.com%2Fprojects%2F123%2Flocations%2Fglobal%2FworkloadIdentityPools%2Fgithub%2Fproviders%2Fgithub",
+				"headers": map[string]interface{}{"Authorization": "Bearer request-token"},
+				"format":  map[string]interface{}{"type": "json", "subject_token_field_name": "value"},
+			},
+			ExpectedType: transport_tpg.DefaultSubjectTokenType,
+		},
+		"Azure environment without an application ID URI": {
+			Config: transport_tpg.ExternalCredentials{
+				Audience:    "audience",
+				Environment: transport_tpg.ExternalCredentialsEnvironmentAzure,
+			},
+			ExpectedErrors: "azure_app_id_uri is required",
+		},
+		"unsupported environment": {
+			Config: transport_tpg.ExternalCredentials{
+				Audience:    "audience",
+				Environment: "oracle",
+			},
+			ExpectedErrors: `unsupported environment "oracle"`,
+		},
+		"missing audience": {
+			Config: transport_tpg.ExternalCredentials{
+				TokenFile: "/tmp/token",
+			},
+			ExpectedErrors: "audience is required",
+		},
+		"missing token source": {
+			Config: transport_tpg.ExternalCredentials{
+				Audience: "audience",
+			},
+			ExpectedErrors: "one of token_file, token_url and environment is required",
+		},
+		"both token sources": {
+			Config: transport_tpg.ExternalCredentials{
+				Audience:  "audience",
+				TokenFile: "/tmp/token",
+				TokenUrl:  "http://localhost/token",
+			},
+			ExpectedErrors: "only one of token_file, token_url and environment can be set",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			v, err := tc.Config.CredentialsJSON()
+			if tc.ExpectedErrors != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.ExpectedErrors) {
+					t.Fatalf("expected error containing %q, got %v", tc.ExpectedErrors, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			var content map[string]interface{}
+			if err := json.Unmarshal([]byte(v), &content); err != nil {
+				t.Fatalf("credentials aren't valid JSON: %s", err)
+			}
+			if content["type"] != "external_account" {
+				t.Fatalf("expected credentials of type external_account, got %v", content["type"])
+			}
+			expectedAudience := tc.ExpectedAudience
+			if expectedAudience == "" {
+				expectedAudience = tc.Config.Audience
+			}
+			if content["audience"] != expectedAudience {
+				t.Fatalf("expected audience %q, got %v", expectedAudience, content["audience"])
+			}
+			if content["subject_token_type"] != tc.ExpectedType {
+				t.Fatalf("expected subject token type %q, got %v", tc.ExpectedType, content["subject_token_type"])
+			}
+			if !reflect.DeepEqual(content["credential_source"], tc.ExpectedSource) {
+				t.Fatalf("expected credential source %v, got %v", tc.ExpectedSource, content["credential_source"])
+			}
+		})
+	}
+}
+
+func TestExternalCredentialsCredentialsJSON_ciEnvironments(t *testing.T) {
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_URL", "")
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN", "")
+	t.Setenv(transport_tpg.GitlabOidcTokenEnvVar, "")
+
+	for _, environment := range []string{transport_tpg.ExternalCredentialsEnvironmentGithub, transport_tpg.ExternalCredentialsEnvironmentGitlab} {
+		ec := transport_tpg.ExternalCredentials{Audience: "audience", Environment: environment}
+		if _, err := ec.CredentialsJSON(); err == nil || !strings.Contains(err.Error(), "required with the "+environment+" environment") {
+			t.Errorf("expected a missing token error for the %s environment, got %v", environment, err)
+		}
+	}
+
+	t.Setenv(transport_tpg.GitlabOidcTokenEnvVar, "gitlab-token")
+	ec := transport_tpg.ExternalCredentials{Audience: "audience", Environment: transport_tpg.ExternalCredentialsEnvironmentGitlab}
+	v, err := ec.CredentialsJSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tokenFile := transport_tpg.GitlabTokenFile("gitlab-token")
+	defer os.Remove(tokenFile)
+	var content struct {
+		CredentialSource struct {
+			File string `json:"file"`
+		} `json:"credential_source"`
+	}
+	if err := json.Unmarshal([]byte(v), &content); err != nil {
+		t.Fatalf("credentials aren't valid JSON: %s", err)
+	}
+	if content.CredentialSource.File != tokenFile {
+		t.Fatalf("expected the token to be read from %s, got %s", tokenFile, content.CredentialSource.File)
+	}
+	token, err := os.ReadFile(tokenFile)
+	if err != nil {
+		t.Fatalf("error reading the token file: %s", err)
+	}
+	if string(token) != "gitlab-token" {
+		t.Errorf("expected the token file to contain the GitLab ID token, got %q", token)
+	}
+
+	// Configuring the provider again overwrites the token file, and gives the
+	// same credentials so that the token source is shared.
+	again, err := ec.CredentialsJSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if again != v {
+		t.Errorf("expected the same credentials when configured again, got %s and %s", v, again)
+	}
+	if transport_tpg.NewSharedCredentialsKey(v, "", nil, "", nil, 0, nil, "") != transport_tpg.NewSharedCredentialsKey(again, "", nil, "", nil, 0, nil, "") {
+		t.Errorf("expected the same shared credentials key when configured again")
+	}
+	leftovers, err := filepath.Glob(tokenFile + ".tmp-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(leftovers) != 0 {
+		t.Errorf("expected no temporary token files left, got %v", leftovers)
+	}
+}
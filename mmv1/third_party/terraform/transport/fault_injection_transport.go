@@ -0,0 +1,154 @@
+package transport
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// FaultInjectionEnvVar is the environment variable configuring the faults
+// injected into HTTP requests, for testing how configurations behave when
+// requests are throttled, fail or time out, see
+// NewTransportWithFaultInjection.
+const FaultInjectionEnvVar = "GOOGLE_FAULT_INJECTION"
+
+// faultTimeout is the fault of requests that time out rather than fail.
+const faultTimeout = "timeout"
+
+// fault is a fault injected into a share of the requests to a service.
+type fault struct {
+	// code is the HTTP error code of the injected responses, or 0 for requests
+	// that time out.
+	code int
+	rate float64
+}
+
+// faultInjectionTransport injects faults into requests in place of sending
+// them, depending on the service the requests are sent to.
+type faultInjectionTransport struct {
+	baseTransit http.RoundTripper
+	basePaths   []string
+	faults      map[string]fault
+	// defaultFault is the fault injected into requests to other services, if
+	// any.
+	defaultFault *fault
+}
+
+// NewTransportWithFaultInjection returns a transport injecting the faults
+// configured by spec, the value of the GOOGLE_FAULT_INJECTION environment
+// variable. It's a comma-separated list of service=fault[:rate] entries,
+// e.g. "compute=429:0.5,storage=timeout", where the service is a name, e.g.
+// "compute", or "*" for all other services, the fault is an HTTP error code
+// of 429 or 5xx, or "timeout" for requests hanging until they time out, and
+// the rate is the share of requests failing, 1 by default. The base paths are
+// given by service name. baseTransit is returned as is if spec is empty.
+func NewTransportWithFaultInjection(baseTransit http.RoundTripper, spec string, basePaths map[string]string) (http.RoundTripper, error) {
+	if baseTransit == nil {
+		baseTransit = http.DefaultTransport
+	}
+	if strings.TrimSpace(spec) == "" {
+		return baseTransit, nil
+	}
+
+	t := &faultInjectionTransport{
+		baseTransit: baseTransit,
+		faults:      make(map[string]fault),
+	}
+	for _, entry := range strings.Split(spec, ",") {
+		service, v, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid entry %q in %s, expected service=fault[:rate]", entry, FaultInjectionEnvVar)
+		}
+		f, err := parseFault(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fault %q for service %q in %s: %s", v, service, FaultInjectionEnvVar, err)
+		}
+
+		if service == "*" {
+			t.defaultFault = &f
+			continue
+		}
+		basePath, ok := basePaths[service]
+		if !ok {
+			return nil, fmt.Errorf("unknown service %q in %s", service, FaultInjectionEnvVar)
+		}
+		if basePath != "" {
+			t.faults[basePath] = f
+			t.basePaths = append(t.basePaths, basePath)
+		}
+	}
+	sortBasePaths(t.basePaths)
+
+	log.Printf("[WARN] Injecting faults into requests as configured by %s=%q", FaultInjectionEnvVar, spec)
+	return t, nil
+}
+
+func parseFault(v string) (fault, error) {
+	kind, rawRate, hasRate := strings.Cut(v, ":")
+	f := fault{rate: 1}
+	if kind != faultTimeout {
+		code, err := strconv.Atoi(kind)
+		if err != nil || (code != http.StatusTooManyRequests && (code < 500 || code > 599)) {
+			return f, fmt.Errorf("expected an error code of 429 or 5xx, or %q", faultTimeout)
+		}
+		f.code = code
+	}
+	if hasRate {
+		rate, err := strconv.ParseFloat(rawRate, 64)
+		if err != nil || rate <= 0 || rate > 1 {
+			return f, fmt.Errorf("expected a rate greater than 0 and at most 1, got %q", rawRate)
+		}
+		f.rate = rate
+	}
+	return f, nil
+}
+
+// faultFor returns the fault to inject into the given request, if any.
+func (t *faultInjectionTransport) faultFor(req *http.Request) (fault, bool) {
+	f := t.defaultFault
+	if basePath, ok := matchBasePath(req.URL, t.basePaths); ok {
+		serviceFault := t.faults[basePath]
+		f = &serviceFault
+	}
+	if f == nil || rand.Float64() >= f.rate {
+		return fault{}, false
+	}
+	return *f, true
+}
+
+func (t *faultInjectionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	f, ok := t.faultFor(req)
+	if !ok {
+		return t.baseTransit.RoundTrip(req)
+	}
+
+	// The request isn't sent, but its body must be closed all the same.
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	if f.code == 0 {
+		log.Printf("[DEBUG] Injecting a timeout into %s %s", req.Method, req.URL)
+		<-req.Context().Done()
+		return nil, fmt.Errorf("injected timeout: %w", req.Context().Err())
+	}
+
+	log.Printf("[DEBUG] Injecting a %d error into %s %s", f.code, req.Method, req.URL)
+	body := fmt.Sprintf(`{"error": {"code": %d, "message": "Fault injected by %s"}}`, f.code, FaultInjectionEnvVar)
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", f.code, http.StatusText(f.code)),
+		StatusCode:    f.code,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json; charset=UTF-8"}},
+		Body:          io.NopCloser(bytes.NewBufferString(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,84 @@
+package transport_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+)
+
+func TestFaultInjectionTransport(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("{}"))
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	otherServer := httptest.NewServer(handler)
+	defer otherServer.Close()
+
+	basePaths := map[string]string{
+		"compute": server.URL + "/compute/v1/",
+		"storage": server.URL + "/storage/v1/",
+	}
+	transport, err := transport_tpg.NewTransportWithFaultInjection(nil, "compute=429, storage=timeout, *=503:1", basePaths)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	cases := map[string]struct {
+		url             string
+		expectedCode    int
+		expectedTimeout bool
+	}{
+		"error code": {
+			url:          server.URL + "/compute/v1/projects/my-project",
+			expectedCode: http.StatusTooManyRequests,
+		},
+		"timeout": {
+			url:             server.URL + "/storage/v1/b/my-bucket",
+			expectedTimeout: true,
+		},
+		"other service": {
+			url:          otherServer.URL + "/v1/projects/my-project/topics",
+			expectedCode: http.StatusServiceUnavailable,
+		},
+	}
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			req, err := http.NewRequestWithContext(ctx, "GET", tc.url, nil)
+			if err != nil {
+				t.Fatalf("cannot create request: %s", err)
+			}
+
+			res, err := transport.RoundTrip(req)
+			if tc.expectedTimeout {
+				if !errors.Is(err, context.DeadlineExceeded) {
+					t.Fatalf("expected the request to time out, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			res.Body.Close()
+			if res.StatusCode != tc.expectedCode {
+				t.Errorf("expected status code %d, got %d", tc.expectedCode, res.StatusCode)
+			}
+		})
+	}
+}
+
+func TestNewTransportWithFaultInjection_invalid(t *testing.T) {
+	basePaths := map[string]string{"compute": "https://compute.googleapis.com/compute/v1/"}
+	for _, spec := range []string{"compute", "compute=404", "compute=500:0", "compute=timeout:2", "unknown=500"} {
+		if _, err := transport_tpg.NewTransportWithFaultInjection(nil, spec, basePaths); err == nil {
+			t.Errorf("expected an error for %q", spec)
+		}
+	}
+}
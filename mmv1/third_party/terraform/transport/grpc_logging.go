@@ -0,0 +1,57 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+
+	grpc_logrus "github.com/grpc-ecosystem/go-grpc-middleware/logging/logrus"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+)
+
+// GrpcLogLevels are the values of the grpc_log_level provider configuration.
+var GrpcLogLevels = []string{"panic", "fatal", "error", "warn", "info", "debug", "trace"}
+
+// GrpcLoggingOptions routes the logs of gRPC clients through logrus at the
+// given level, see the grpc_log_level provider configuration, or debug if
+// empty. These logs are only written with TF_LOG=DEBUG or TRACE, formatted as
+// JSON if jsonFormat is set. It returns the client options logging the
+// payloads of gRPC requests and responses if logPayloads is set, see the
+// grpc_log_payloads provider configuration.
+func GrpcLoggingOptions(level string, logPayloads, jsonFormat bool) ([]option.ClientOption, error) {
+	logLevel := logrus.DebugLevel
+	if level != "" {
+		var err error
+		logLevel, err = logrus.ParseLevel(level)
+		if err != nil {
+			return nil, fmt.Errorf("invalid grpc_log_level: %s", err)
+		}
+	}
+
+	logger := logrus.StandardLogger()
+
+	logrus.SetLevel(logLevel)
+	if jsonFormat {
+		logrus.SetFormatter(&logrus.JSONFormatter{})
+	} else {
+		logrus.SetFormatter(&Formatter{
+			TimestampFormat: "2006/01/02 15:04:05",
+			LogFormat:       "%time% [%lvl%] %msg% \n",
+		})
+	}
+
+	grpc_logrus.ReplaceGrpcLogger(logrus.NewEntry(logger))
+
+	if !logPayloads {
+		return nil, nil
+	}
+
+	alwaysLoggingDeciderClient := func(ctx context.Context, fullMethodName string) bool { return true }
+	return []option.ClientOption{
+		option.WithGRPCDialOption(grpc.WithUnaryInterceptor(
+			grpc_logrus.PayloadUnaryClientInterceptor(logrus.NewEntry(logger), alwaysLoggingDeciderClient))),
+		option.WithGRPCDialOption(grpc.WithStreamInterceptor(
+			grpc_logrus.PayloadStreamClientInterceptor(logrus.NewEntry(logger), alwaysLoggingDeciderClient))),
+	}, nil
+}
@@ -0,0 +1,39 @@
+package transport_test
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+)
+
+func TestGrpcLoggingOptions(t *testing.T) {
+	defer logrus.SetLevel(logrus.DebugLevel)
+
+	opts, err := transport_tpg.GrpcLoggingOptions("", false, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(opts) != 0 {
+		t.Errorf("expected payloads not to be logged by default, got %d options", len(opts))
+	}
+	if logrus.GetLevel() != logrus.DebugLevel {
+		t.Errorf("expected the debug level by default, got %s", logrus.GetLevel())
+	}
+
+	opts, err = transport_tpg.GrpcLoggingOptions("warn", true, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(opts) != 2 {
+		t.Errorf("expected unary and stream payload logging options, got %d options", len(opts))
+	}
+	if logrus.GetLevel() != logrus.WarnLevel {
+		t.Errorf("expected the warn level, got %s", logrus.GetLevel())
+	}
+
+	if _, err := transport_tpg.GrpcLoggingOptions("verbose", false, false); err == nil {
+		t.Errorf("expected an error for an invalid level")
+	}
+}
@@ -0,0 +1,48 @@
+package transport
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"sync/atomic"
+)
+
+// HeapProfileDirEnvVar is the environment variable of the directory heap
+// profiles of the provider are written to, for diagnosing its memory usage,
+// see WriteHeapProfile.
+const HeapProfileDirEnvVar = "GOOGLE_HEAP_PROFILE_DIR"
+
+// heapProfileCount numbers the heap profiles of the process, as the provider
+// may be configured several times, e.g. with provider aliases.
+var heapProfileCount int32
+
+// WriteHeapProfile writes a heap profile of the provider to the directory of
+// the GOOGLE_HEAP_PROFILE_DIR environment variable, if set, named after the
+// given stage, e.g. "configure", the process and the number of the profile.
+// Heap profiles include the memory in use and the allocations since the start
+// of the process, see go tool pprof -sample_index.
+func WriteHeapProfile(stage string) {
+	dir := os.Getenv(HeapProfileDirEnvVar)
+	if dir == "" {
+		return
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("%s-%d-%d.pprof", stage, os.Getpid(), atomic.AddInt32(&heapProfileCount, 1)))
+	f, err := os.Create(path)
+	if err != nil {
+		log.Printf("[WARN] Unable to create heap profile %s: %s", path, err)
+		return
+	}
+	defer f.Close()
+
+	// The memory in use is the one of the last garbage collection.
+	runtime.GC()
+	if err := pprof.WriteHeapProfile(f); err != nil {
+		log.Printf("[WARN] Unable to write heap profile %s: %s", path, err)
+		return
+	}
+	log.Printf("[DEBUG] Wrote heap profile %s", path)
+}
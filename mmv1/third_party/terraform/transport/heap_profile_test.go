@@ -0,0 +1,30 @@
+package transport_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+)
+
+func TestWriteHeapProfile(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(transport_tpg.HeapProfileDirEnvVar, dir)
+
+	transport_tpg.WriteHeapProfile("configure")
+	transport_tpg.WriteHeapProfile("configure")
+
+	profiles, err := filepath.Glob(filepath.Join(dir, "configure-*.pprof"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(profiles) != 2 {
+		t.Fatalf("expected 2 heap profiles, got %v", profiles)
+	}
+	for _, profile := range profiles {
+		if info, err := os.Stat(profile); err != nil || info.Size() == 0 {
+			t.Errorf("expected a heap profile in %s, got %v", profile, err)
+		}
+	}
+}
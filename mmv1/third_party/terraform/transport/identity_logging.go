@@ -0,0 +1,20 @@
+package transport
+
+import (
+	"os"
+	"strings"
+)
+
+// IdentityLoggingEnabled returns whether the identities of the credentials are
+// looked up to be logged when the provider is configured, i.e. whether
+// provider logs are enabled through TF_LOG or TF_LOG_PROVIDER. The lookups
+// fetch a token and the userinfo of the credentials before any resource is
+// read, slowing down every run while their logs are discarded otherwise.
+func IdentityLoggingEnabled() bool {
+	for _, name := range []string{"TF_LOG", "TF_LOG_PROVIDER"} {
+		if v := os.Getenv(name); v != "" && !strings.EqualFold(v, "off") {
+			return true
+		}
+	}
+	return false
+}
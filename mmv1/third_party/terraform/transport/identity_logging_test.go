@@ -0,0 +1,38 @@
+package transport_test
+
+import (
+	"testing"
+
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+)
+
+func TestIdentityLoggingEnabled(t *testing.T) {
+	cases := map[string]struct {
+		TfLog         string
+		TfLogProvider string
+		Expected      bool
+	}{
+		"logs disabled": {},
+		"logs enabled": {
+			TfLog:    "INFO",
+			Expected: true,
+		},
+		"provider logs enabled": {
+			TfLogProvider: "DEBUG",
+			Expected:      true,
+		},
+		"logs turned off": {
+			TfLog: "off",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			t.Setenv("TF_LOG", tc.TfLog)
+			t.Setenv("TF_LOG_PROVIDER", tc.TfLogProvider)
+			if enabled := transport_tpg.IdentityLoggingEnabled(); enabled != tc.Expected {
+				t.Errorf("expected %t, got %t", tc.Expected, enabled)
+			}
+		})
+	}
+}
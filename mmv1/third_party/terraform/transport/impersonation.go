@@ -0,0 +1,40 @@
+package transport
+
+import (
+	"context"
+	"time"
+
+	googleoauth "golang.org/x/oauth2/google"
+	"google.golang.org/api/impersonate"
+	"google.golang.org/api/option"
+	"google.golang.org/api/transport"
+)
+
+// ImpersonatedCredentials returns credentials impersonating the given service
+// account through the given delegates, authenticated with the credentials of
+// opts, or the application default credentials without any. Tokens last for
+// the given lifetime, see the impersonate_service_account_lifetime provider
+// configuration, or the default lifetime of an hour if 0.
+func ImpersonatedCredentials(targetPrincipal string, delegates, scopes []string, lifetime time.Duration, opts ...option.ClientOption) (googleoauth.Credentials, error) {
+	if lifetime == 0 {
+		opts = append(opts, option.ImpersonateCredentials(targetPrincipal, delegates...), option.WithScopes(scopes...))
+		creds, err := transport.Creds(context.TODO(), opts...)
+		if err != nil {
+			return googleoauth.Credentials{}, err
+		}
+		return *creds, nil
+	}
+
+	tokenSource, err := impersonate.CredentialsTokenSource(context.TODO(), impersonate.CredentialsConfig{
+		TargetPrincipal: targetPrincipal,
+		Delegates:       delegates,
+		Scopes:          scopes,
+		Lifetime:        lifetime,
+	}, opts...)
+	if err != nil {
+		return googleoauth.Credentials{}, err
+	}
+	return googleoauth.Credentials{
+		TokenSource: tokenSource,
+	}, nil
+}
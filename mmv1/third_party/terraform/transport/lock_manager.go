@@ -0,0 +1,86 @@
+package transport
+
+import (
+	"fmt"
+	"time"
+)
+
+// LockManager serializes the changes of a provider configuration on the keys
+// they conflict on, see MutexKV, waiting at most Timeout for each lock.
+//
+// Changes only reading what's locked, e.g. reading an IAM policy, take a read
+// lock with RLock so they run in parallel with each other, and are only
+// serialized with the changes taking the lock with Lock.
+//
+// The lock managers of the provider configurations share the keys of
+// MutexStore by default, so that changes are serialized across provider
+// aliases as well as with the resources locking MutexStore directly. A nil
+// LockManager locks MutexStore without a timeout.
+type LockManager struct {
+	store *MutexKV
+	// Timeout is how long to wait for a lock before failing the change. Zero
+	// waits forever.
+	Timeout time.Duration
+}
+
+// NewLockManager returns a LockManager locking the keys of the given store,
+// MutexStore if nil, with the given timeout.
+func NewLockManager(store *MutexKV, timeout time.Duration) *LockManager {
+	return &LockManager{
+		store:   store,
+		Timeout: timeout,
+	}
+}
+
+func (l *LockManager) mutexKV() *MutexKV {
+	if l == nil || l.store == nil {
+		return MutexStore
+	}
+	return l.store
+}
+
+func (l *LockManager) timeout() time.Duration {
+	if l == nil {
+		return 0
+	}
+	return l.Timeout
+}
+
+// Lock locks the given key for writing. Caller is responsible for calling
+// Unlock for the same key if it succeeds.
+func (l *LockManager) Lock(key string) error {
+	if err := l.mutexKV().LockWithTimeout(key, l.timeout()); err != nil {
+		return fmt.Errorf("%s, another change of the same resources may be in progress, see lock_timeout", err)
+	}
+	return nil
+}
+
+// Unlock unlocks the given key. Caller must have called Lock for the same key
+// first.
+func (l *LockManager) Unlock(key string) {
+	l.mutexKV().Unlock(key)
+}
+
+// RLock locks the given key for reading. Caller is responsible for calling
+// RUnlock for the same key if it succeeds.
+func (l *LockManager) RLock(key string) error {
+	if err := l.mutexKV().RLockWithTimeout(key, l.timeout()); err != nil {
+		return fmt.Errorf("%s, another change of the same resources may be in progress, see lock_timeout", err)
+	}
+	return nil
+}
+
+// RUnlock unlocks the given key read-locked with RLock.
+func (l *LockManager) RUnlock(key string) {
+	l.mutexKV().RUnlock(key)
+}
+
+// LockedCall calls f with the given key locked for writing.
+func (l *LockManager) LockedCall(key string, f func() error) error {
+	if err := l.Lock(key); err != nil {
+		return err
+	}
+	defer l.Unlock(key)
+
+	return f()
+}
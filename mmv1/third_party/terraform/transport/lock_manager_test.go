@@ -0,0 +1,61 @@
+package transport_test
+
+import (
+	"testing"
+	"time"
+
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+)
+
+func TestLockManager(t *testing.T) {
+	locks := transport_tpg.NewLockManager(transport_tpg.NewMutexKV(), 50*time.Millisecond)
+
+	if err := locks.RLock("key"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := locks.RLock("key"); err != nil {
+		t.Fatalf("expected concurrent read locks, got: %s", err)
+	}
+	if err := locks.Lock("key"); err == nil {
+		t.Fatalf("expected the lock to time out while read-locked")
+	}
+	if err := locks.Lock("other"); err != nil {
+		t.Fatalf("expected other keys to be locked independently, got: %s", err)
+	}
+	locks.Unlock("other")
+	locks.RUnlock("key")
+	locks.RUnlock("key")
+
+	if err := locks.Lock("key"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := locks.RLock("key"); err == nil {
+		t.Fatalf("expected the read lock to time out while locked")
+	}
+	unlocked := make(chan struct{})
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		close(unlocked)
+		locks.Unlock("key")
+	}()
+	if err := locks.LockedCall("key", func() error {
+		select {
+		case <-unlocked:
+		default:
+			t.Errorf("expected the lock to be held by a single caller")
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("expected the lock to be acquired once released, got: %s", err)
+	}
+}
+
+func TestLockManager_nil(t *testing.T) {
+	var locks *transport_tpg.LockManager
+
+	if err := locks.LockedCall("test-lock-manager-nil", func() error {
+		return nil
+	}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
@@ -0,0 +1,333 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/logging"
+)
+
+const redactedValue = "REDACTED"
+
+const logRequestMsg = `%s API Request Details:
+---[ REQUEST ]---------------------------------------
+%s
+-----------------------------------------------------`
+
+const logResponseMsg = `%s API Response Details:
+---[ RESPONSE ]--------------------------------------
+%s
+-----------------------------------------------------`
+
+// Formats of the logs of HTTP requests and gRPC calls, see the format field of
+// the request_logging provider block.
+const (
+	LogFormatText = "text"
+	LogFormatJson = "json"
+)
+
+// LoggingConfig controls what HTTP requests and responses logged at the
+// DEBUG level contain, see the request_logging provider block.
+type LoggingConfig struct {
+	// Format is LogFormatText, the default if empty, to log requests and
+	// responses as dumps, or LogFormatJson to log each request along with its
+	// response as a JSON object on a single line.
+	Format string
+	// RedactedHeaders are headers whose values are replaced, matched
+	// case-insensitively.
+	RedactedHeaders []string
+	// RedactedBodyFields are fields of JSON bodies whose values are replaced
+	// at any depth, matched case-insensitively.
+	RedactedBodyFields []string
+	// MaxBodySize caps the logged size of bodies in bytes, 0 for no cap.
+	MaxBodySize int
+}
+
+// JsonFormat returns whether logs are formatted as JSON.
+func (c *LoggingConfig) JsonFormat() bool {
+	return c != nil && c.Format == LogFormatJson
+}
+
+// WithLogFormatFromEnv sets the format of logs from the GOOGLE_LOG_FORMAT
+// environment variable unless the configuration sets it. A configuration is
+// returned in place of a nil one if the variable is set to json.
+func WithLogFormatFromEnv(config *LoggingConfig) *LoggingConfig {
+	if config != nil && config.Format != "" {
+		return config
+	}
+	format := MultiEnvSearch([]string{"GOOGLE_LOG_FORMAT"})
+	switch format {
+	case "":
+		return config
+	case LogFormatText, LogFormatJson:
+	default:
+		log.Printf("[WARN] Ignoring GOOGLE_LOG_FORMAT value %q, expected %q or %q", format, LogFormatText, LogFormatJson)
+		return config
+	}
+
+	if config == nil {
+		if format != LogFormatJson {
+			return nil
+		}
+		config = &LoggingConfig{}
+	}
+	config.Format = format
+	return config
+}
+
+func ExpandProviderLoggingConfig(v interface{}) *LoggingConfig {
+	if v == nil {
+		return WithLogFormatFromEnv(nil)
+	}
+	ls := v.([]interface{})
+	if len(ls) == 0 || ls[0] == nil {
+		return WithLogFormatFromEnv(nil)
+	}
+
+	cfgV := ls[0].(map[string]interface{})
+	config := &LoggingConfig{}
+	if format, ok := cfgV["format"]; ok {
+		config.Format = format.(string)
+	}
+	if headers, ok := cfgV["redacted_headers"]; ok {
+		for _, header := range headers.([]interface{}) {
+			config.RedactedHeaders = append(config.RedactedHeaders, header.(string))
+		}
+	}
+	if fields, ok := cfgV["redacted_body_fields"]; ok {
+		for _, field := range fields.([]interface{}) {
+			config.RedactedBodyFields = append(config.RedactedBodyFields, field.(string))
+		}
+	}
+	if maxBodySize, ok := cfgV["max_body_size"]; ok {
+		config.MaxBodySize = maxBodySize.(int)
+	}
+	return WithLogFormatFromEnv(config)
+}
+
+// loggingTransport logs HTTP requests and responses at the DEBUG level like
+// the logging transport of the SDK, redacting them as configured.
+type loggingTransport struct {
+	name     string
+	internal http.RoundTripper
+	config   *LoggingConfig
+}
+
+// NewLoggingTransport returns a transport logging requests and responses,
+// redacted as configured. Without a configuration, the transport of the SDK
+// is used, which logs them in full.
+func NewLoggingTransport(name string, t http.RoundTripper, config *LoggingConfig) http.RoundTripper {
+	if config == nil {
+		return logging.NewTransport(name, t)
+	}
+	return &loggingTransport{name: name, internal: t, config: config}
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.config.JsonFormat() {
+		return t.roundTripJson(req)
+	}
+
+	if logging.IsDebugOrHigher() {
+		reqData, err := t.dumpRequest(req)
+		if err == nil {
+			log.Printf("[DEBUG] "+logRequestMsg, t.name, reqData)
+		} else {
+			log.Printf("[ERROR] %s API Request error: %#v", t.name, err)
+		}
+	}
+
+	resp, err := t.internal.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	if logging.IsDebugOrHigher() {
+		respData, err := t.dumpResponse(resp)
+		if err == nil {
+			log.Printf("[DEBUG] "+logResponseMsg, t.name, respData)
+		} else {
+			log.Printf("[ERROR] %s API Response error: %#v", t.name, err)
+		}
+	}
+
+	return resp, nil
+}
+
+// jsonLogEntry is a request logged in the JSON format, along with its
+// response.
+type jsonLogEntry struct {
+	Api             string      `json:"api"`
+	Method          string      `json:"method"`
+	Url             string      `json:"url"`
+	Status          int         `json:"status,omitempty"`
+	LatencyMs       int64       `json:"latency_ms"`
+	RetryCount      int         `json:"retry_count"`
+	Error           string      `json:"error,omitempty"`
+	RequestHeaders  http.Header `json:"request_headers,omitempty"`
+	RequestBody     string      `json:"request_body,omitempty"`
+	ResponseHeaders http.Header `json:"response_headers,omitempty"`
+	ResponseBody    string      `json:"response_body,omitempty"`
+}
+
+func (t *loggingTransport) roundTripJson(req *http.Request) (*http.Response, error) {
+	if !logging.IsDebugOrHigher() {
+		return t.internal.RoundTrip(req)
+	}
+
+	entry := jsonLogEntry{
+		Api:            t.name,
+		Method:         req.Method,
+		Url:            req.URL.String(),
+		RetryCount:     retryCount(req.Context()),
+		RequestHeaders: t.config.redactHeaders(req.Header),
+	}
+	body, err := readBody(&req.Body)
+	if err != nil {
+		log.Printf("[ERROR] %s API Request error: %#v", t.name, err)
+	}
+	entry.RequestBody = t.config.redactBody(body)
+
+	start := time.Now()
+	resp, respErr := t.internal.RoundTrip(req)
+	entry.LatencyMs = time.Since(start).Milliseconds()
+	if respErr != nil {
+		entry.Error = respErr.Error()
+	} else {
+		entry.Status = resp.StatusCode
+		entry.ResponseHeaders = t.config.redactHeaders(resp.Header)
+		body, err := readBody(&resp.Body)
+		if err != nil {
+			log.Printf("[ERROR] %s API Response error: %#v", t.name, err)
+		}
+		entry.ResponseBody = t.config.redactBody(body)
+	}
+
+	line, err := json.Marshal(entry)
+	if err == nil {
+		log.Printf("[DEBUG] %s", line)
+	} else {
+		log.Printf("[ERROR] %s API Request logging error: %#v", t.name, err)
+	}
+	return resp, respErr
+}
+
+// readBody reads a request or response body, replacing it with a copy.
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	b, err := io.ReadAll(*body)
+	(*body).Close()
+	if err != nil {
+		return nil, err
+	}
+	*body = io.NopCloser(bytes.NewReader(b))
+	return b, nil
+}
+
+// retryCountKey is the context key of the number of times a request was
+// retried, set by the retry transport.
+type retryCountKey struct{}
+
+func withRetryCount(ctx context.Context, count int) context.Context {
+	return context.WithValue(ctx, retryCountKey{}, count)
+}
+
+func retryCount(ctx context.Context) int {
+	count, _ := ctx.Value(retryCountKey{}).(int)
+	return count
+}
+
+func (t *loggingTransport) dumpRequest(req *http.Request) (string, error) {
+	logged := req.Clone(req.Context())
+	logged.Header = t.config.redactHeaders(req.Header)
+	head, err := httputil.DumpRequestOut(logged, false)
+	if err != nil {
+		return "", err
+	}
+
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return "", err
+	}
+	return string(head) + t.config.redactBody(body), nil
+}
+
+func (t *loggingTransport) dumpResponse(resp *http.Response) (string, error) {
+	logged := *resp
+	logged.Header = t.config.redactHeaders(resp.Header)
+	head, err := httputil.DumpResponse(&logged, false)
+	if err != nil {
+		return "", err
+	}
+
+	body, err := readBody(&resp.Body)
+	if err != nil {
+		return "", err
+	}
+	return string(head) + t.config.redactBody(body), nil
+}
+
+func (c *LoggingConfig) redactHeaders(header http.Header) http.Header {
+	redacted := header.Clone()
+	for _, name := range c.RedactedHeaders {
+		if _, ok := redacted[http.CanonicalHeaderKey(name)]; ok {
+			redacted.Set(name, redactedValue)
+		}
+	}
+	return redacted
+}
+
+// redactBody redacts the fields of JSON bodies, pretty printing them, and
+// caps the size of bodies.
+func (c *LoggingConfig) redactBody(body []byte) string {
+	if len(c.RedactedBodyFields) > 0 {
+		var v interface{}
+		if err := json.Unmarshal(body, &v); err == nil {
+			if redacted, err := json.MarshalIndent(c.redactValue(v), "", "  "); err == nil {
+				body = redacted
+			}
+		}
+	}
+
+	if c.MaxBodySize > 0 && len(body) > c.MaxBodySize {
+		return fmt.Sprintf("%s\n[%d bytes truncated]", body[:c.MaxBodySize], len(body)-c.MaxBodySize)
+	}
+	return string(body)
+}
+
+func (c *LoggingConfig) redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if c.redactedField(key) {
+				v[key] = redactedValue
+			} else {
+				v[key] = c.redactValue(value)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = c.redactValue(value)
+		}
+	}
+	return v
+}
+
+func (c *LoggingConfig) redactedField(key string) bool {
+	for _, field := range c.RedactedBodyFields {
+		if strings.EqualFold(field, key) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,168 @@
+package transport_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+)
+
+func TestExpandProviderLoggingConfig(t *testing.T) {
+	t.Setenv("GOOGLE_LOG_FORMAT", "")
+	if config := transport_tpg.ExpandProviderLoggingConfig([]interface{}{}); config != nil {
+		t.Fatalf("expected no configuration for an empty block, got %#v", config)
+	}
+
+	config := transport_tpg.ExpandProviderLoggingConfig([]interface{}{
+		map[string]interface{}{
+			"redacted_headers":     []interface{}{"X-Goog-User-Project"},
+			"redacted_body_fields": []interface{}{"privateKeyData"},
+			"max_body_size":        1024,
+		},
+	})
+	if len(config.RedactedHeaders) != 1 || len(config.RedactedBodyFields) != 1 || config.MaxBodySize != 1024 {
+		t.Fatalf("unexpected configuration %#v", config)
+	}
+}
+
+func TestLoggingTransport_redaction(t *testing.T) {
+	t.Setenv("TF_LOG", "DEBUG")
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Session", "response-session")
+		w.Write([]byte(`{"name": "key", "privateKeyData": "response-secret", "description": "` + strings.Repeat("x", 100) + `"}`))
+	}))
+	defer server.Close()
+
+	client := &http.Client{
+		Transport: transport_tpg.NewLoggingTransport("Google", http.DefaultTransport, &transport_tpg.LoggingConfig{
+			RedactedHeaders:    []string{"x-session"},
+			RedactedBodyFields: []string{"PRIVATEKEYDATA"},
+			MaxBodySize:        80,
+		}),
+	}
+
+	req, err := http.NewRequest("POST", server.URL, strings.NewReader(`{"keys": [{"privateKeyData": "request-secret"}]}`))
+	if err != nil {
+		t.Fatalf("cannot create request: %s", err)
+	}
+	req.Header.Set("X-Session", "request-session")
+	res, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatalf("cannot read response: %s", err)
+	}
+	if !strings.Contains(string(body), "response-secret") {
+		t.Fatalf("expected the response body to be unchanged, got %q", body)
+	}
+
+	for _, secret := range []string{"request-session", "response-session", "request-secret", "response-secret"} {
+		if strings.Contains(logs.String(), secret) {
+			t.Errorf("expected %q to be redacted from the logs:\n%s", secret, logs.String())
+		}
+	}
+	if !strings.Contains(logs.String(), "bytes truncated]") {
+		t.Errorf("expected the response body to be truncated in the logs:\n%s", logs.String())
+	}
+}
+
+func TestExpandProviderLoggingConfig_formatFromEnv(t *testing.T) {
+	t.Setenv("GOOGLE_LOG_FORMAT", "json")
+	if config := transport_tpg.ExpandProviderLoggingConfig([]interface{}{}); !config.JsonFormat() {
+		t.Fatalf("expected a JSON configuration from GOOGLE_LOG_FORMAT, got %#v", config)
+	}
+
+	config := transport_tpg.ExpandProviderLoggingConfig([]interface{}{
+		map[string]interface{}{
+			"format": transport_tpg.LogFormatText,
+		},
+	})
+	if config.JsonFormat() {
+		t.Fatalf("expected the format of the configuration to override GOOGLE_LOG_FORMAT, got %#v", config)
+	}
+
+	t.Setenv("GOOGLE_LOG_FORMAT", "yaml")
+	if config := transport_tpg.ExpandProviderLoggingConfig([]interface{}{}); config != nil {
+		t.Fatalf("expected an invalid GOOGLE_LOG_FORMAT to be ignored, got %#v", config)
+	}
+}
+
+func TestLoggingTransport_json(t *testing.T) {
+	t.Setenv("TF_LOG", "DEBUG")
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"name": "key", "privateKeyData": "response-secret"}`))
+	}))
+	defer server.Close()
+
+	loggingTransport := transport_tpg.NewLoggingTransport("Google", http.DefaultTransport, &transport_tpg.LoggingConfig{
+		Format:             transport_tpg.LogFormatJson,
+		RedactedBodyFields: []string{"privateKeyData"},
+	})
+	client := &http.Client{
+		Transport: transport_tpg.NewTransportWithRetryConfig(loggingTransport, &transport_tpg.RetryConfig{
+			MaxAttempts:    2,
+			InitialBackoff: time.Millisecond,
+		}),
+	}
+
+	res, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	res.Body.Close()
+
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(logs.String(), "\n") {
+		i := strings.Index(line, "[DEBUG] {")
+		if i < 0 {
+			continue
+		}
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line[i+len("[DEBUG] "):]), &entry); err != nil {
+			t.Fatalf("cannot parse log entry %q: %s", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 log entries, got %d:\n%s", len(entries), logs.String())
+	}
+
+	for i, status := range []float64{http.StatusServiceUnavailable, http.StatusOK} {
+		entry := entries[i]
+		if entry["method"] != "GET" || entry["url"] != server.URL || entry["status"] != status || entry["retry_count"] != float64(i) {
+			t.Errorf("unexpected log entry %v", entry)
+		}
+		if _, ok := entry["latency_ms"]; !ok {
+			t.Errorf("expected the log entry to have a latency, got %v", entry)
+		}
+	}
+	if strings.Contains(logs.String(), "response-secret") {
+		t.Errorf("expected the response body to be redacted from the logs:\n%s", logs.String())
+	}
+}
@@ -0,0 +1,124 @@
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// ModuleAttributionDirEnvVar is the environment variable of the directory the
+// resources created by each module, according to the module_name of its
+// provider_meta block, are written to when the provider stops, e.g. at the end
+// of an apply, see WriteModuleAttribution.
+const ModuleAttributionDirEnvVar = "GOOGLE_MODULE_ATTRIBUTION_DIR"
+
+// ModuleAttribution is the machine-readable record of the resources created by
+// the modules of a configuration.
+type ModuleAttribution struct {
+	Modules []ModuleAttributionModule `json:"modules"`
+}
+
+// ModuleAttributionModule lists the resources created by a module.
+type ModuleAttributionModule struct {
+	ModuleName    string                      `json:"module_name"`
+	ModuleVersion string                      `json:"module_version,omitempty"`
+	Resources     []ModuleAttributionResource `json:"resources"`
+}
+
+// ModuleAttributionResource is a resource created by a module.
+type ModuleAttributionResource struct {
+	Type string `json:"type"`
+	Id   string `json:"id"`
+}
+
+var (
+	moduleAttributionMu sync.Mutex
+	// moduleAttribution holds the created resources by module name and version.
+	moduleAttribution = make(map[ProviderMeta][]ModuleAttributionResource)
+)
+
+// ModuleAttributionEnabled returns whether the created resources are recorded,
+// i.e. whether GOOGLE_MODULE_ATTRIBUTION_DIR is set.
+func ModuleAttributionEnabled() bool {
+	return os.Getenv(ModuleAttributionDirEnvVar) != ""
+}
+
+// RecordModuleAttribution records a resource created by the module of the
+// given provider_meta block. Resources created outside of modules declaring a
+// module_name aren't recorded.
+func RecordModuleAttribution(meta ProviderMeta, resourceType, id string) {
+	if meta.ModuleName == "" || !ModuleAttributionEnabled() {
+		return
+	}
+	// Only the module is relevant to the attribution.
+	meta.RequestReason = ""
+
+	moduleAttributionMu.Lock()
+	defer moduleAttributionMu.Unlock()
+
+	moduleAttribution[meta] = append(moduleAttribution[meta], ModuleAttributionResource{Type: resourceType, Id: id})
+}
+
+// ModuleAttributionReport returns the resources recorded so far, sorted by
+// module, then by type and id.
+func ModuleAttributionReport() ModuleAttribution {
+	moduleAttributionMu.Lock()
+	defer moduleAttributionMu.Unlock()
+
+	report := ModuleAttribution{Modules: make([]ModuleAttributionModule, 0, len(moduleAttribution))}
+	for meta, resources := range moduleAttribution {
+		resources = append([]ModuleAttributionResource{}, resources...)
+		sort.Slice(resources, func(i, j int) bool {
+			if resources[i].Type != resources[j].Type {
+				return resources[i].Type < resources[j].Type
+			}
+			return resources[i].Id < resources[j].Id
+		})
+		report.Modules = append(report.Modules, ModuleAttributionModule{
+			ModuleName:    meta.ModuleName,
+			ModuleVersion: meta.ModuleVersion,
+			Resources:     resources,
+		})
+	}
+	sort.Slice(report.Modules, func(i, j int) bool {
+		if report.Modules[i].ModuleName != report.Modules[j].ModuleName {
+			return report.Modules[i].ModuleName < report.Modules[j].ModuleName
+		}
+		return report.Modules[i].ModuleVersion < report.Modules[j].ModuleVersion
+	})
+	return report
+}
+
+// WriteModuleAttribution writes the resources recorded by
+// RecordModuleAttribution as JSON to the directory of the
+// GOOGLE_MODULE_ATTRIBUTION_DIR environment variable, if set and if any
+// resources were recorded, named after the process as several providers may
+// run at once.
+func WriteModuleAttribution() {
+	dir := os.Getenv(ModuleAttributionDirEnvVar)
+	if dir == "" {
+		return
+	}
+
+	report := ModuleAttributionReport()
+	if len(report.Modules) == 0 {
+		return
+	}
+
+	b, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		log.Printf("[WARN] Unable to encode module attribution: %s", err)
+		return
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("module-attribution-%d.json", os.Getpid()))
+	if err := os.WriteFile(path, b, 0644); err != nil {
+		log.Printf("[WARN] Unable to write module attribution %s: %s", path, err)
+		return
+	}
+	log.Printf("[DEBUG] Wrote module attribution %s", path)
+}
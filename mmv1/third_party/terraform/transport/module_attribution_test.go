@@ -0,0 +1,42 @@
+package transport_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+)
+
+func TestWriteModuleAttribution(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(transport_tpg.ModuleAttributionDirEnvVar, dir)
+
+	vm := transport_tpg.ProviderMeta{ModuleName: "blueprints/terraform/terraform-google-vm", ModuleVersion: "v11.0.0"}
+	transport_tpg.RecordModuleAttribution(vm, "google_compute_instance", "projects/p/zones/z/instances/b")
+	transport_tpg.RecordModuleAttribution(vm, "google_compute_instance", "projects/p/zones/z/instances/a")
+	transport_tpg.RecordModuleAttribution(transport_tpg.ProviderMeta{}, "google_compute_network", "projects/p/global/networks/n")
+
+	transport_tpg.WriteModuleAttribution()
+
+	b, err := os.ReadFile(filepath.Join(dir, fmt.Sprintf("module-attribution-%d.json", os.Getpid())))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report transport_tpg.ModuleAttribution
+	if err := json.Unmarshal(b, &report); err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Modules) != 1 {
+		t.Fatalf("expected the resources of a single module, got %s", b)
+	}
+	module := report.Modules[0]
+	if module.ModuleName != vm.ModuleName || module.ModuleVersion != vm.ModuleVersion {
+		t.Errorf("expected the resources of module %s, got %s", vm.UserAgentProduct(), b)
+	}
+	if len(module.Resources) != 2 || module.Resources[0].Id != "projects/p/zones/z/instances/a" || module.Resources[1].Id != "projects/p/zones/z/instances/b" {
+		t.Errorf("expected the 2 instances sorted by id, got %s", b)
+	}
+}
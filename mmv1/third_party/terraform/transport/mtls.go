@@ -0,0 +1,98 @@
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"google.golang.org/api/option/internaloption"
+	"google.golang.org/api/transport"
+
+	"github.com/hashicorp/terraform-provider-google/google/verify"
+)
+
+// MtlsEndpoint returns the mTLS endpoint of the given regular endpoint.
+func MtlsEndpoint(baseEndpoint string) string {
+	u, err := url.Parse(baseEndpoint)
+	if err != nil {
+		if strings.Contains(baseEndpoint, ".googleapis") {
+			return strings.Replace(baseEndpoint, ".googleapis", ".mtls.googleapis", 1)
+		}
+		return baseEndpoint
+	}
+	domainParts := strings.Split(u.Host, ".")
+	if len(domainParts) > 1 {
+		u.Host = fmt.Sprintf("%s.mtls.%s", domainParts[0], strings.Join(domainParts[1:], "."))
+	} else {
+		u.Host = fmt.Sprintf("%s.mtls", domainParts[0])
+	}
+	return u.String()
+}
+
+// MtlsEnabled returns whether the default client certificate source of the
+// environment switches clients to mTLS endpoints. The transport library does
+// not natively expose it, but returns the mTLS endpoint during client creation
+// if it's enabled, so a client is created and thrown away to determine it.
+func MtlsEnabled() bool {
+	regularEndpoint := "https://mockservice.googleapis.com/v1/"
+	mtlsEndpoint := MtlsEndpoint(regularEndpoint)
+	_, endpoint, err := transport.NewHTTPClient(context.Background(),
+		internaloption.WithDefaultEndpoint(regularEndpoint),
+		internaloption.WithDefaultMTLSEndpoint(mtlsEndpoint),
+	)
+	if err != nil {
+		return false
+	}
+	return endpoint == mtlsEndpoint
+}
+
+// SetMtlsBasePaths rewrites the given base paths, see NewDefaultBasePaths, to
+// mTLS endpoints, when the default client certificate source of the
+// environment or the client certificate of the provider configuration is
+// used. Base paths already rewritten are left as is.
+func SetMtlsBasePaths(basePaths map[string]string) {
+	for key, basePath := range basePaths {
+		if strings.Contains(basePath, ".mtls.") {
+			continue
+		}
+		basePaths[key] = MtlsEndpoint(basePath)
+	}
+}
+
+// DisableMtlsBasePaths rewrites the given base paths of the given services,
+// named like their custom endpoints, e.g. "compute" for
+// compute_custom_endpoint, back to regular endpoints, see the
+// mtls_disabled_services provider configuration. Services lacking mTLS
+// endpoints keep working this way.
+func DisableMtlsBasePaths(basePaths map[string]string, services []string) error {
+	for _, service := range services {
+		key, ok := ServiceBasePathKeys[service]
+		if !ok {
+			return fmt.Errorf("unknown service %q in mtls_disabled_services", service)
+		}
+		basePaths[key] = strings.Replace(basePaths[key], ".mtls.", ".", 1)
+	}
+	return nil
+}
+
+// ClientCertificate returns the client certificate of the client_certificate
+// and client_private_key provider configuration, which are either PEM encoded
+// or the paths of files containing them.
+func ClientCertificate(certificate, privateKey string) (tls.Certificate, error) {
+	certificateContents, _, err := verify.PathOrContents(certificate)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("error loading client certificate: %s", err)
+	}
+	privateKeyContents, _, err := verify.PathOrContents(privateKey)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("error loading client private key: %s", err)
+	}
+
+	cert, err := tls.X509KeyPair([]byte(certificateContents), []byte(privateKeyContents))
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("error loading client certificate: %s", err)
+	}
+	return cert, nil
+}
@@ -0,0 +1,45 @@
+package transport_test
+
+import (
+	"strings"
+	"testing"
+
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+)
+
+func TestUnitMtls_urlSwitching(t *testing.T) {
+	t.Parallel()
+	for key, bp := range transport_tpg.DefaultBasePaths {
+		url := transport_tpg.MtlsEndpoint(bp)
+		if !strings.Contains(url, ".mtls.") {
+			t.Errorf("%s: mtls conversion unsuccessful preconv - %s postconv - %s", key, bp, url)
+		}
+	}
+}
+
+func TestDisableMtlsBasePaths(t *testing.T) {
+	basePaths := transport_tpg.NewDefaultBasePaths()
+	transport_tpg.SetMtlsBasePaths(basePaths)
+	if err := transport_tpg.DisableMtlsBasePaths(basePaths, []string{"compute"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for key, bp := range basePaths {
+		if key == transport_tpg.ComputeBasePathKey {
+			if bp != transport_tpg.DefaultBasePaths[key] {
+				t.Errorf("expected the regular endpoint %s for compute, got %s", transport_tpg.DefaultBasePaths[key], bp)
+			}
+		} else if !strings.Contains(bp, ".mtls.") {
+			t.Errorf("%s: expected an mtls endpoint, got %s", key, bp)
+		}
+	}
+	for key, bp := range transport_tpg.DefaultBasePaths {
+		if strings.Contains(bp, ".mtls.") {
+			t.Errorf("%s: expected the default base paths to be left as is, got %s", key, bp)
+		}
+	}
+
+	if err := transport_tpg.DisableMtlsBasePaths(basePaths, []string{"unknown"}); err == nil || !strings.Contains(err.Error(), `unknown service "unknown"`) {
+		t.Fatalf("expected an unknown service error, got %v", err)
+	}
+}
@@ -1,8 +1,10 @@
 package transport
 
 import (
+	"fmt"
 	"log"
 	"sync"
+	"time"
 )
 
 // MutexKV is a simple key/value store for arbitrary mutexes. It can be used to
@@ -13,46 +15,69 @@ import (
 // their access to individual security groups based on SG ID.
 type MutexKV struct {
 	lock  sync.Mutex
-	store map[string]*sync.RWMutex
+	store map[string]*rwMutex
 }
 
 // Locks the mutex for the given key. Caller is responsible for calling Unlock
 // for the same key
 func (m *MutexKV) Lock(key string) {
+	// Without a timeout, the lock is always acquired.
+	_ = m.LockWithTimeout(key, 0)
+}
+
+// Locks the mutex for the given key, giving up with an error if it isn't
+// acquired within the timeout, or waiting forever if the timeout is zero.
+// Caller is responsible for calling Unlock for the same key if it succeeds
+func (m *MutexKV) LockWithTimeout(key string, timeout time.Duration) error {
 	log.Printf("[DEBUG] Locking %q", key)
-	m.get(key).Lock()
+	if !m.get(key).lock(true, timeout) {
+		return fmt.Errorf("timed out after %s waiting to lock %q", timeout, key)
+	}
 	log.Printf("[DEBUG] Locked %q", key)
+	return nil
 }
 
 // Unlock the mutex for the given key. Caller must have called Lock for the same key first
 func (m *MutexKV) Unlock(key string) {
 	log.Printf("[DEBUG] Unlocking %q", key)
-	m.get(key).Unlock()
+	m.get(key).unlock(true)
 	log.Printf("[DEBUG] Unlocked %q", key)
 }
 
 // Acquires a read-lock on the mutex for the given key. Caller is responsible for calling RUnlock
 // for the same key
 func (m *MutexKV) RLock(key string) {
+	// Without a timeout, the lock is always acquired.
+	_ = m.RLockWithTimeout(key, 0)
+}
+
+// Acquires a read-lock on the mutex for the given key, giving up with an error
+// if it isn't acquired within the timeout, or waiting forever if the timeout
+// is zero. Caller is responsible for calling RUnlock for the same key if it
+// succeeds
+func (m *MutexKV) RLockWithTimeout(key string, timeout time.Duration) error {
 	log.Printf("[DEBUG] RLocking %q", key)
-	m.get(key).RLock()
+	if !m.get(key).lock(false, timeout) {
+		return fmt.Errorf("timed out after %s waiting to read-lock %q", timeout, key)
+	}
 	log.Printf("[DEBUG] RLocked %q", key)
+	return nil
 }
 
 // Releases a read-lock on the mutex for the given key. Caller must have called RLock for the same key first
 func (m *MutexKV) RUnlock(key string) {
 	log.Printf("[DEBUG] RUnlocking %q", key)
-	m.get(key).RUnlock()
+	m.get(key).unlock(false)
 	log.Printf("[DEBUG] RUnlocked %q", key)
 }
 
 // Returns a mutex for the given key, no guarantee of its lock status
-func (m *MutexKV) get(key string) *sync.RWMutex {
+func (m *MutexKV) get(key string) *rwMutex {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 	mutex, ok := m.store[key]
 	if !ok {
-		mutex = &sync.RWMutex{}
+		mutex = newRWMutex()
 		m.store[key] = mutex
 	}
 	return mutex
@@ -61,7 +86,7 @@ func (m *MutexKV) get(key string) *sync.RWMutex {
 // Returns a properly initialized MutexKV
 func NewMutexKV() *MutexKV {
 	return &MutexKV{
-		store: make(map[string]*sync.RWMutex),
+		store: make(map[string]*rwMutex),
 	}
 }
 
@@ -74,3 +99,90 @@ func LockedCall(lockKey string, f func() error) error {
 
 	return f()
 }
+
+// rwMutex is a reader/writer mutual exclusion lock like sync.RWMutex, except
+// that waiting for it can time out.
+type rwMutex struct {
+	mu      sync.Mutex
+	readers int
+	writer  bool
+	// writersWaiting keeps new readers from acquiring the lock while writers
+	// wait for it, so that writers aren't starved by overlapping readers.
+	writersWaiting int
+	// released is closed, and replaced, whenever the lock is released.
+	released chan struct{}
+}
+
+func newRWMutex() *rwMutex {
+	return &rwMutex{
+		released: make(chan struct{}),
+	}
+}
+
+// lock acquires the lock for writing or reading, and returns whether it was
+// acquired before the timeout. A zero timeout waits forever.
+func (l *rwMutex) lock(write bool, timeout time.Duration) bool {
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	l.mu.Lock()
+	if write {
+		l.writersWaiting++
+	}
+	for {
+		if write && !l.writer && l.readers == 0 {
+			l.writersWaiting--
+			l.writer = true
+			l.mu.Unlock()
+			return true
+		}
+		if !write && !l.writer && l.writersWaiting == 0 {
+			l.readers++
+			l.mu.Unlock()
+			return true
+		}
+
+		released := l.released
+		l.mu.Unlock()
+		select {
+		case <-released:
+			l.mu.Lock()
+		case <-expired:
+			if write {
+				l.mu.Lock()
+				l.writersWaiting--
+				// Readers may have been waiting for this writer only.
+				l.notify()
+				l.mu.Unlock()
+			}
+			return false
+		}
+	}
+}
+
+func (l *rwMutex) unlock(write bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if write {
+		if !l.writer {
+			panic("unlock of unlocked mutex")
+		}
+		l.writer = false
+	} else {
+		if l.readers == 0 {
+			panic("runlock of unlocked mutex")
+		}
+		l.readers--
+	}
+	l.notify()
+}
+
+// notify wakes up the goroutines waiting for the lock. l.mu must be held.
+func (l *rwMutex) notify() {
+	close(l.released)
+	l.released = make(chan struct{})
+}
@@ -0,0 +1,27 @@
+package transport
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultPollInterval is the interval operations are polled at unless
+// operation_poll_interval is set.
+const DefaultPollInterval = 10 * time.Second
+
+// ParseServicePollIntervals parses the service_operation_poll_intervals
+// provider configuration, i.e. durations by service name, e.g. "compute".
+func ParseServicePollIntervals(intervals map[string]string) (map[string]time.Duration, error) {
+	result := make(map[string]time.Duration, len(intervals))
+	for service, v := range intervals {
+		if _, ok := ServiceBasePathKeys[service]; !ok {
+			return nil, fmt.Errorf("unknown service %q in service_operation_poll_intervals", service)
+		}
+		interval, err := time.ParseDuration(v)
+		if err != nil || interval <= 0 {
+			return nil, fmt.Errorf("unable to parse a positive duration from service_operation_poll_intervals value %q for service %q", v, service)
+		}
+		result[service] = interval
+	}
+	return result, nil
+}
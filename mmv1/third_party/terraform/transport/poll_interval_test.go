@@ -0,0 +1,43 @@
+package transport_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+)
+
+func TestParseServicePollIntervals(t *testing.T) {
+	intervals, err := transport_tpg.ParseServicePollIntervals(map[string]string{"compute": "30s"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(intervals) != 1 || intervals["compute"] != 30*time.Second {
+		t.Fatalf("expected an interval of 30s for compute, got %v", intervals)
+	}
+
+	if _, err := transport_tpg.ParseServicePollIntervals(map[string]string{"unknown": "30s"}); err == nil || !strings.Contains(err.Error(), `unknown service "unknown"`) {
+		t.Fatalf("expected an unknown service error, got %v", err)
+	}
+
+	for _, v := range []string{"often", "0s", "-1s"} {
+		if _, err := transport_tpg.ParseServicePollIntervals(map[string]string{"compute": v}); err == nil || !strings.Contains(err.Error(), "unable to parse a positive duration") {
+			t.Fatalf("expected a duration error for %q, got %v", v, err)
+		}
+	}
+}
+
+func TestConfigServicePollInterval(t *testing.T) {
+	config := &transport_tpg.Config{
+		PollInterval:         transport_tpg.DefaultPollInterval,
+		ServicePollIntervals: map[string]time.Duration{"compute": time.Minute},
+	}
+
+	if got := config.ServicePollInterval("compute"); got != time.Minute {
+		t.Errorf("expected a poll interval of 1m for compute, got %s", got)
+	}
+	if got := config.ServicePollInterval("sql"); got != transport_tpg.DefaultPollInterval {
+		t.Errorf("expected the default poll interval for sql, got %s", got)
+	}
+}
@@ -0,0 +1,48 @@
+package transport
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// privateServiceConnectEndpointName matches the names of Private Service
+// Connect endpoints for Google APIs.
+var privateServiceConnectEndpointName = regexp.MustCompile(`^[a-z][a-z0-9]{0,19}$`)
+
+// PrivateServiceConnectEndpoint returns the endpoint of the given regular
+// endpoint through the Private Service Connect endpoint of the given name,
+// e.g. https://compute-myendpoint.p.googleapis.com/compute/v1/ for
+// https://compute.googleapis.com/compute/v1/. Endpoints of other domains, and
+// the ones whose host isn't a single service name followed by googleapis.com,
+// e.g. mTLS endpoints, are returned as is.
+func PrivateServiceConnectEndpoint(baseEndpoint, endpointName string) string {
+	scheme, rest, ok := strings.Cut(baseEndpoint, "://")
+	if !ok {
+		return baseEndpoint
+	}
+	host, path, _ := strings.Cut(rest, "/")
+	service, ok := strings.CutSuffix(host, "."+DefaultUniverseDomain)
+	if !ok || service == "" || strings.Contains(service, ".") {
+		return baseEndpoint
+	}
+	return fmt.Sprintf("%s://%s-%s.p.%s/%s", scheme, service, endpointName, DefaultUniverseDomain, path)
+}
+
+// SetPrivateServiceConnectBasePaths rewrites the given base paths, see
+// NewDefaultBasePaths, to go through the Private Service Connect endpoint of
+// the given name, see the private_service_connect_endpoint provider
+// configuration and PrivateServiceConnectEndpoint. Base paths already
+// rewritten are left as is.
+func SetPrivateServiceConnectBasePaths(basePaths map[string]string, endpointName string) error {
+	if endpointName == "" {
+		return nil
+	}
+	if !privateServiceConnectEndpointName.MatchString(endpointName) {
+		return fmt.Errorf("invalid private_service_connect_endpoint %q, expected up to 20 lowercase letters and digits starting with a letter", endpointName)
+	}
+	for key, basePath := range basePaths {
+		basePaths[key] = PrivateServiceConnectEndpoint(basePath, endpointName)
+	}
+	return nil
+}
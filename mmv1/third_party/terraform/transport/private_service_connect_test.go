@@ -0,0 +1,38 @@
+package transport_test
+
+import (
+	"strings"
+	"testing"
+
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+)
+
+func TestPrivateServiceConnectEndpoint(t *testing.T) {
+	cases := map[string]string{
+		"https://compute.googleapis.com/compute/v1/":              "https://compute-myendpoint.p.googleapis.com/compute/v1/",
+		"https://{{region}}-aiplatform.googleapis.com/v1/":        "https://{{region}}-aiplatform-myendpoint.p.googleapis.com/v1/",
+		"https://compute-myendpoint.p.googleapis.com/compute/v1/": "https://compute-myendpoint.p.googleapis.com/compute/v1/",
+		"https://compute.mtls.googleapis.com/compute/v1/":         "https://compute.mtls.googleapis.com/compute/v1/",
+		"https://compute.example.com/compute/v1/":                 "https://compute.example.com/compute/v1/",
+	}
+	for basePath, expected := range cases {
+		if endpoint := transport_tpg.PrivateServiceConnectEndpoint(basePath, "myendpoint"); endpoint != expected {
+			t.Errorf("expected %s for %s, got %s", expected, basePath, endpoint)
+		}
+	}
+}
+
+func TestSetPrivateServiceConnectBasePaths(t *testing.T) {
+	basePaths := transport_tpg.NewDefaultBasePaths()
+	if err := transport_tpg.SetPrivateServiceConnectBasePaths(basePaths, "My-Endpoint"); err == nil {
+		t.Fatalf("expected an error for an invalid endpoint name")
+	}
+
+	if err := transport_tpg.SetPrivateServiceConnectBasePaths(basePaths, "myendpoint"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := strings.Replace(transport_tpg.DefaultBasePaths[transport_tpg.ComputeBasePathKey], "compute.googleapis.com", "compute-myendpoint.p.googleapis.com", 1)
+	if bp := basePaths[transport_tpg.ComputeBasePathKey]; bp != expected {
+		t.Errorf("expected the compute base path %s, got %s", expected, bp)
+	}
+}
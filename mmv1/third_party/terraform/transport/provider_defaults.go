@@ -0,0 +1,77 @@
+package transport
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// ProviderEnvVars are the environment variables of the provider settings,
+// read in order by both the SDK and the framework providers when a setting
+// isn't configured.
+var ProviderEnvVars = map[string][]string{
+	"credentials": {
+		"GOOGLE_CREDENTIALS",
+		"GOOGLE_CLOUD_KEYFILE_JSON",
+		"GCLOUD_KEYFILE_JSON",
+	},
+	"access_token": {
+		"GOOGLE_OAUTH_ACCESS_TOKEN",
+	},
+	"impersonate_service_account": {
+		"GOOGLE_IMPERSONATE_SERVICE_ACCOUNT",
+	},
+	"project": {
+		"GOOGLE_PROJECT",
+		"GOOGLE_CLOUD_PROJECT",
+		"GCLOUD_PROJECT",
+		"CLOUDSDK_CORE_PROJECT",
+	},
+	"billing_project": {
+		"GOOGLE_BILLING_PROJECT",
+	},
+	"region": {
+		"GOOGLE_REGION",
+		"GCLOUD_REGION",
+		"CLOUDSDK_COMPUTE_REGION",
+	},
+	"zone": {
+		"GOOGLE_ZONE",
+		"GCLOUD_ZONE",
+		"CLOUDSDK_COMPUTE_ZONE",
+	},
+	"user_project_override": {
+		"USER_PROJECT_OVERRIDE",
+	},
+	"request_reason": {
+		"CLOUDSDK_CORE_REQUEST_REASON",
+	},
+	"grpc_log_level": {
+		"GOOGLE_GRPC_LOG_LEVEL",
+	},
+	"grpc_log_payloads": {
+		"GOOGLE_GRPC_LOG_PAYLOADS",
+	},
+}
+
+// ProviderEnvDefault returns the value of the first environment variable of
+// the given provider setting that is set, see ProviderEnvVars, or an empty
+// string.
+func ProviderEnvDefault(key string) string {
+	return MultiEnvSearch(ProviderEnvVars[key])
+}
+
+// ProviderEnvDefaultBool is ProviderEnvDefault for boolean settings. It
+// returns whether one of the environment variables is set, and an error if
+// its value isn't a boolean.
+func ProviderEnvDefaultBool(key string) (bool, bool, error) {
+	for _, k := range ProviderEnvVars[key] {
+		if v := getEnv(k); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return false, false, fmt.Errorf("error parsing environment variable `%s` into bool: %s", k, err)
+			}
+			return b, true, nil
+		}
+	}
+	return false, false, nil
+}
@@ -0,0 +1,55 @@
+package transport_test
+
+import (
+	"testing"
+
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+)
+
+func TestProviderEnvDefault(t *testing.T) {
+	t.Setenv("GOOGLE_PROJECT", "")
+	t.Setenv("GOOGLE_CLOUD_PROJECT", "")
+	t.Setenv("GCLOUD_PROJECT", "my-project")
+	t.Setenv("CLOUDSDK_CORE_PROJECT", "other-project")
+
+	if project := transport_tpg.ProviderEnvDefault("project"); project != "my-project" {
+		t.Errorf("expected the first project environment variable set, got %q", project)
+	}
+}
+
+func TestProviderEnvDefaultBool(t *testing.T) {
+	cases := map[string]struct {
+		Value         string
+		Expected      bool
+		ExpectedOk    bool
+		ExpectedError bool
+	}{
+		"unset": {},
+		"true": {
+			Value:      "true",
+			Expected:   true,
+			ExpectedOk: true,
+		},
+		"false": {
+			Value:      "0",
+			ExpectedOk: true,
+		},
+		"invalid": {
+			Value:         "yes",
+			ExpectedError: true,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			t.Setenv("USER_PROJECT_OVERRIDE", tc.Value)
+			b, ok, err := transport_tpg.ProviderEnvDefaultBool("user_project_override")
+			if (err != nil) != tc.ExpectedError {
+				t.Fatalf("expected error %t, got %v", tc.ExpectedError, err)
+			}
+			if b != tc.Expected || ok != tc.ExpectedOk {
+				t.Errorf("expected %t, %t, got %t, %t", tc.Expected, tc.ExpectedOk, b, ok)
+			}
+		})
+	}
+}
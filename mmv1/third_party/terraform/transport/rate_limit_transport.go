@@ -0,0 +1,87 @@
+package transport
+
+import (
+	"fmt"
+	"net/http"
+
+	"golang.org/x/time/rate"
+)
+
+// ServiceQpsLimitsByBasePath resolves the service_qps_limits provider
+// configuration, i.e. requests per second by service name, to limits by the
+// base path of the service. The base paths are given by service name, e.g.
+// "compute".
+func ServiceQpsLimitsByBasePath(limits map[string]float64, basePaths map[string]string) (map[string]float64, error) {
+	result := make(map[string]float64, len(limits))
+	for service, limit := range limits {
+		basePath, ok := basePaths[service]
+		if !ok {
+			return nil, fmt.Errorf("unknown service %q in service_qps_limits", service)
+		}
+		if limit <= 0 {
+			return nil, fmt.Errorf("service_qps_limits value %v for service %q must be positive", limit, service)
+		}
+		if basePath != "" {
+			result[basePath] = limit
+		}
+	}
+	return result, nil
+}
+
+// rateLimitTransport limits the number of requests in flight and the rate of
+// requests to each service, so that large numbers of resources don't exceed
+// API rate limits and end up retrying requests over and over.
+type rateLimitTransport struct {
+	baseTransit http.RoundTripper
+	// requests holds a value per request in flight, nil if their number isn't
+	// limited.
+	requests  chan struct{}
+	basePaths []string
+	limiters  map[string]*rate.Limiter
+}
+
+// NewTransportWithRateLimits returns a transport allowing at most
+// maxConcurrentRequests requests in flight, unless 0, and limiting requests by
+// base path to the given number of requests per second, see matchBasePath.
+// Requests wait for their turn until their context is done.
+func NewTransportWithRateLimits(baseTransit http.RoundTripper, maxConcurrentRequests int, qpsLimits map[string]float64) *rateLimitTransport {
+	if baseTransit == nil {
+		baseTransit = http.DefaultTransport
+	}
+
+	t := &rateLimitTransport{
+		baseTransit: baseTransit,
+		limiters:    make(map[string]*rate.Limiter, len(qpsLimits)),
+	}
+	if maxConcurrentRequests > 0 {
+		t.requests = make(chan struct{}, maxConcurrentRequests)
+	}
+
+	basePaths := make([]string, 0, len(qpsLimits))
+	for basePath, qps := range qpsLimits {
+		basePaths = append(basePaths, basePath)
+		t.limiters[basePath] = rate.NewLimiter(rate.Limit(qps), 1)
+	}
+	t.basePaths = sortBasePaths(basePaths)
+	return t
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if basePath, ok := matchBasePath(req.URL, t.basePaths); ok {
+		if err := t.limiters[basePath].Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if t.requests != nil {
+		select {
+		case t.requests <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		defer func() { <-t.requests }()
+	}
+
+	return t.baseTransit.RoundTrip(req)
+}
@@ -0,0 +1,117 @@
+package transport_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+)
+
+func TestServiceQpsLimitsByBasePath(t *testing.T) {
+	basePaths := map[string]string{
+		"compute": "https://compute.googleapis.com/compute/v1/",
+		"storage": "https://storage.googleapis.com/storage/v1/",
+	}
+
+	limits, err := transport_tpg.ServiceQpsLimitsByBasePath(map[string]float64{"compute": 20}, basePaths)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(limits) != 1 || limits["https://compute.googleapis.com/compute/v1/"] != 20 {
+		t.Fatalf("expected a limit of 20 for compute, got %v", limits)
+	}
+
+	if _, err := transport_tpg.ServiceQpsLimitsByBasePath(map[string]float64{"unknown": 20}, basePaths); err == nil || !strings.Contains(err.Error(), `unknown service "unknown"`) {
+		t.Fatalf("expected an unknown service error, got %v", err)
+	}
+
+	if _, err := transport_tpg.ServiceQpsLimitsByBasePath(map[string]float64{"compute": 0}, basePaths); err == nil || !strings.Contains(err.Error(), "must be positive") {
+		t.Fatalf("expected a non-positive limit error, got %v", err)
+	}
+}
+
+func TestRateLimitTransport_maxConcurrentRequests(t *testing.T) {
+	var inFlight, maxInFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client := &http.Client{
+		Transport: transport_tpg.NewTransportWithRateLimits(nil, 2, nil),
+	}
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := client.Get(server.URL)
+			if err != nil {
+				t.Errorf("unexpected error: %s", err)
+				return
+			}
+			res.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	if maxInFlight > 2 {
+		t.Fatalf("expected at most 2 requests in flight, got %d", maxInFlight)
+	}
+}
+
+func TestRateLimitTransport_qpsLimits(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	otherServer := httptest.NewServer(handler)
+	defer otherServer.Close()
+
+	client := &http.Client{
+		Transport: transport_tpg.NewTransportWithRateLimits(nil, 0, map[string]float64{
+			server.URL + "/limited/": 20,
+		}),
+	}
+
+	get := func(rawurl string) {
+		res, err := client.Get(rawurl)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		res.Body.Close()
+	}
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		get(server.URL + "/limited/")
+	}
+	// The first request is sent right away, the others every 50ms.
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Fatalf("expected requests to be limited to 20 per second, 5 requests took %s", elapsed)
+	}
+
+	start = time.Now()
+	for i := 0; i < 5; i++ {
+		get(otherServer.URL + "/")
+	}
+	if elapsed := time.Since(start); elapsed > 200*time.Millisecond {
+		t.Fatalf("expected requests to other services to be unlimited, 5 requests took %s", elapsed)
+	}
+}
@@ -0,0 +1,38 @@
+package transport
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseResourceCustomEndpoints parses the resource_custom_endpoints provider
+// configuration, i.e. base paths by resource type, e.g.
+// google_compute_instance_template, overriding the base path of the service
+// of the resource, e.g. to use the beta version of the Compute Engine API for a
+// few resources. The base paths are validated like *_custom_endpoint.
+func ParseResourceCustomEndpoints(endpoints map[string]string) (map[string]string, error) {
+	result := make(map[string]string, len(endpoints))
+	for resourceType, endpoint := range endpoints {
+		if !strings.HasPrefix(resourceType, "google_") {
+			return nil, fmt.Errorf("unknown resource type %q in resource_custom_endpoints", resourceType)
+		}
+		if _, errs := ValidateCustomEndpoint(endpoint, fmt.Sprintf("resource_custom_endpoints.%s", resourceType)); len(errs) > 0 {
+			return nil, errs[0]
+		}
+		result[resourceType] = endpoint
+	}
+	return result, nil
+}
+
+// ResourceBasePath returns the base path of the given resource type set in
+// resource_custom_endpoints, if any, or basePath, e.g. "{{ComputeBasePath}}".
+// Operation waiters keep polling the base path of the service.
+func (c *Config) ResourceBasePath(resourceType, basePath string) string {
+	if c == nil {
+		return basePath
+	}
+	if endpoint, ok := c.ResourceCustomEndpoints[resourceType]; ok {
+		return endpoint
+	}
+	return basePath
+}
@@ -0,0 +1,46 @@
+package transport_test
+
+import (
+	"strings"
+	"testing"
+
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+)
+
+func TestParseResourceCustomEndpoints(t *testing.T) {
+	endpoints, err := transport_tpg.ParseResourceCustomEndpoints(map[string]string{
+		"google_compute_instance_template": "https://compute.googleapis.com/compute/beta/",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(endpoints) != 1 || endpoints["google_compute_instance_template"] != "https://compute.googleapis.com/compute/beta/" {
+		t.Fatalf("unexpected endpoints %v", endpoints)
+	}
+
+	if _, err := transport_tpg.ParseResourceCustomEndpoints(map[string]string{"compute_instance": "https://compute.googleapis.com/compute/beta/"}); err == nil || !strings.Contains(err.Error(), `unknown resource type "compute_instance"`) {
+		t.Fatalf("expected an unknown resource type error, got %v", err)
+	}
+
+	if _, err := transport_tpg.ParseResourceCustomEndpoints(map[string]string{"google_compute_instance": "https://compute.googleapis.com/compute/beta"}); err == nil || !strings.Contains(err.Error(), "must end with a slash") {
+		t.Fatalf("expected an invalid endpoint error, got %v", err)
+	}
+}
+
+func TestConfigResourceBasePath(t *testing.T) {
+	config := &transport_tpg.Config{
+		ResourceCustomEndpoints: map[string]string{"google_compute_instance_template": "https://compute.googleapis.com/compute/beta/"},
+	}
+
+	if got := config.ResourceBasePath("google_compute_instance_template", "{{ComputeBasePath}}"); got != "https://compute.googleapis.com/compute/beta/" {
+		t.Errorf("expected the beta endpoint for google_compute_instance_template, got %s", got)
+	}
+	if got := config.ResourceBasePath("google_compute_instance", "{{ComputeBasePath}}"); got != "{{ComputeBasePath}}" {
+		t.Errorf("expected the service base path for google_compute_instance, got %s", got)
+	}
+
+	var nilConfig *transport_tpg.Config
+	if got := nilConfig.ResourceBasePath("google_compute_instance", "{{ComputeBasePath}}"); got != "{{ComputeBasePath}}" {
+		t.Errorf("expected the service base path without a config, got %s", got)
+	}
+}
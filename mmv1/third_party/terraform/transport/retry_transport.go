@@ -36,8 +36,10 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
+	"math/rand"
 	"net/http"
 	"net/http/httputil"
+	"strconv"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -45,15 +47,154 @@ import (
 )
 
 const defaultRetryTransportTimeoutSec = 90
+const defaultRetryTransportInitialBackoff = 500 * time.Millisecond
+
+// RetryConfig parameterizes the retries of a retryTransport, see the retry
+// provider block.
+type RetryConfig struct {
+	// MaxAttempts limits the number of attempts of a request, 0 for no limit
+	// other than the timeout of the request.
+	MaxAttempts int
+	// InitialBackoff is the time to wait before the first retry. Later waits
+	// grow following the Fibonacci sequence.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the time to wait between attempts, 0 for no cap.
+	MaxBackoff time.Duration
+	// Jitter is the fraction, between 0 and 1, of each wait that is
+	// randomized, so that requests failing together aren't retried together.
+	// Each wait is shortened by a random duration up to Jitter times itself.
+	Jitter float64
+	// DisableErrorCodeRetries disables retries of requests failing with 429
+	// and 5xx error codes.
+	DisableErrorCodeRetries bool
+	// AdditionalErrorCodes are HTTP error codes retried in addition to the
+	// common ones, even if DisableErrorCodeRetries is set.
+	AdditionalErrorCodes []int
+	// AdditionalErrorReasons are strings, e.g. error reasons, retried when
+	// found in error responses.
+	AdditionalErrorReasons []string
+	// IgnoreRetryAfter disables waiting for the delay servers ask for before
+	// retrying, see serverRetryDelay, when it's longer than the backoff.
+	IgnoreRetryAfter bool
+	// PauseBatchers enables pausing the request batchers of services asking
+	// for a delay before retrying, through OnServerDelay.
+	PauseBatchers bool
+	// OnServerDelay, if set, is called with requests whose server asked for a
+	// delay before retrying them.
+	OnServerDelay func(req *http.Request, delay time.Duration)
+}
+
+// additionalRetryPredicates returns the predicates of the additional errors
+// of the configuration.
+func (c RetryConfig) additionalRetryPredicates() []RetryErrorPredicateFunc {
+	var predicates []RetryErrorPredicateFunc
+	if len(c.AdditionalErrorCodes) > 0 {
+		predicates = append(predicates, IsOneOfErrorCodes(c.AdditionalErrorCodes))
+	}
+	if len(c.AdditionalErrorReasons) > 0 {
+		predicates = append(predicates, IsOneOfErrorReasons(c.AdditionalErrorReasons))
+	}
+	return predicates
+}
+
+// jittered returns the time to wait for the given backoff, randomized
+// according to Jitter.
+func (c RetryConfig) jittered(backoff time.Duration) time.Duration {
+	if c.Jitter <= 0 {
+		return backoff
+	}
+	return backoff - time.Duration(c.Jitter*rand.Float64()*float64(backoff))
+}
+
+// DefaultRetryConfig returns the retry configuration used unless the retry
+// provider block is set.
+func DefaultRetryConfig() *RetryConfig {
+	return &RetryConfig{
+		InitialBackoff: defaultRetryTransportInitialBackoff,
+	}
+}
 
 // NewTransportWithDefaultRetries constructs a default retryTransport that will retry common temporary errors
 func NewTransportWithDefaultRetries(t http.RoundTripper) *retryTransport {
+	return NewTransportWithRetryConfig(t, nil)
+}
+
+// NewTransportWithRetryConfig constructs a retryTransport that will retry
+// common temporary errors as configured, or as by default if config is nil.
+func NewTransportWithRetryConfig(t http.RoundTripper, config *RetryConfig) *retryTransport {
+	if config == nil {
+		config = DefaultRetryConfig()
+	}
 	return &retryTransport{
-		retryPredicates: defaultErrorRetryPredicates,
+		retryPredicates: append(config.additionalRetryPredicates(), defaultErrorRetryPredicates...),
 		internal:        t,
+		config:          *config,
 	}
 }
 
+func ExpandProviderRetryConfig(v interface{}) (*RetryConfig, error) {
+	config := DefaultRetryConfig()
+
+	if v == nil {
+		return config, nil
+	}
+	ls := v.([]interface{})
+	if len(ls) == 0 || ls[0] == nil {
+		return config, nil
+	}
+
+	cfgV := ls[0].(map[string]interface{})
+	if maxAttempts, ok := cfgV["max_attempts"]; ok {
+		config.MaxAttempts = maxAttempts.(int)
+	}
+
+	if initialBackoffV, ok := cfgV["initial_backoff"]; ok && initialBackoffV != "" {
+		initialBackoff, err := time.ParseDuration(initialBackoffV.(string))
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse duration from 'initial_backoff' value %q", initialBackoffV)
+		}
+		config.InitialBackoff = initialBackoff
+	}
+
+	if maxBackoffV, ok := cfgV["max_backoff"]; ok && maxBackoffV != "" {
+		maxBackoff, err := time.ParseDuration(maxBackoffV.(string))
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse duration from 'max_backoff' value %q", maxBackoffV)
+		}
+		config.MaxBackoff = maxBackoff
+	}
+
+	if jitter, ok := cfgV["jitter"]; ok {
+		config.Jitter = jitter.(float64)
+	}
+
+	if retryErrorCodes, ok := cfgV["retry_on_error_codes"]; ok {
+		config.DisableErrorCodeRetries = !retryErrorCodes.(bool)
+	}
+
+	if additionalErrorCodes, ok := cfgV["additional_error_codes"]; ok {
+		for _, code := range additionalErrorCodes.([]interface{}) {
+			config.AdditionalErrorCodes = append(config.AdditionalErrorCodes, code.(int))
+		}
+	}
+
+	if additionalErrorReasons, ok := cfgV["additional_error_reasons"]; ok {
+		for _, reason := range additionalErrorReasons.([]interface{}) {
+			config.AdditionalErrorReasons = append(config.AdditionalErrorReasons, reason.(string))
+		}
+	}
+
+	if honorRetryAfter, ok := cfgV["honor_retry_after"]; ok {
+		config.IgnoreRetryAfter = !honorRetryAfter.(bool)
+	}
+
+	if pauseBatchers, ok := cfgV["pause_batchers"]; ok {
+		config.PauseBatchers = pauseBatchers.(bool)
+	}
+
+	return config, nil
+}
+
 // Helper method to create a shallow copy of an HTTP client with a shallow-copied retryTransport
 // s.t. the base HTTP transport is the same (i.e. client connection pools are shared, retryPredicates are different)
 func ClientWithAdditionalRetries(baseClient *http.Client, predicates ...RetryErrorPredicateFunc) *http.Client {
@@ -74,6 +215,7 @@ func (t *retryTransport) WithAddedPredicates(predicates ...RetryErrorPredicateFu
 type retryTransport struct {
 	retryPredicates []RetryErrorPredicateFunc
 	internal        http.RoundTripper
+	config          RetryConfig
 }
 
 // RoundTrip implements the RoundTripper interface method.
@@ -94,8 +236,11 @@ func (t *retryTransport) RoundTrip(req *http.Request) (resp *http.Response, resp
 	}
 
 	attempts := 0
-	backoff := time.Millisecond * 500
-	nextBackoff := time.Millisecond * 500
+	backoff := t.config.InitialBackoff
+	if backoff <= 0 {
+		backoff = defaultRetryTransportInitialBackoff
+	}
+	nextBackoff := backoff
 
 	// VCR depends on the original request body being consumed, so
 	// consume here. Since this won't affect the request itself,
@@ -121,6 +266,7 @@ Retry:
 
 		log.Printf("[DEBUG] Retry Transport: request attempt %d", attempts)
 		// Do the wrapped Roundtrip. This is one request in the retry loop.
+		newRequest = newRequest.WithContext(withRetryCount(newRequest.Context(), attempts))
 		resp, respErr = t.internal.RoundTrip(newRequest)
 		attempts++
 
@@ -133,14 +279,31 @@ Retry:
 			log.Printf("[DEBUG] Retry Transport: Stopping retries, last request failed with non-retryable error: %s", retryErr.Err)
 			break Retry
 		}
+		if t.config.MaxAttempts > 0 && attempts >= t.config.MaxAttempts {
+			log.Printf("[DEBUG] Retry Transport: Stopping retries, reached %d attempts", attempts)
+			break Retry
+		}
+		if t.config.MaxBackoff > 0 && backoff > t.config.MaxBackoff {
+			backoff = t.config.MaxBackoff
+		}
 
-		log.Printf("[DEBUG] Retry Transport: Waiting %s before trying request again", backoff)
+		wait := t.config.jittered(backoff)
+		if delay, ok := serverRetryDelay(resp, retryErr.Err); ok && !t.config.IgnoreRetryAfter {
+			if t.config.OnServerDelay != nil {
+				t.config.OnServerDelay(newRequest, delay)
+			}
+			if delay > wait {
+				log.Printf("[DEBUG] Retry Transport: Server asked to retry after %s", delay)
+				wait = delay
+			}
+		}
+		log.Printf("[DEBUG] Retry Transport: Waiting %s before trying request again", wait)
 		select {
 		case <-ctx.Done():
 			log.Printf("[DEBUG] Retry Transport: Stopping retries, context done: %v", ctx.Err())
 			break Retry
-		case <-time.After(backoff):
-			log.Printf("[DEBUG] Retry Transport: Finished waiting %s before next retry", backoff)
+		case <-time.After(wait):
+			log.Printf("[DEBUG] Retry Transport: Finished waiting %s before next retry", wait)
 
 			// Fibonnaci backoff - 0.5, 1, 1.5, 2.5, 4, 6.5, 10.5, ...
 			lastBackoff := backoff
@@ -153,6 +316,41 @@ Retry:
 	return resp, respErr
 }
 
+// serverRetryDelay returns the delay before retrying a request that its server
+// asked for, either in a Retry-After header, in seconds or as a date, or in the
+// RetryInfo details of a Google API error, e.g. of a quota error.
+func serverRetryDelay(resp *http.Response, err error) (time.Duration, bool) {
+	if resp != nil {
+		if v := resp.Header.Get("Retry-After"); v != "" {
+			if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+				return time.Duration(seconds) * time.Second, true
+			}
+			if date, err := http.ParseTime(v); err == nil {
+				if delay := time.Until(date); delay > 0 {
+					return delay, true
+				}
+				return 0, true
+			}
+		}
+	}
+
+	var gerr *googleapi.Error
+	if errors.As(err, &gerr) {
+		for _, detail := range gerr.Details {
+			m, ok := detail.(map[string]interface{})
+			if !ok || m["@type"] != "type.googleapis.com/google.rpc.RetryInfo" {
+				continue
+			}
+			if v, ok := m["retryDelay"].(string); ok {
+				if delay, err := time.ParseDuration(v); err == nil {
+					return delay, true
+				}
+			}
+		}
+	}
+	return 0, false
+}
+
 // copyHttpRequest provides an copy of the given HTTP request for one RoundTrip.
 // If the request has a non-empty body (io.ReadCloser), the body is deep copied
 // so it can be consumed.
@@ -204,6 +402,13 @@ func (t *retryTransport) checkForRetryableError(resp *http.Response, respErr err
 	if errToCheck == nil {
 		return nil
 	}
+	if t.config.DisableErrorCodeRetries {
+		isErrorCode, _ := isCommonRetryableErrorCode(errToCheck)
+		isAdditionalErrorCode, _ := IsOneOfErrorCodes(t.config.AdditionalErrorCodes)(errToCheck)
+		if isErrorCode && !isAdditionalErrorCode {
+			return resource.NonRetryableError(errToCheck)
+		}
+	}
 	if IsRetryableError(errToCheck, t.retryPredicates, nil) {
 		return resource.RetryableError(errToCheck)
 	}
@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"sync"
 	"testing"
@@ -170,6 +171,252 @@ func TestRetryTransport_DoesNotRetryEmptyGetBody(t *testing.T) {
 }
 
 // handlers
+// Check that retries stop after the configured number of attempts
+func TestRetryTransport_MaxAttempts(t *testing.T) {
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(testRetryTransportCodeRetry)
+		if _, err := w.Write([]byte(fmt.Sprintf("Code: %d", testRetryTransportCodeRetry))); err != nil {
+			t.Errorf("[ERROR] unable to write to response writer: %v", err)
+		}
+	}))
+	defer ts.Close()
+
+	client := ts.Client()
+	client.Transport = NewTransportWithRetryConfig(http.DefaultTransport, &RetryConfig{
+		MaxAttempts:    3,
+		InitialBackoff: 10 * time.Millisecond,
+	})
+
+	resp, err := client.Get(ts.URL)
+	testRetryTransport_checkFailure(t, resp, err, testRetryTransportCodeRetry)
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts)
+	}
+}
+
+// Check that error codes aren't retried if disabled
+func TestRetryTransport_DisableErrorCodeRetries(t *testing.T) {
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(503)
+		if _, err := w.Write([]byte("Code: 503")); err != nil {
+			t.Errorf("[ERROR] unable to write to response writer: %v", err)
+		}
+	}))
+	defer ts.Close()
+
+	client := ts.Client()
+	client.Transport = NewTransportWithRetryConfig(http.DefaultTransport, &RetryConfig{
+		InitialBackoff:          10 * time.Millisecond,
+		DisableErrorCodeRetries: true,
+	})
+
+	resp, err := client.Get(ts.URL)
+	testRetryTransport_checkFailure(t, resp, err, 503)
+	if attempts != 1 {
+		t.Errorf("expected a single attempt, got %d", attempts)
+	}
+}
+
+// Check that additional error codes and reasons are retried
+func TestRetryTransport_AdditionalErrors(t *testing.T) {
+	cases := map[string]struct {
+		code   int
+		body   string
+		config RetryConfig
+	}{
+		"error code": {
+			code: 409,
+			body: "Code: 409",
+			config: RetryConfig{
+				AdditionalErrorCodes: []int{409},
+			},
+		},
+		"error code with error code retries disabled": {
+			code: 503,
+			body: "Code: 503",
+			config: RetryConfig{
+				DisableErrorCodeRetries: true,
+				AdditionalErrorCodes:    []int{503},
+			},
+		},
+		"error reason": {
+			code: 400,
+			body: `{"error": {"code": 400, "message": "not yet", "errors": [{"reason": "customNotReady"}]}}`,
+			config: RetryConfig{
+				AdditionalErrorReasons: []string{"customNotReady"},
+			},
+		},
+	}
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			attempts := 0
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				w.WriteHeader(tc.code)
+				if _, err := w.Write([]byte(tc.body)); err != nil {
+					t.Errorf("[ERROR] unable to write to response writer: %v", err)
+				}
+			}))
+			defer ts.Close()
+
+			tc.config.MaxAttempts = 2
+			tc.config.InitialBackoff = 10 * time.Millisecond
+			client := ts.Client()
+			client.Transport = NewTransportWithRetryConfig(http.DefaultTransport, &tc.config)
+
+			resp, err := client.Get(ts.URL)
+			testRetryTransport_checkFailure(t, resp, err, tc.code)
+			if attempts != 2 {
+				t.Errorf("expected 2 attempts, got %d", attempts)
+			}
+		})
+	}
+}
+
+// Check that the delays servers ask for are waited for
+func TestRetryTransport_RetryAfter(t *testing.T) {
+	cases := map[string]struct {
+		config      RetryConfig
+		expectedMin time.Duration
+		expectedMax time.Duration
+	}{
+		"honored": {
+			expectedMin: time.Second,
+			expectedMax: time.Minute,
+		},
+		"ignored": {
+			config:      RetryConfig{IgnoreRetryAfter: true},
+			expectedMax: time.Second,
+		},
+	}
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			attempts := 0
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				if attempts == 1 {
+					w.Header().Set("Retry-After", "1")
+					w.WriteHeader(429)
+					return
+				}
+				w.WriteHeader(testRetryTransportCodeSuccess)
+			}))
+			defer ts.Close()
+
+			var serverDelay time.Duration
+			tc.config.InitialBackoff = 10 * time.Millisecond
+			tc.config.OnServerDelay = func(req *http.Request, delay time.Duration) {
+				serverDelay = delay
+			}
+			client := ts.Client()
+			client.Transport = NewTransportWithRetryConfig(http.DefaultTransport, &tc.config)
+
+			start := time.Now()
+			resp, err := client.Get(ts.URL)
+			elapsed := time.Since(start)
+			testRetryTransport_checkSuccess(t, resp, err)
+			if elapsed < tc.expectedMin || elapsed > tc.expectedMax {
+				t.Errorf("expected the request to take between %s and %s, took %s", tc.expectedMin, tc.expectedMax, elapsed)
+			}
+			if !tc.config.IgnoreRetryAfter && serverDelay != time.Second {
+				t.Errorf("expected OnServerDelay to be called with a delay of 1s, got %s", serverDelay)
+			}
+		})
+	}
+}
+
+func TestServerRetryDelay(t *testing.T) {
+	retryInfoErr := &googleapi.Error{
+		Code: 429,
+		Details: []interface{}{
+			map[string]interface{}{
+				"@type":  "type.googleapis.com/google.rpc.ErrorInfo",
+				"reason": "RATE_LIMIT_EXCEEDED",
+			},
+			map[string]interface{}{
+				"@type":      "type.googleapis.com/google.rpc.RetryInfo",
+				"retryDelay": "30s",
+			},
+		},
+	}
+	if delay, ok := serverRetryDelay(nil, fmt.Errorf("wrapped: %w", retryInfoErr)); !ok || delay != 30*time.Second {
+		t.Errorf("expected a delay of 30s from RetryInfo, got %s", delay)
+	}
+
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)}}}
+	if delay, ok := serverRetryDelay(resp, nil); !ok || delay <= 50*time.Second || delay > time.Minute {
+		t.Errorf("expected a delay of about 1m from a Retry-After date, got %s", delay)
+	}
+
+	if _, ok := serverRetryDelay(&http.Response{}, &googleapi.Error{Code: 503}); ok {
+		t.Errorf("expected no delay without Retry-After or RetryInfo")
+	}
+}
+
+func TestExpandProviderRetryConfig(t *testing.T) {
+	config, err := ExpandProviderRetryConfig([]interface{}{
+		map[string]interface{}{
+			"max_attempts":             5,
+			"initial_backoff":          "1s",
+			"max_backoff":              "30s",
+			"jitter":                   0.5,
+			"retry_on_error_codes":     false,
+			"additional_error_codes":   []interface{}{409},
+			"additional_error_reasons": []interface{}{"resourceNotReady"},
+			"honor_retry_after":        false,
+			"pause_batchers":           true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := &RetryConfig{
+		MaxAttempts:             5,
+		InitialBackoff:          time.Second,
+		MaxBackoff:              30 * time.Second,
+		Jitter:                  0.5,
+		DisableErrorCodeRetries: true,
+		AdditionalErrorCodes:    []int{409},
+		AdditionalErrorReasons:  []string{"resourceNotReady"},
+		IgnoreRetryAfter:        true,
+		PauseBatchers:           true,
+	}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("expected retry config %+v, got %+v", expected, config)
+	}
+
+	config, err = ExpandProviderRetryConfig(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(config, DefaultRetryConfig()) {
+		t.Errorf("expected the default retry config, got %+v", config)
+	}
+
+	if _, err := ExpandProviderRetryConfig([]interface{}{map[string]interface{}{"initial_backoff": "soon"}}); err == nil {
+		t.Errorf("expected an error for an invalid duration")
+	}
+}
+
+func TestRetryConfig_jittered(t *testing.T) {
+	backoff := 10 * time.Second
+	if wait := (RetryConfig{}).jittered(backoff); wait != backoff {
+		t.Errorf("expected no jitter by default, got a wait of %s", wait)
+	}
+
+	config := RetryConfig{Jitter: 0.5}
+	for i := 0; i < 100; i++ {
+		if wait := config.jittered(backoff); wait < backoff/2 || wait > backoff {
+			t.Fatalf("expected a wait between %s and %s, got %s", backoff/2, backoff, wait)
+		}
+	}
+}
+
 func testRetryTransportHandler_noRetries(t *testing.T, code int) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(code)
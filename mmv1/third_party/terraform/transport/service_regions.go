@@ -0,0 +1,23 @@
+package transport
+
+import (
+	"fmt"
+)
+
+// ParseServiceDefaultRegions parses the service_default_regions provider
+// configuration, i.e. regions by service name, e.g. "compute". Regions may be
+// given as names or self links.
+func ParseServiceDefaultRegions(regions map[string]string) (map[string]string, error) {
+	result := make(map[string]string, len(regions))
+	for service, region := range regions {
+		if _, ok := ServiceBasePathKeys[service]; !ok {
+			return nil, fmt.Errorf("unknown service %q in service_default_regions", service)
+		}
+		region = GetRegionFromRegionSelfLink(region)
+		if region == "" {
+			return nil, fmt.Errorf("empty service_default_regions value for service %q", service)
+		}
+		result[service] = region
+	}
+	return result, nil
+}
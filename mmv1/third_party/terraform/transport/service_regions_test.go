@@ -0,0 +1,43 @@
+package transport_test
+
+import (
+	"strings"
+	"testing"
+
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+)
+
+func TestParseServiceDefaultRegions(t *testing.T) {
+	regions, err := transport_tpg.ParseServiceDefaultRegions(map[string]string{
+		"compute":      "europe-west1",
+		"cloud_run_v2": "https://www.googleapis.com/compute/v1/projects/my-project/regions/us-central1",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(regions) != 2 || regions["compute"] != "europe-west1" || regions["cloud_run_v2"] != "us-central1" {
+		t.Fatalf("unexpected regions %v", regions)
+	}
+
+	if _, err := transport_tpg.ParseServiceDefaultRegions(map[string]string{"unknown": "us-central1"}); err == nil || !strings.Contains(err.Error(), `unknown service "unknown"`) {
+		t.Fatalf("expected an unknown service error, got %v", err)
+	}
+
+	if _, err := transport_tpg.ParseServiceDefaultRegions(map[string]string{"compute": ""}); err == nil || !strings.Contains(err.Error(), "empty service_default_regions value") {
+		t.Fatalf("expected an empty region error, got %v", err)
+	}
+}
+
+func TestConfigServiceRegion(t *testing.T) {
+	config := &transport_tpg.Config{
+		Region:                "us-central1",
+		ServiceDefaultRegions: map[string]string{"compute": "europe-west1"},
+	}
+
+	if got := config.ServiceRegion("compute"); got != "europe-west1" {
+		t.Errorf("expected the region europe-west1 for compute, got %s", got)
+	}
+	if got := config.ServiceRegion("sql"); got != "us-central1" {
+		t.Errorf("expected the provider region for sql, got %s", got)
+	}
+}
@@ -0,0 +1,110 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// SharedCredentialsKey identifies the credentials of a provider configuration.
+// The SDK and the framework provider are configured with the same provider
+// block when muxed, so they share the token source and the identity logging
+// of their credentials through it rather than fetching tokens and userinfo
+// twice, see SharedTokenSource and LogIdentitiesOnce.
+type SharedCredentialsKey struct {
+	Credentials                        string
+	AccessToken                        string
+	CredentialsCommand                 string
+	ImpersonateServiceAccount          string
+	ImpersonateServiceAccountDelegates string
+	ImpersonateServiceAccountLifetime  time.Duration
+	Scopes                             string
+	UniverseDomain                     string
+}
+
+// NewSharedCredentialsKey returns the key of the given credentials settings of
+// a provider configuration.
+func NewSharedCredentialsKey(credentials, accessToken string, credentialsCommand *CredentialsCommand, impersonateServiceAccount string, delegates []string, lifetime time.Duration, scopes []string, universeDomain string) SharedCredentialsKey {
+	key := SharedCredentialsKey{
+		Credentials:                        credentials,
+		AccessToken:                        accessToken,
+		ImpersonateServiceAccount:          impersonateServiceAccount,
+		ImpersonateServiceAccountDelegates: strings.Join(delegates, ","),
+		ImpersonateServiceAccountLifetime:  lifetime,
+		Scopes:                             strings.Join(scopes, ","),
+		UniverseDomain:                     universeDomain,
+	}
+	if credentialsCommand != nil {
+		// Maps are printed sorted by key.
+		key.CredentialsCommand = fmt.Sprintf("%q %q %v", credentialsCommand.Command, credentialsCommand.Args, credentialsCommand.Env)
+	}
+	return key
+}
+
+// sharedCredentials are the token source and the identity logging of the
+// providers configured with the same credentials.
+type sharedCredentials struct {
+	tokenSource *CachedTokenSource
+	// tokenSourceCtx is the context the token source fetches tokens with.
+	tokenSourceCtx context.Context
+	identitiesOnce sync.Once
+	identitiesErr  error
+}
+
+var (
+	sharedCredentialsMu    sync.Mutex
+	sharedCredentialsByKey = make(map[SharedCredentialsKey]*sharedCredentials)
+)
+
+func getSharedCredentials(key SharedCredentialsKey) *sharedCredentials {
+	sharedCredentialsMu.Lock()
+	defer sharedCredentialsMu.Unlock()
+
+	shared, ok := sharedCredentialsByKey[key]
+	if !ok {
+		shared = &sharedCredentials{}
+		sharedCredentialsByKey[key] = shared
+	}
+	return shared
+}
+
+// SharedTokenSource returns the cached token source of the given credentials,
+// see NewCachedTokenSource, created with newTokenSource by the first provider
+// configured with them, so that the providers of the process share their
+// tokens. newTokenSource fetches tokens with the given context, so the token
+// source is created again once it's done. Token sources failing to be created
+// aren't shared.
+func SharedTokenSource(ctx context.Context, key SharedCredentialsKey, newTokenSource func() (oauth2.TokenSource, error)) (oauth2.TokenSource, error) {
+	shared := getSharedCredentials(key)
+
+	sharedCredentialsMu.Lock()
+	defer sharedCredentialsMu.Unlock()
+
+	if shared.tokenSource != nil && shared.tokenSourceCtx.Err() == nil {
+		return shared.tokenSource, nil
+	}
+	tokenSource, err := newTokenSource()
+	if err != nil {
+		return nil, err
+	}
+	// Tokens are shared by all requests, refresh them once and ahead of time.
+	shared.tokenSource = NewCachedTokenSource(tokenSource, DefaultTokenRefreshAhead)
+	shared.tokenSourceCtx = ctx
+	return shared.tokenSource, nil
+}
+
+// LogIdentitiesOnce runs logIdentities for the first provider configured with
+// the given credentials only, and returns its error to every provider
+// configured with them, as the identities are looked up with requests, see
+// IdentityLoggingEnabled.
+func LogIdentitiesOnce(key SharedCredentialsKey, logIdentities func() error) error {
+	shared := getSharedCredentials(key)
+	shared.identitiesOnce.Do(func() {
+		shared.identitiesErr = logIdentities()
+	})
+	return shared.identitiesErr
+}
@@ -0,0 +1,68 @@
+package transport_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"golang.org/x/oauth2"
+
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+)
+
+func TestSharedTokenSource(t *testing.T) {
+	key := transport_tpg.NewSharedCredentialsKey("", "test-shared-token-source", nil, "", nil, 0, transport_tpg.DefaultClientScopes, "")
+
+	if _, err := transport_tpg.SharedTokenSource(context.Background(), key, func() (oauth2.TokenSource, error) {
+		return nil, errors.New("no credentials")
+	}); err == nil {
+		t.Fatalf("expected the error creating the token source")
+	}
+
+	created := 0
+	newTokenSource := func() (oauth2.TokenSource, error) {
+		created++
+		return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"}), nil
+	}
+	sdk, err := transport_tpg.SharedTokenSource(context.Background(), key, newTokenSource)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	framework, err := transport_tpg.SharedTokenSource(context.Background(), key, newTokenSource)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if created != 1 || sdk != framework {
+		t.Errorf("expected a single token source shared by the providers, created %d", created)
+	}
+
+	other := transport_tpg.NewSharedCredentialsKey("", "test-shared-token-source", nil, "sa@project.iam.gserviceaccount.com", nil, 0, transport_tpg.DefaultClientScopes, "")
+	if impersonated, _ := transport_tpg.SharedTokenSource(context.Background(), other, newTokenSource); impersonated == sdk {
+		t.Errorf("expected another token source for other credentials")
+	}
+
+	stopped := transport_tpg.NewSharedCredentialsKey("", "test-shared-token-source-stopped", nil, "", nil, 0, transport_tpg.DefaultClientScopes, "")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelled, _ := transport_tpg.SharedTokenSource(ctx, stopped, newTokenSource)
+	cancel()
+	if recreated, _ := transport_tpg.SharedTokenSource(context.Background(), stopped, newTokenSource); recreated == cancelled {
+		t.Errorf("expected the token source to be created again once its context is done")
+	}
+}
+
+func TestLogIdentitiesOnce(t *testing.T) {
+	key := transport_tpg.NewSharedCredentialsKey("", "test-log-identities-once", nil, "", nil, 0, transport_tpg.DefaultClientScopes, "")
+
+	logged := 0
+	for i := 0; i < 2; i++ {
+		if err := transport_tpg.LogIdentitiesOnce(key, func() error {
+			logged++
+			return nil
+		}); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if logged != 1 {
+		t.Errorf("expected the identities to be logged once, got %d", logged)
+	}
+}
@@ -0,0 +1,122 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ServiceTimeoutsByBasePath resolves the service_timeouts provider
+// configuration, i.e. durations by service name, to timeouts by the base path
+// of the service. The base paths are given by service name, e.g. "compute".
+func ServiceTimeoutsByBasePath(timeouts map[string]string, basePaths map[string]string) (map[string]time.Duration, error) {
+	result := make(map[string]time.Duration, len(timeouts))
+	for service, v := range timeouts {
+		basePath, ok := basePaths[service]
+		if !ok {
+			return nil, fmt.Errorf("unknown service %q in service_timeouts", service)
+		}
+		timeout, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse duration from service_timeouts value %q for service %q", v, service)
+		}
+		if basePath != "" {
+			result[basePath] = timeout
+		}
+	}
+	return result, nil
+}
+
+// serviceTimeoutTransport applies a timeout per HTTP request that depends on
+// the service the request is sent to, in place of the timeout of the client.
+type serviceTimeoutTransport struct {
+	baseTransit    http.RoundTripper
+	basePaths      []string
+	timeouts       map[string]time.Duration
+	defaultTimeout time.Duration
+}
+
+// NewTransportWithServiceTimeouts returns a transport applying the given
+// timeouts by base path to requests, and the default timeout to requests
+// to other services. The timeout of the client using it should be unset.
+func NewTransportWithServiceTimeouts(baseTransit http.RoundTripper, timeouts map[string]time.Duration, defaultTimeout time.Duration) serviceTimeoutTransport {
+	if baseTransit == nil {
+		baseTransit = http.DefaultTransport
+	}
+
+	basePaths := make([]string, 0, len(timeouts))
+	for basePath := range timeouts {
+		basePaths = append(basePaths, basePath)
+	}
+
+	return serviceTimeoutTransport{
+		baseTransit:    baseTransit,
+		basePaths:      sortBasePaths(basePaths),
+		timeouts:       timeouts,
+		defaultTimeout: defaultTimeout,
+	}
+}
+
+// Timeout returns the timeout of requests to the given URL, see matchBasePath.
+func (t serviceTimeoutTransport) Timeout(u *url.URL) time.Duration {
+	if basePath, ok := matchBasePath(u, t.basePaths); ok {
+		return t.timeouts[basePath]
+	}
+	return t.defaultTimeout
+}
+
+func (t serviceTimeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(req.Context(), t.Timeout(req.URL))
+	res, err := t.baseTransit.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	// The timeout also covers reading the body, like the one of the client.
+	res.Body = cancelOnCloseBody{ReadCloser: res.Body, cancel: cancel}
+	return res, nil
+}
+
+// sortBasePaths sorts base paths from the most specific to the least specific
+// one, i.e. longer base paths, e.g. of versioned endpoints, first.
+func sortBasePaths(basePaths []string) []string {
+	sort.Slice(basePaths, func(i, j int) bool {
+		return len(basePaths[i]) > len(basePaths[j])
+	})
+	return basePaths
+}
+
+// matchBasePath returns the base path, out of base paths sorted with
+// sortBasePaths, that the given URL belongs to. URLs that don't match a base
+// path, e.g. the ones of API client libraries using another API version, match
+// a base path on the same host.
+func matchBasePath(u *url.URL, basePaths []string) (string, bool) {
+	rawurl := u.String()
+	for _, basePath := range basePaths {
+		if strings.HasPrefix(rawurl, basePath) {
+			return basePath, true
+		}
+	}
+	for _, basePath := range basePaths {
+		if bu, err := url.Parse(basePath); err == nil && bu.Host == u.Host {
+			return basePath, true
+		}
+	}
+	return "", false
+}
+
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b cancelOnCloseBody) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
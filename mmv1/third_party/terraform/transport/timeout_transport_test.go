@@ -0,0 +1,85 @@
+package transport_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+)
+
+func TestServiceTimeoutsByBasePath(t *testing.T) {
+	basePaths := map[string]string{
+		"compute": "https://compute.googleapis.com/compute/v1/",
+		"storage": "https://storage.googleapis.com/storage/v1/",
+	}
+
+	timeouts, err := transport_tpg.ServiceTimeoutsByBasePath(map[string]string{"compute": "4m"}, basePaths)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(timeouts) != 1 || timeouts["https://compute.googleapis.com/compute/v1/"] != 4*time.Minute {
+		t.Fatalf("expected a timeout of 4m for compute, got %v", timeouts)
+	}
+
+	if _, err := transport_tpg.ServiceTimeoutsByBasePath(map[string]string{"unknown": "4m"}, basePaths); err == nil || !strings.Contains(err.Error(), `unknown service "unknown"`) {
+		t.Fatalf("expected an unknown service error, got %v", err)
+	}
+
+	if _, err := transport_tpg.ServiceTimeoutsByBasePath(map[string]string{"compute": "forever"}, basePaths); err == nil || !strings.Contains(err.Error(), "unable to parse duration") {
+		t.Fatalf("expected a duration error, got %v", err)
+	}
+}
+
+func TestServiceTimeoutTransport_Timeout(t *testing.T) {
+	transport := transport_tpg.NewTransportWithServiceTimeouts(nil, map[string]time.Duration{
+		"https://compute.googleapis.com/compute/v1/":    4 * time.Minute,
+		"https://compute.googleapis.com/compute/beta/":  5 * time.Minute,
+		"https://storage.googleapis.com/storage/v1/b/x": 6 * time.Minute,
+	}, time.Minute)
+
+	cases := map[string]time.Duration{
+		"https://compute.googleapis.com/compute/v1/projects/p/global/networks": 4 * time.Minute,
+		"https://compute.googleapis.com/compute/beta/projects/p":               5 * time.Minute,
+		"https://storage.googleapis.com/storage/v1/b/x/o":                      6 * time.Minute,
+		"https://pubsub.googleapis.com/v1/projects/p/topics":                   time.Minute,
+	}
+	for rawurl, expected := range cases {
+		u, err := url.Parse(rawurl)
+		if err != nil {
+			t.Fatalf("cannot parse %q: %s", rawurl, err)
+		}
+		if got := transport.Timeout(u); got != expected {
+			t.Errorf("expected timeout %s for %q, got %s", expected, rawurl, got)
+		}
+	}
+}
+
+func TestServiceTimeoutTransport_RoundTrip(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow/" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client := &http.Client{
+		Transport: transport_tpg.NewTransportWithServiceTimeouts(nil, map[string]time.Duration{
+			server.URL + "/slow/": 10 * time.Millisecond,
+		}, time.Minute),
+	}
+
+	res, err := client.Get(server.URL + "/fast/")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	res.Body.Close()
+
+	if _, err := client.Get(server.URL + "/slow/"); err == nil {
+		t.Fatalf("expected the request to time out")
+	}
+}
@@ -0,0 +1,54 @@
+package transport_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-provider-google/google/mockgcp"
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+)
+
+func TestSendRequest_mockgcp(t *testing.T) {
+	server := mockgcp.NewServer()
+	defer server.Close()
+	config := transport_tpg.NewTestConfig(server.URL, server.Client())
+	config.ComputeBasePath = server.BasePath("compute", "v1")
+	url := config.ComputeBasePath + "projects/my-project/global/networks"
+
+	op, err := transport_tpg.SendRequest(transport_tpg.SendRequestOptions{
+		Config:    config,
+		Method:    "POST",
+		RawURL:    url,
+		UserAgent: "test",
+		Body:      map[string]interface{}{"name": "my-network"},
+	})
+	if err != nil {
+		t.Fatalf("creating the network failed: %s", err)
+	}
+	if op["status"] != "DONE" {
+		t.Errorf("want a done operation, got %v", op)
+	}
+
+	network, err := transport_tpg.SendRequest(transport_tpg.SendRequestOptions{
+		Config:    config,
+		Method:    "GET",
+		RawURL:    url + "/my-network",
+		UserAgent: "test",
+	})
+	if err != nil {
+		t.Fatalf("reading the network failed: %s", err)
+	}
+	if network["selfLink"] != url+"/my-network" {
+		t.Errorf("want the created network, got %v", network)
+	}
+
+	_, err = transport_tpg.SendRequest(transport_tpg.SendRequestOptions{
+		Config:    config,
+		Method:    "GET",
+		RawURL:    url + "/other-network",
+		UserAgent: "test",
+	})
+	if !transport_tpg.IsGoogleApiErrorWithCode(err, http.StatusNotFound) {
+		t.Errorf("want a %d error reading a missing network, got %v", http.StatusNotFound, err)
+	}
+}
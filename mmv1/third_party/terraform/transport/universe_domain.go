@@ -0,0 +1,64 @@
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-provider-google/google/verify"
+)
+
+// DefaultUniverseDomain is the domain of the default Google Cloud universe.
+const DefaultUniverseDomain = "googleapis.com"
+
+// GetUniverseDomain returns the universe domain the provider operates in,
+// given the universe_domain provider configuration and the credentials.
+// Credentials that set a universe domain determine it, and the configured
+// universe domain has to match them. Credentials that don't set one are
+// assumed to be in the default universe. Access tokens and application
+// default credentials carry no universe domain, so the configured one is used.
+func GetUniverseDomain(configured, credentials string) (string, error) {
+	if credentials == "" {
+		return configured, nil
+	}
+
+	contents, _, err := verify.PathOrContents(credentials)
+	if err != nil {
+		return "", fmt.Errorf("error loading service account credentials: %s", err)
+	}
+	var content map[string]any
+	if err := json.Unmarshal([]byte(contents), &content); err != nil {
+		return "", err
+	}
+	universeDomain, _ := content["universe_domain"].(string)
+
+	if configured == "" || configured == DefaultUniverseDomain {
+		return universeDomain, nil
+	}
+	if universeDomain == "" {
+		return "", fmt.Errorf("Universe domain mismatch: '%s' supplied directly to Terraform with no matching universe domain in credentials. Credentials with no 'universe_domain' set are assumed to be in the default universe.", configured)
+	}
+	if configured != universeDomain {
+		if _, err := os.Stat(credentials); err == nil {
+			return "", fmt.Errorf("Universe domain mismatch: '%s' does not match the universe domain '%s' already set in the credential file '%s'. The 'universe_domain' provider configuration can not be used to override the universe domain that is defined in the active credential.  Set the 'universe_domain' provider configuration when universe domain information is not already available in the credential, e.g. when authenticating with a JWT token.", configured, universeDomain, credentials)
+		}
+		return "", fmt.Errorf("Universe domain mismatch: '%s' does not match the universe domain '%s' supplied directly to Terraform. The 'universe_domain' provider configuration can not be used to override the universe domain that is defined in the active credential.  Set the 'universe_domain' provider configuration when universe domain information is not already available in the credential, e.g. when authenticating with a JWT token.", configured, universeDomain)
+	}
+	return universeDomain, nil
+}
+
+// SetUniverseDomainBasePaths rewrites the given base paths, see
+// NewDefaultBasePaths, to the given universe domain. Base paths already in the
+// universe are left as is.
+func SetUniverseDomainBasePaths(basePaths map[string]string, universeDomain string) {
+	if universeDomain == "" || universeDomain == DefaultUniverseDomain {
+		return
+	}
+	for key, basePath := range basePaths {
+		if strings.Contains(basePath, universeDomain) {
+			continue
+		}
+		basePaths[key] = strings.ReplaceAll(basePath, DefaultUniverseDomain, universeDomain)
+	}
+}
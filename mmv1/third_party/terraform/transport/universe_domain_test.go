@@ -0,0 +1,80 @@
+package transport_test
+
+import (
+	"strings"
+	"testing"
+
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+)
+
+func TestGetUniverseDomain(t *testing.T) {
+	cases := map[string]struct {
+		Configured     string
+		Credentials    string
+		ExpectedValue  string
+		ExpectedErrors string
+	}{
+		"configured universe domain is used without credentials": {
+			Configured:    "example.goog",
+			ExpectedValue: "example.goog",
+		},
+		"universe domain is read from credentials": {
+			Credentials:   `{"type": "service_account", "universe_domain": "example.goog"}`,
+			ExpectedValue: "example.goog",
+		},
+		"default universe domain doesn't override credentials": {
+			Configured:    "googleapis.com",
+			Credentials:   `{"type": "service_account", "universe_domain": "example.goog"}`,
+			ExpectedValue: "example.goog",
+		},
+		"configured universe domain matching credentials": {
+			Configured:    "example.goog",
+			Credentials:   `{"type": "service_account", "universe_domain": "example.goog"}`,
+			ExpectedValue: "example.goog",
+		},
+		"configured universe domain with credentials in the default universe": {
+			Configured:     "example.goog",
+			Credentials:    `{"type": "service_account"}`,
+			ExpectedErrors: "with no matching universe domain in credentials",
+		},
+		"configured universe domain not matching credentials": {
+			Configured:     "example.goog",
+			Credentials:    `{"type": "service_account", "universe_domain": "other.goog"}`,
+			ExpectedErrors: "does not match the universe domain 'other.goog'",
+		},
+		"credentials that aren't JSON": {
+			Credentials:    "{",
+			ExpectedErrors: "unexpected end of JSON input",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			v, err := transport_tpg.GetUniverseDomain(tc.Configured, tc.Credentials)
+			if tc.ExpectedErrors != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.ExpectedErrors) {
+					t.Fatalf("expected error containing %q, got %v", tc.ExpectedErrors, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if v != tc.ExpectedValue {
+				t.Fatalf("expected universe domain %q, got %q", tc.ExpectedValue, v)
+			}
+		})
+	}
+}
+
+func TestSetUniverseDomainBasePaths(t *testing.T) {
+	basePaths := transport_tpg.NewDefaultBasePaths()
+	transport_tpg.SetUniverseDomainBasePaths(basePaths, "example.com")
+
+	if bp := basePaths[transport_tpg.ComputeBasePathKey]; !strings.Contains(bp, "example.com") {
+		t.Errorf("expected the compute base path in the universe, got %s", bp)
+	}
+	if bp := transport_tpg.DefaultBasePaths[transport_tpg.ComputeBasePathKey]; strings.Contains(bp, "example.com") {
+		t.Errorf("expected the default base paths to be left as is, got %s", bp)
+	}
+}
@@ -0,0 +1,65 @@
+package transport
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// userProjectTransport removes the X-Goog-User-Project header set by
+// user_project_override from requests to the services it doesn't apply to,
+// since some APIs reject it.
+type userProjectTransport struct {
+	baseTransit http.RoundTripper
+	basePaths   []string
+	// exclude is set if basePaths are the ones of the services the header
+	// doesn't apply to, rather than the only ones it applies to.
+	exclude bool
+}
+
+// NewTransportWithUserProjectOverrideServices returns a transport applying the
+// user_project_override_services or user_project_override_excluded_services
+// provider configuration, i.e. keeping the X-Goog-User-Project header of
+// requests only to the given services, or to services other than the excluded
+// ones, see matchBasePath. The base paths are given by service name, e.g.
+// "compute". baseTransit is returned as is if neither is configured.
+func NewTransportWithUserProjectOverrideServices(baseTransit http.RoundTripper, services, excludedServices []string, basePaths map[string]string) (http.RoundTripper, error) {
+	if baseTransit == nil {
+		baseTransit = http.DefaultTransport
+	}
+
+	field, exclude := "user_project_override_services", false
+	if len(excludedServices) > 0 {
+		services, field, exclude = excludedServices, "user_project_override_excluded_services", true
+	}
+	if len(services) == 0 {
+		return baseTransit, nil
+	}
+
+	servicePaths := make([]string, 0, len(services))
+	for _, service := range services {
+		basePath, ok := basePaths[service]
+		if !ok {
+			return nil, fmt.Errorf("unknown service %q in %s", service, field)
+		}
+		if basePath != "" {
+			servicePaths = append(servicePaths, basePath)
+		}
+	}
+
+	return userProjectTransport{
+		baseTransit: baseTransit,
+		basePaths:   sortBasePaths(servicePaths),
+		exclude:     exclude,
+	}, nil
+}
+
+func (t userProjectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if _, ok := req.Header["X-Goog-User-Project"]; ok {
+		if _, matched := matchBasePath(req.URL, t.basePaths); matched == t.exclude {
+			// The request mustn't be modified, so remove the header from a copy.
+			req = req.Clone(req.Context())
+			req.Header.Del("X-Goog-User-Project")
+		}
+	}
+	return t.baseTransit.RoundTrip(req)
+}
@@ -0,0 +1,83 @@
+package transport_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+)
+
+func TestUserProjectTransport(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Header.Get("X-Goog-User-Project")))
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	otherServer := httptest.NewServer(handler)
+	defer otherServer.Close()
+
+	basePaths := map[string]string{
+		"compute": server.URL + "/v1/",
+		"storage": otherServer.URL + "/v1/",
+	}
+	cases := map[string]struct {
+		services         []string
+		excludedServices []string
+		expected         map[string]string
+	}{
+		"services": {
+			services: []string{"compute"},
+			expected: map[string]string{
+				server.URL + "/v1/":      "billing-project",
+				otherServer.URL + "/v1/": "",
+			},
+		},
+		"excluded services": {
+			excludedServices: []string{"compute"},
+			expected: map[string]string{
+				server.URL + "/v1/":      "",
+				otherServer.URL + "/v1/": "billing-project",
+			},
+		},
+	}
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			transport, err := transport_tpg.NewTransportWithUserProjectOverrideServices(nil, tc.services, tc.excludedServices, basePaths)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			client := &http.Client{Transport: transport}
+			for rawurl, expected := range tc.expected {
+				req, err := http.NewRequest("GET", rawurl, nil)
+				if err != nil {
+					t.Fatalf("cannot create request: %s", err)
+				}
+				req.Header.Set("X-Goog-User-Project", "billing-project")
+
+				res, err := client.Do(req)
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				body, err := io.ReadAll(res.Body)
+				res.Body.Close()
+				if err != nil {
+					t.Fatalf("cannot read response: %s", err)
+				}
+
+				if string(body) != expected {
+					t.Errorf("expected the header of requests to %s to be %q, got %q", rawurl, expected, body)
+				}
+				if req.Header.Get("X-Goog-User-Project") != "billing-project" {
+					t.Errorf("expected the request not to be modified")
+				}
+			}
+		})
+	}
+	_, err := transport_tpg.NewTransportWithUserProjectOverrideServices(nil, []string{"unknown"}, nil, basePaths)
+	if err == nil || !strings.Contains(err.Error(), `unknown service "unknown" in user_project_override_services`) {
+		t.Errorf("expected an unknown service error, got %v", err)
+	}
+}
@@ -5,19 +5,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/http/httptest"
 	"os"
 	"os/exec"
 	"path"
 	"path/filepath"
-	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/GoogleCloudPlatform/terraform-google-conversion/v5/caiasset"
 	"github.com/GoogleCloudPlatform/terraform-google-conversion/v5/tfplan2cai"
 	"github.com/google/go-cmp/cmp"
-	transport_tpg "github.com/hashicorp/terraform-provider-google-beta/google-beta/transport"
 	"go.uber.org/zap/zaptest"
 )
 
@@ -187,26 +184,3 @@ func generateTFVconvertedAsset(t *testing.T, testDir, testSlug string) {
 
 	fmt.Println("created file : " + dstFile)
 }
-
-// newTestConfig create a config using the http test server.
-func newTestConfig(server *httptest.Server) *transport_tpg.Config {
-	cfg := &transport_tpg.Config{}
-	cfg.Client = server.Client()
-	configureTestBasePaths(cfg, server.URL)
-	return cfg
-}
-
-func configureTestBasePaths(c *transport_tpg.Config, url string) {
-	if !strings.HasSuffix(url, "/") {
-		url = url + "/"
-	}
-	typ := reflect.ValueOf(c).Elem().Type()
-	val := reflect.ValueOf(c).Elem()
-
-	for i := 0; i < typ.NumField(); i++ {
-		name := typ.Field(i).Name
-		if strings.HasSuffix(name, "BasePath") {
-			val.Field(i).SetString(url)
-		}
-	}
-}
@@ -0,0 +1,146 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v5/cai2hcl"
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v5/caiasset"
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v5/tfplan2cai"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap/zaptest"
+)
+
+// roundTripProviders is prepended to the configurations planned by round-trip
+// tests. Examples can use either provider, both resolve to the beta provider.
+const roundTripProviders = `terraform {
+  required_providers {
+    google = {
+      source  = "hashicorp/google-beta"
+      version = "~> %[1]s"
+    }
+    google-beta = {
+      source  = "hashicorp/google-beta"
+      version = "~> %[1]s"
+    }
+  }
+}
+
+`
+
+// roundTripPlan is the part of `terraform show --json` compared by round-trip tests.
+type roundTripPlan struct {
+	PlannedValues struct {
+		RootModule struct {
+			Resources []struct {
+				Type   string                 `json:"type"`
+				Values map[string]interface{} `json:"values"`
+			} `json:"resources"`
+		} `json:"root_module"`
+	} `json:"planned_values"`
+}
+
+// assertRoundTrip converts the plan of config to CAI assets with tfplan2cai,
+// converts the assets back to HCL with cai2hcl and checks that the resources
+// of resourceType are planned with the same values from both configurations.
+// Values of the fields in ignore aren't compared, e.g. fields not in assets.
+func assertRoundTrip(t *testing.T, resourceType, config string, ignore []string) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode.")
+		return
+	}
+
+	dir := roundTripDir(t, config)
+	terraformWorkflow(t, dir, "roundtrip")
+	want := roundTripValues(t, dir, resourceType, ignore)
+
+	jsonPlan, err := os.ReadFile(filepath.Join(dir, "roundtrip.tfplan.json"))
+	if err != nil {
+		t.Fatalf("reading plan: %v", err)
+	}
+	assets, err := tfplan2cai.Convert(context.Background(), jsonPlan, &tfplan2cai.Options{
+		ErrorLogger:    zaptest.NewLogger(t),
+		Offline:        true,
+		DefaultProject: data.Provider["project"],
+		AncestryCache: map[string]string{
+			data.Provider["project"]: data.Ancestry,
+		},
+	})
+	if err != nil {
+		t.Fatalf("tfplan2cai.Convert(): %v", err)
+	}
+
+	assetPtrs := make([]*caiasset.Asset, len(assets))
+	for i := range assets {
+		assetPtrs[i] = &assets[i]
+	}
+	hcl, err := cai2hcl.Convert(assetPtrs, &cai2hcl.Options{
+		ErrorLogger: zaptest.NewLogger(t),
+	})
+	if err != nil {
+		t.Fatalf("cai2hcl.Convert(): %v", err)
+	}
+
+	convertedDir := roundTripDir(t, string(hcl))
+	terraformWorkflow(t, convertedDir, "roundtrip")
+	got := roundTripValues(t, convertedDir, resourceType, ignore)
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("%s converted config:\n%s\ndiff(-want, +got):\n%s", t.Name(), hcl, diff)
+	}
+}
+
+// roundTripDir writes config to a temporary directory, which is removed at
+// the end of the test.
+func roundTripDir(t *testing.T, config string) string {
+	dir, err := os.MkdirTemp(tmpDir, "terraform")
+	if err != nil {
+		t.Fatalf("creating temporary directory: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	config = fmt.Sprintf(roundTripProviders, data.Provider["version"]) + config
+	saveFile(t, dir, "main.tf", []byte(config))
+	return dir
+}
+
+// roundTripValues returns the planned values of the resources of resourceType,
+// without null and ignored fields, in a stable order.
+func roundTripValues(t *testing.T, dir, resourceType string, ignore []string) []map[string]interface{} {
+	payload, err := os.ReadFile(filepath.Join(dir, "roundtrip.tfplan.json"))
+	if err != nil {
+		t.Fatalf("reading plan: %v", err)
+	}
+	var plan roundTripPlan
+	if err := json.Unmarshal(payload, &plan); err != nil {
+		t.Fatalf("parsing plan: %v", err)
+	}
+
+	values := []map[string]interface{}{}
+	for _, r := range plan.PlannedValues.RootModule.Resources {
+		if r.Type != resourceType {
+			continue
+		}
+		for k, v := range r.Values {
+			if v == nil {
+				delete(r.Values, k)
+			}
+		}
+		for _, k := range ignore {
+			delete(r.Values, k)
+		}
+		values = append(values, r.Values)
+	}
+
+	sort.Slice(values, func(i, j int) bool {
+		a, _ := json.Marshal(values[i])
+		b, _ := json.Marshal(values[j])
+		return string(a) < string(b)
+	})
+	return values
+}
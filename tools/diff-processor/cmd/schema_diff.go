@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	newProvider "google/provider/new/google/provider"
+	oldProvider "google/provider/old/google/provider"
+
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/diff"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/maps"
+)
+
+const schemaDiffDesc = `Print a human-readable report of the schema differences between the old / new Terraform provider versions.`
+
+type schemaDiffOptions struct {
+	rootOptions       *rootOptions
+	computeSchemaDiff func() diff.SchemaDiff
+	stdout            io.Writer
+}
+
+func newSchemaDiffCmd(rootOptions *rootOptions) *cobra.Command {
+	o := &schemaDiffOptions{
+		rootOptions: rootOptions,
+		computeSchemaDiff: func() diff.SchemaDiff {
+			return diff.ComputeSchemaDiff(oldProvider.ResourceMap(), newProvider.ResourceMap())
+		},
+		stdout: os.Stdout,
+	}
+	cmd := &cobra.Command{
+		Use:   "schema-diff",
+		Short: schemaDiffDesc,
+		Long:  schemaDiffDesc,
+		Args:  cobra.NoArgs,
+		RunE: func(c *cobra.Command, args []string) error {
+			return o.run()
+		},
+	}
+	return cmd
+}
+
+func (o *schemaDiffOptions) run() error {
+	_, err := io.WriteString(o.stdout, schemaDiffReport(o.computeSchemaDiff()))
+	return err
+}
+
+// schemaDiffReport lists the resources that were added or removed, and the
+// fields of the remaining resources that were added, removed, or changed
+// type or ForceNew. Other changes, e.g. to descriptions or validation, are
+// not reported. Used to check that a generator refactor doesn't change the
+// generated schemas.
+func schemaDiffReport(schemaDiff diff.SchemaDiff) string {
+	var added, removed, changed []string
+	changes := make(map[string][]string)
+	for resource, resourceDiff := range schemaDiff {
+		if resourceDiff.ResourceConfig.Old == nil {
+			added = append(added, resource)
+			continue
+		}
+		if resourceDiff.ResourceConfig.New == nil {
+			removed = append(removed, resource)
+			continue
+		}
+		if fieldChanges := fieldChanges(resourceDiff.Fields); len(fieldChanges) > 0 {
+			changed = append(changed, resource)
+			changes[resource] = fieldChanges
+		}
+	}
+
+	if len(added) == 0 && len(removed) == 0 && len(changed) == 0 {
+		return "No schema differences found.\n"
+	}
+
+	var sb strings.Builder
+	writeSection := func(title string, resources []string) {
+		if len(resources) == 0 {
+			return
+		}
+		sort.Strings(resources)
+		fmt.Fprintf(&sb, "%s (%d):\n", title, len(resources))
+		for _, resource := range resources {
+			fmt.Fprintf(&sb, "  %s\n", resource)
+			for _, change := range changes[resource] {
+				fmt.Fprintf(&sb, "    %s\n", change)
+			}
+		}
+	}
+	writeSection("Resources added", added)
+	writeSection("Resources removed", removed)
+	writeSection("Resources changed", changed)
+	return sb.String()
+}
+
+func fieldChanges(fields map[string]diff.FieldDiff) []string {
+	var changes []string
+	keys := maps.Keys(fields)
+	sort.Strings(keys)
+	for _, key := range keys {
+		fieldDiff := fields[key]
+		switch {
+		case fieldDiff.Old == nil:
+			changes = append(changes, fmt.Sprintf("%s: field added (%s)", key, fieldDiff.New.Type))
+		case fieldDiff.New == nil:
+			changes = append(changes, fmt.Sprintf("%s: field removed", key))
+		default:
+			if fieldDiff.Old.Type != fieldDiff.New.Type {
+				changes = append(changes, fmt.Sprintf("%s: type changed from %s to %s", key, fieldDiff.Old.Type, fieldDiff.New.Type))
+			}
+			if fieldDiff.Old.ForceNew != fieldDiff.New.ForceNew {
+				changes = append(changes, fmt.Sprintf("%s: ForceNew changed from %t to %t", key, fieldDiff.Old.ForceNew, fieldDiff.New.ForceNew))
+			}
+		}
+	}
+	return changes
+}
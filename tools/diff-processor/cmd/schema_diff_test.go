@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/diff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestSchemaDiffCmdRun(t *testing.T) {
+	cases := map[string]struct {
+		oldResourceMap map[string]*schema.Resource
+		newResourceMap map[string]*schema.Resource
+		expectedOutput string
+	}{
+		"empty resource map": {
+			oldResourceMap: map[string]*schema.Resource{},
+			newResourceMap: map[string]*schema.Resource{},
+			expectedOutput: "No schema differences found.\n",
+		},
+		"resource isn't changed": {
+			oldResourceMap: map[string]*schema.Resource{
+				"google_x_resource": {
+					Schema: map[string]*schema.Schema{
+						"field_a": {Type: schema.TypeString, Optional: true},
+					},
+				},
+			},
+			newResourceMap: map[string]*schema.Resource{
+				"google_x_resource": {
+					Schema: map[string]*schema.Schema{
+						"field_a": {Type: schema.TypeString, Optional: true},
+					},
+				},
+			},
+			expectedOutput: "No schema differences found.\n",
+		},
+		"only unreported attributes are changed": {
+			oldResourceMap: map[string]*schema.Resource{
+				"google_x_resource": {
+					Schema: map[string]*schema.Schema{
+						"field_a": {Type: schema.TypeString, Description: "beep", Optional: true},
+					},
+				},
+			},
+			newResourceMap: map[string]*schema.Resource{
+				"google_x_resource": {
+					Schema: map[string]*schema.Schema{
+						"field_a": {Type: schema.TypeString, Description: "boop", Optional: true},
+					},
+				},
+			},
+			expectedOutput: "No schema differences found.\n",
+		},
+		"resources are added and removed": {
+			oldResourceMap: map[string]*schema.Resource{
+				"google_x_resource": {
+					Schema: map[string]*schema.Schema{
+						"field_a": {Type: schema.TypeString, Optional: true},
+					},
+				},
+			},
+			newResourceMap: map[string]*schema.Resource{
+				"google_y_resource": {
+					Schema: map[string]*schema.Schema{
+						"field_a": {Type: schema.TypeString, Optional: true},
+					},
+				},
+			},
+			expectedOutput: "Resources added (1):\n" +
+				"  google_y_resource\n" +
+				"Resources removed (1):\n" +
+				"  google_x_resource\n",
+		},
+		"fields are changed": {
+			oldResourceMap: map[string]*schema.Resource{
+				"google_x_resource": {
+					Schema: map[string]*schema.Schema{
+						"field_a": {Type: schema.TypeString, Optional: true},
+						"field_b": {Type: schema.TypeString, Optional: true},
+						"field_c": {Type: schema.TypeString, Optional: true},
+						"nested": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"field_d": {Type: schema.TypeString, Optional: true},
+								},
+							},
+						},
+					},
+				},
+			},
+			newResourceMap: map[string]*schema.Resource{
+				"google_x_resource": {
+					Schema: map[string]*schema.Schema{
+						"field_a": {Type: schema.TypeInt, Optional: true},
+						"field_b": {Type: schema.TypeString, Optional: true, ForceNew: true},
+						"field_e": {Type: schema.TypeBool, Optional: true},
+						"nested": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"field_d": {Type: schema.TypeString, Optional: true, ForceNew: true},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedOutput: "Resources changed (1):\n" +
+				"  google_x_resource\n" +
+				"    field_a: type changed from TypeString to TypeInt\n" +
+				"    field_b: ForceNew changed from false to true\n" +
+				"    field_c: field removed\n" +
+				"    field_e: field added (TypeBool)\n" +
+				"    nested.field_d: ForceNew changed from false to true\n",
+		},
+	}
+
+	for tn, tc := range cases {
+		tc := tc
+		t.Run(tn, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			o := schemaDiffOptions{
+				computeSchemaDiff: func() diff.SchemaDiff {
+					return diff.ComputeSchemaDiff(tc.oldResourceMap, tc.newResourceMap)
+				},
+				stdout: &buf,
+			}
+
+			if err := o.run(); err != nil {
+				t.Fatalf("Error running command: %s", err)
+			}
+
+			if got := buf.String(); got != tc.expectedOutput {
+				t.Errorf("Unexpected output. Want %q, got %q", tc.expectedOutput, got)
+			}
+		})
+	}
+}
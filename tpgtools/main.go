@@ -41,7 +41,7 @@ var sFilter = flag.String("service", "", "optional service name. If specified, o
 var rFilter = flag.String("resource", "", "optional resource name (from filename). If specified, only resources with this name are generated")
 var vFilter = flag.String("version", "", "optional version name. If specified, this version is preferred for resource generation when applicable")
 
-var mode = flag.String("mode", "", "mode for the generator. If unset, creates the provider. Options: 'serialization'")
+var mode = flag.String("mode", "", "mode for the generator. If unset, creates the provider. Options: 'serialization', 'cai2hcl'")
 
 var terraformResourceDirectory = "google-beta"
 var terraformProviderModule = "github.com/hashicorp/terraform-provider-google-beta"
@@ -82,6 +82,17 @@ func main() {
 		terraformProviderModule = "internal/terraform-next"
 	}
 
+	if mode != nil && *mode == "cai2hcl" {
+		for _, resource := range resourcesForVersion {
+			if skipResource(resource) || resource.SkipInProvider {
+				continue
+			}
+			glog.Infof("Generating cai2hcl converter from resource %s", resource.TitleCaseFullName())
+			generateCai2hclConverterFile(resource)
+		}
+		return
+	}
+
 	generatedResources := make([]*Resource, 0, len(resourcesForVersion))
 	for _, resource := range resourcesForVersion {
 		if skipResource(resource) {
@@ -411,6 +422,46 @@ func generateResourceFile(res *Resource) {
 	}
 }
 
+// generateCai2hclConverterFile generates a converter from CAI assets to HCL for
+// the terraform-google-conversion library. The output path is expected to be
+// the library's cai2hcl directory.
+func generateCai2hclConverterFile(res *Resource) {
+	tmplInput := ResourceInput{
+		Resource: *res,
+	}
+
+	tmpl, err := template.New("cai2hcl_converter.go.tmpl").Funcs(TemplateFunctions).ParseFiles(
+		"templates/cai2hcl_converter.go.tmpl",
+	)
+	if err != nil {
+		glog.Exit(err)
+	}
+
+	contents := bytes.Buffer{}
+	if err = tmpl.ExecuteTemplate(&contents, "cai2hcl_converter.go.tmpl", tmplInput); err != nil {
+		glog.Exit(err)
+	}
+
+	formatted, err := formatSource(&contents)
+	if err != nil {
+		glog.Error(fmt.Errorf("error formatting %v%v: %v - cai2hcl converter \n ", res.ProductName(), res.Name(), err))
+	}
+
+	if oPath == nil || *oPath == "" {
+		fmt.Printf("%v", string(formatted))
+	} else {
+		outname := fmt.Sprintf("%s_%s.go", res.ProductName(), res.Name())
+		servicePath := path.Join(*oPath, "services", string(res.Package()))
+		if err := os.MkdirAll(servicePath, os.ModePerm); err != nil {
+			glog.Error(fmt.Errorf("error creating cai2hcl service directory %v: %v", servicePath, err))
+		}
+		err = ioutil.WriteFile(path.Join(servicePath, outname), formatted, 0644)
+		if err != nil {
+			glog.Exit(err)
+		}
+	}
+}
+
 func generateSweeperFile(res *Resource) {
 	if !res.HasSweeper {
 		return
@@ -56,6 +56,7 @@ const (
 	CustomTimeout                      = "CUSTOM_TIMEOUT"
 	StateUpgrade                       = "STATE_UPGRADE"
 	GenerateLongFormTests              = "GENERATE_LONG_FORM_TESTS"
+	CaiAssetType                       = "CAI_ASSET_TYPE"
 )
 
 // Field-level Overrides
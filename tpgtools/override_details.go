@@ -202,6 +202,11 @@ type TerraformProductNameDetails struct {
 	Product string
 }
 
+type CaiAssetTypeDetails struct {
+	// The Cloud Asset Inventory type of the resource, e.g. "gkemulticloud.googleapis.com/AwsCluster".
+	AssetType string
+}
+
 type ProductBasePathDetails struct {
 	// If set to true, generating the product base path should be skipped
 	// This is the case when mmv1 already generates base path support
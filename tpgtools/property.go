@@ -372,6 +372,13 @@ func (p Property) FlattenGetter() string {
 	return p.flattenGetterWithParent("obj")
 }
 
+// ConverterFlattenGetter returns a snippet of code that returns an interface{}
+// for a top-level field, given a DCL-type for the resource named `res`. It is
+// used by cai2hcl converters, which have no ResourceData to set.
+func (p Property) ConverterFlattenGetter() string {
+	return p.flattenGetterWithParent("res")
+}
+
 func (p Property) flattenGetterWithParent(parent string) string {
 	switch p.Type.String() {
 	case SchemaTypeBool:
@@ -389,7 +396,7 @@ func (p Property) flattenGetterWithParent(parent string) string {
 		return fmt.Sprintf("%s.%s", parent, p.PackageName)
 	case SchemaTypeList, SchemaTypeSet:
 		if p.Type.IsEnumArray() {
-			return fmt.Sprintf("flatten%s%sArray(obj.%s)", p.resource.PathType(), p.PackagePath(), p.PackageName)
+			return fmt.Sprintf("flatten%s%sArray(%s.%s)", p.resource.PathType(), p.PackagePath(), parent, p.PackageName)
 		}
 		if p.Type.IsComplexMap() {
 			return fmt.Sprintf("flatten%s%sMap(%s.%s)", p.resource.PathType(), p.PackagePath(), parent, p.PackageName)
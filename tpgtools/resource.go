@@ -163,6 +163,9 @@ type Resource struct {
 	// TerraformProductName is the Product name overriden from the DCL
 	TerraformProductName *SnakeCaseProductName
 
+	// caiAssetType is the Cloud Asset Inventory type overriden from the DCL
+	caiAssetType string
+
 	// The array of Samples associated with the resource
 	Samples []Sample
 
@@ -419,6 +422,33 @@ func (r Resource) HasAnnotations() bool {
 	return false
 }
 
+// CaiAssetType is the Cloud Asset Inventory type of the resource, e.g.
+// "eventarc.googleapis.com/Trigger". Unless overridden, it is derived from the
+// DCL service and resource names.
+func (r Resource) CaiAssetType() string {
+	if r.caiAssetType != "" {
+		return r.caiAssetType
+	}
+	return fmt.Sprintf("%s.googleapis.com/%s", r.Package().lowercase(), r.DCLTitle())
+}
+
+// Check if the resource or any of its nested objects has a three-state boolean field
+func (r Resource) HasEnumBool() bool {
+	for _, p := range r.Properties {
+		if p.EnumBool {
+			return true
+		}
+	}
+	for _, o := range r.Objects() {
+		for _, p := range o.Properties {
+			if p.EnumBool {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // ResourceInput is a Resource along with additional generation metadata.
 type ResourceInput struct {
 	Resource
@@ -700,6 +730,16 @@ func createResource(schema *openapi.Schema, info *openapi.Info, typeFetcher *Typ
 		res.TerraformProductName = &scpn
 	}
 
+	// Resource Override: CaiAssetType
+	caiAssetType := CaiAssetTypeDetails{}
+	caiAssetTypeOk, err := overrides.ResourceOverrideWithDetails(CaiAssetType, &caiAssetType, location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode cai asset type details: %v", err)
+	}
+	if caiAssetTypeOk {
+		res.caiAssetType = caiAssetType.AssetType
+	}
+
 	// Resource Override: StateUpgrade
 	stateUpgrade := StateUpgradeDetails{}
 	stateUpgradeOk, err := overrides.ResourceOverrideWithDetails(StateUpgrade, &stateUpgrade, location)